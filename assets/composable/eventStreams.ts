@@ -143,7 +143,7 @@ function useLogStream(url: Ref<string>, container?: Ref<Container>) {
       if (initial) {
         wasInitial = true;
         // sort the buffer the very first time because of multiple logs in parallel
-        buffer.value.sort((a, b) => a.date.getTime() - b.date.getTime());
+        buffer.value.sort((a, b) => a.displayDate.getTime() - b.displayDate.getTime());
 
         if (container || containers.value.length > 0) {
           const loadMoreItem = new LoadMoreLogEntry(new Date(), loadOlderLogs);
@@ -201,6 +201,21 @@ function useLogStream(url: Ref<string>, container?: Ref<Container>) {
       flushBuffer.flush();
     });
 
+    es.addEventListener("logs-dropped", (e) => {
+      const data = parseEventData<{ containerId: string; count: number; time: string }[]>(e);
+      const entries = data.map(
+        (d) =>
+          new ContainerEventLogEntry(
+            `Skipped ${d.count} lines over the rate limit`,
+            d.containerId,
+            new Date(d.time),
+            "logs-dropped",
+          ),
+      );
+      buffer.value = [...buffer.value, ...entries];
+      flushBuffer();
+    });
+
     es.addEventListener("logs-backfill", (e) => {
       const data = parseEventData<LogEvent[]>(e);
       const logs = data.map((e) => asLogEntry(e));
@@ -29,7 +29,9 @@ export function useLogLoader(
     for (const log of existingLogs) {
       const id = log.containerID;
       if (!id || !containerIDs.has(id)) continue;
-      if (!earliestByContainer.has(id)) {
+      // lines sorted by message time are not in driver order, which paging uses
+      const earliest = earliestByContainer.get(id);
+      if (!earliest || log.date < earliest.date) {
         earliestByContainer.set(id, log);
       }
       const count = (countByContainer.get(id) ?? 0) + 1;
@@ -60,7 +62,7 @@ export function useLogLoader(
       const allNewLogs = results
         .filter(({ signal }) => !signal.aborted)
         .flatMap(({ logs }) => logs)
-        .sort((a, b) => a.date.getTime() - b.date.getTime());
+        .sort((a, b) => a.displayDate.getTime() - b.displayDate.getTime());
 
       if (allNewLogs.length > 0) {
         messages.value = [loader, ...(await withAlerts(allNewLogs)), ...existingLogs];
@@ -90,7 +92,7 @@ export function useLogLoader(
       const allLogs = results
         .filter(({ signal }) => !signal.aborted)
         .flatMap(({ logs }) => logs)
-        .sort((a, b) => a.date.getTime() - b.date.getTime());
+        .sort((a, b) => a.displayDate.getTime() - b.displayDate.getTime());
 
       if (allLogs.length > 0) {
         const withAlertsApplied = await withAlerts(allLogs);
@@ -19,6 +19,12 @@ describe("useSearchFilter", () => {
     expect(isValidQuery.value).toBe(false);
   });
 
+  test("isValidQuery accepts structured queries", () => {
+    const { searchQueryFilter, isValidQuery } = useSearchFilter();
+    searchQueryFilter.value = "?status>=500";
+    expect(isValidQuery.value).toBe(true);
+  });
+
   test("toggleInverse flips the inverse flag", () => {
     const { inverseFilter, toggleInverse } = useSearchFilter();
     expect(inverseFilter.value).toBe(false);
@@ -21,6 +21,8 @@ function toggleInverse() {
 const isSearching = computed(() => showSearch.value && debouncedSearchFilter.value !== "");
 
 const isValidQuery = computed(() => {
+  // a leading ? marks a structured query, which the server validates
+  if (searchQueryFilter.value.startsWith("?")) return true;
   try {
     new RegExp(searchQueryFilter.value);
     return true;
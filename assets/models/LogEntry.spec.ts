@@ -136,3 +136,19 @@ describe("matchedEvent", () => {
     expect(rendered.matchedEvent).toEqual({ suppressed: true });
   });
 });
+
+describe("displayDate", () => {
+  test("is the driver date when the event has no message timestamp", () => {
+    const entry = asLogEntry(event({ ts: 1_700_000_000_000 }));
+    expect(entry.displayDate.getTime()).toBe(1_700_000_000_000);
+  });
+
+  test("is the message date while date stays the driver's", () => {
+    const entry = asLogEntry(event({ t: "complex", m: { msg: "hi" }, ts: 1_700_000_000_000, mts: 1_699_999_990_000 }));
+    expect(entry.date.getTime()).toBe(1_700_000_000_000);
+    expect(entry.displayDate.getTime()).toBe(1_699_999_990_000);
+
+    const rendered = ComplexLogEntry.fromLogEvent(entry as ComplexLogEntry, ref(new Map()));
+    expect(rendered.displayDate.getTime()).toBe(1_699_999_990_000);
+  });
+});
@@ -32,6 +32,9 @@ export interface LogEvent {
   readonly s: "stdout" | "stderr" | "unknown";
   readonly c: string;
   readonly rm: string;
+  readonly sm?: string;
+  readonly mts?: number;
+  readonly ctx?: boolean;
 }
 
 /**
@@ -61,9 +64,35 @@ export interface MatchedEvent {
  */
 const matchedEvents = new WeakMap<LogEntry<LogMessage>, ShallowRef<MatchedEvent | undefined>>();
 
+/**
+ * Entries sent only as context around a filter match. Kept out of the entry's
+ * fields for the same reason as matched events.
+ */
+const contextEntries = new WeakSet<LogEntry<LogMessage>>();
+
+/**
+ * The time the application wrote in the message, for containers that opt into
+ * message timestamps. Kept out of the entry's fields for the same reason as
+ * matched events.
+ */
+const messageDates = new WeakMap<LogEntry<LogMessage>, Date>();
+
 export abstract class LogEntry<T extends LogMessage> {
   protected readonly _message: T;
 
+  /** Whether the line did not match the filter and only surrounds a match. */
+  public get isContext(): boolean {
+    return contextEntries.has(this);
+  }
+
+  /**
+   * The time shown for the line and used to sort it: the message timestamp when
+   * there is one. `date` stays the log driver's time, which paging relies on.
+   */
+  public get displayDate(): Date {
+    return messageDates.get(this) ?? this.date;
+  }
+
   /** The event Cloud matched on this line, once the alert loader reports it. */
   public get matchedEvent(): MatchedEvent | undefined {
     return matchedEventRef(this).value;
@@ -140,6 +169,7 @@ export class ComplexLogEntry extends LogEntry<JSONObject> {
     public readonly std: Std,
     public readonly rawMessage: string,
     visibleKeys?: Ref<Map<string[], boolean>>,
+    public readonly summary?: string,
   ) {
     super(message, containerID, id, date, std, rawMessage, level);
     if (visibleKeys) {
@@ -187,6 +217,7 @@ export class ComplexLogEntry extends LogEntry<JSONObject> {
       event.std,
       event.rawMessage,
       visibleKeys,
+      event.summary,
     );
     // The viewer re-clones every complex entry on each render, so the clone is
     // what actually reaches the row component. Matched events live in a WeakMap
@@ -195,6 +226,13 @@ export class ComplexLogEntry extends LogEntry<JSONObject> {
     // away before it was drawn. Sharing the ref rather than copying the value
     // also keeps a badge that arrives after this clone was made.
     matchedEvents.set(clone, matchedEventRef(event));
+    if (event.isContext) {
+      contextEntries.add(clone);
+    }
+    const messageDate = messageDates.get(event);
+    if (messageDate) {
+      messageDates.set(clone, messageDate);
+    }
     return clone;
   }
 }
@@ -204,7 +242,7 @@ export class ContainerEventLogEntry extends LogEntry<string> {
     message: string,
     containerID: string,
     date: Date,
-    public readonly event: "container-stopped" | "container-started",
+    public readonly event: "container-stopped" | "container-started" | "logs-dropped",
   ) {
     super(message, containerID, date.getTime(), date, "stderr", "unknown");
   }
@@ -355,11 +393,32 @@ function matchedEventRef(entry: LogEntry<any>): ShallowRef<MatchedEvent | undefi
 }
 
 export function asLogEntry(event: LogEvent): LogEntry<LogMessage> {
+  const entry = newLogEntry(event);
+  if (event.ctx) {
+    contextEntries.add(entry);
+  }
+  if (event.mts) {
+    messageDates.set(entry, new Date(event.mts));
+  }
+  return entry;
+}
+
+function newLogEntry(event: LogEvent): LogEntry<LogMessage> {
   const std = event.s === "unknown" ? "stderr" : (event.s ?? "stderr");
 
   switch (event.t) {
     case "complex":
-      return new ComplexLogEntry(event.m as JSONObject, event.c, event.id, new Date(event.ts), event.l, std, event.rm);
+      return new ComplexLogEntry(
+        event.m as JSONObject,
+        event.c,
+        event.id,
+        new Date(event.ts),
+        event.l,
+        std,
+        event.rm,
+        undefined,
+        event.sm,
+      );
     case "group":
       return new GroupedLogEntry(
         (event.m as LogFragment[]).map((f) => f.m),
@@ -8,9 +8,12 @@ export interface NotificationRule {
   eventExpression?: string;
   cooldown?: number;
   sampleWindow?: number;
+  groupBy?: string;
+  digestWindow?: number;
   triggerCount: number;
   triggeredContainers: number;
   lastTriggeredAt: string | null;
+  firingCount: number;
   dispatcher: Dispatcher | null;
 }
 
@@ -23,6 +26,59 @@ export interface Dispatcher {
   headers?: Record<string, string>;
   prefix?: string;
   expiresAt?: string;
+  channel?: string;
+  topic?: string;
+  priority?: string;
+  user?: string;
+  dozzleUrl?: string;
+  smtpHost?: string;
+  smtpPort?: number;
+  smtpSecurity?: "starttls" | "tls" | "none";
+  smtpUsername?: string;
+  tokenSet?: boolean;
+  smtpPasswordSet?: boolean;
+  from?: string;
+  to?: string[];
+  subject?: string;
+}
+
+export interface NotificationDelivery {
+  notificationId: string;
+  time: string;
+  type: "log" | "metric" | "event";
+  status?: "firing" | "resolved";
+  subscriptionId: number;
+  subscriptionName: string;
+  dispatcherId: number;
+  dispatcherName: string;
+  dispatcherType: string;
+  containerId: string;
+  containerName: string;
+  hostId: string;
+  hostName: string;
+  summary: string;
+  success: boolean;
+  statusCode?: number;
+  latencyMs: number;
+  error?: string;
+}
+
+export const NATIVE_DISPATCHER_TYPES = ["slack", "discord", "teams", "telegram", "ntfy", "gotify", "pushover"] as const;
+
+export type NativeDispatcherType = (typeof NATIVE_DISPATCHER_TYPES)[number];
+
+export const NATIVE_DISPATCHER_LABELS: Record<NativeDispatcherType, string> = {
+  slack: "Slack",
+  discord: "Discord",
+  teams: "Microsoft Teams",
+  telegram: "Telegram",
+  ntfy: "ntfy",
+  gotify: "Gotify",
+  pushover: "Pushover",
+};
+
+export function isNativeDispatcherType(type: string): type is NativeDispatcherType {
+  return (NATIVE_DISPATCHER_TYPES as readonly string[]).includes(type);
 }
 
 export interface NotificationRuleInput {
@@ -35,6 +91,8 @@ export interface NotificationRuleInput {
   eventExpression?: string;
   cooldown?: number;
   sampleWindow?: number;
+  groupBy?: string;
+  digestWindow?: number;
 }
 
 export interface PreviewResult {
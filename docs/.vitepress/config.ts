@@ -95,6 +95,7 @@ export default defineConfig({
           { text: "Reverse Proxy & Base Path", link: "/guide/changing-base" },
           { text: "Container Names", link: "/guide/container-names" },
           { text: "Container Groups", link: "/guide/container-groups" },
+          { text: "Container Timeline", link: "/guide/container-timeline" },
           { text: "Data Analytics", link: "/guide/analytics" },
           { text: "Default Profile", link: "/guide/default-profile" },
           { text: "Display Name", link: "/guide/hostname" },
@@ -102,7 +103,16 @@ export default defineConfig({
           { text: "Healthcheck", link: "/guide/healthcheck" },
           { text: "Remote Hosts", link: "/guide/remote-hosts" },
           { text: "Logging Files on Disk", link: "/guide/log-files-on-disk" },
+          { text: "Log Archive", link: "/guide/log-archive" },
+          { text: "Log Parsing", link: "/guide/log-parsing" },
+          { text: "Redacting Secrets", link: "/guide/redaction" },
+          { text: "Search Queries", link: "/guide/search-queries" },
+          { text: "Request Correlation", link: "/guide/correlation" },
+          { text: "Saved Views", link: "/guide/saved-views" },
+          { text: "Share Links", link: "/guide/share-links" },
+          { text: "Log Diff", link: "/guide/log-diff" },
           { text: "SQL Engine", link: "/guide/sql-engine" },
+          { text: "Stats History", link: "/guide/stats-history" },
         ],
       },
       {
@@ -207,14 +207,16 @@ func sendLogs(stream pb.AgentService_StreamLogsClient, events chan<- *container.
 		}
 
 		events <- &container.LogEvent{
-			Id:          resp.Event.Id,
-			ContainerID: resp.Event.ContainerId,
-			Message:     message,
-			Type:        logType,
-			Timestamp:   resp.Event.Timestamp.AsTime().Unix(),
-			Level:       resp.Event.Level,
-			Stream:      resp.Event.Stream,
-			RawMessage:  resp.Event.RawMessage,
+			Id:               resp.Event.Id,
+			ContainerID:      resp.Event.ContainerId,
+			Message:          message,
+			Type:             logType,
+			Timestamp:        resp.Event.Timestamp.AsTime().Unix(),
+			Level:            resp.Event.Level,
+			Stream:           resp.Event.Stream,
+			RawMessage:       resp.Event.RawMessage,
+			Summary:          resp.Event.Summary,
+			MessageTimestamp: resp.Event.MessageTimestamp,
 		}
 	}
 }
@@ -607,18 +609,34 @@ func (c *Client) UpdateNotificationConfig(ctx context.Context, subscriptions []t
 			Cooldown:            int32(sub.Cooldown),
 			SampleWindow:        int32(sub.SampleWindow),
 			EventExpression:     sub.EventExpression,
+			GroupBy:             sub.GroupBy,
+			DigestWindow:        int32(sub.DigestWindow),
 		}
 	}
 
 	pbDispatchers := make([]*pb.NotificationDispatcher, len(dispatchers))
 	for i, d := range dispatchers {
 		pbDispatchers[i] = &pb.NotificationDispatcher{
-			Id:       int32(d.ID),
-			Name:     d.Name,
-			Type:     d.Type,
-			Url:      d.URL,
-			Template: d.Template,
-			Headers:  d.Headers,
+			Id:           int32(d.ID),
+			Name:         d.Name,
+			Type:         d.Type,
+			Url:          d.URL,
+			Template:     d.Template,
+			Headers:      d.Headers,
+			Channel:      d.Channel,
+			Topic:        d.Topic,
+			Priority:     d.Priority,
+			Token:        d.Token,
+			User:         d.User,
+			DozzleUrl:    d.DozzleURL,
+			SmtpHost:     d.SMTPHost,
+			SmtpPort:     int32(d.SMTPPort),
+			SmtpSecurity: d.SMTPSecurity,
+			SmtpUsername: d.SMTPUsername,
+			SmtpPassword: d.SMTPPassword,
+			From:         d.From,
+			To:           d.To,
+			Subject:      d.Subject,
 		}
 	}
 
@@ -665,10 +683,57 @@ func (c *Client) GetNotificationStats(ctx context.Context) ([]types.Subscription
 			LastTriggeredAt:       lastTriggered,
 			TriggeredContainerIDs: s.TriggeredContainerIds,
 		}
+		for _, alert := range s.FiringAlerts {
+			stats[i].FiringAlerts = append(stats[i].FiringAlerts, types.FiringAlert{
+				SubscriptionID: int(s.SubscriptionId),
+				Type:           types.NotificationType(alert.Type),
+				Container: types.NotificationContainer{
+					ID:       alert.ContainerId,
+					Name:     alert.ContainerName,
+					Image:    alert.Image,
+					HostID:   alert.HostId,
+					HostName: alert.HostName,
+				},
+				Detail: alert.Detail,
+				Since:  alert.Since.AsTime(),
+			})
+		}
 	}
 	return stats, nil
 }
 
+func (c *Client) GetNotificationDeliveries(ctx context.Context) ([]types.NotificationDelivery, error) {
+	resp, err := c.client.GetNotificationDeliveries(ctx, &pb.GetNotificationDeliveriesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]types.NotificationDelivery, len(resp.Deliveries))
+	for i, d := range resp.Deliveries {
+		deliveries[i] = types.NotificationDelivery{
+			NotificationID:   d.NotificationId,
+			Time:             d.Time.AsTime(),
+			Type:             types.NotificationType(d.Type),
+			Status:           types.NotificationStatus(d.Status),
+			SubscriptionID:   int(d.SubscriptionId),
+			SubscriptionName: d.SubscriptionName,
+			DispatcherID:     int(d.DispatcherId),
+			DispatcherName:   d.DispatcherName,
+			DispatcherType:   d.DispatcherType,
+			ContainerID:      d.ContainerId,
+			ContainerName:    d.ContainerName,
+			HostID:           d.HostId,
+			HostName:         d.HostName,
+			Summary:          d.Summary,
+			Success:          d.Success,
+			StatusCode:       int(d.StatusCode),
+			LatencyMs:        d.LatencyMs,
+			Error:            d.Error,
+		}
+	}
+	return deliveries, nil
+}
+
 func jsonBytesToOrderedMap(b []byte) *orderedmap.OrderedMap[string, any] {
 	var data *orderedmap.OrderedMap[string, any]
 	reader := bytes.NewReader(b)
@@ -43,6 +43,10 @@ func (m *mockNotificationHandler) GetNotificationStats() []types.SubscriptionSta
 	return nil
 }
 
+func (m *mockNotificationHandler) Deliveries() []types.NotificationDelivery {
+	return nil
+}
+
 type MockedClientService struct {
 	mock.Mock
 }
@@ -1638,6 +1638,86 @@ func (x *GetNotificationStatsResponse) GetStats() []*NotificationSubscriptionSta
 	return nil
 }
 
+type GetNotificationDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNotificationDeliveriesRequest) Reset() {
+	*x = GetNotificationDeliveriesRequest{}
+	mi := &file_rpc_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNotificationDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNotificationDeliveriesRequest) ProtoMessage() {}
+
+func (x *GetNotificationDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNotificationDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*GetNotificationDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{33}
+}
+
+type GetNotificationDeliveriesResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Deliveries    []*NotificationDelivery `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNotificationDeliveriesResponse) Reset() {
+	*x = GetNotificationDeliveriesResponse{}
+	mi := &file_rpc_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNotificationDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNotificationDeliveriesResponse) ProtoMessage() {}
+
+func (x *GetNotificationDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNotificationDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*GetNotificationDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetNotificationDeliveriesResponse) GetDeliveries() []*NotificationDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
 var File_rpc_proto protoreflect.FileDescriptor
 
 const file_rpc_proto_rawDesc = "" +
@@ -1731,7 +1811,12 @@ const file_rpc_proto_rawDesc = "" +
 	"\x19UpdateCloudConfigResponse\"\x1d\n" +
 	"\x1bGetNotificationStatsRequest\"]\n" +
 	"\x1cGetNotificationStatsResponse\x12=\n" +
-	"\x05stats\x18\x01 \x03(\v2'.protobuf.NotificationSubscriptionStatsR\x05stats2\xbb\v\n" +
+	"\x05stats\x18\x01 \x03(\v2'.protobuf.NotificationSubscriptionStatsR\x05stats\"\"\n" +
+	" GetNotificationDeliveriesRequest\"c\n" +
+	"!GetNotificationDeliveriesResponse\x12>\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x1e.protobuf.NotificationDeliveryR\n" +
+	"deliveries2\xb3\f\n" +
 	"\fAgentService\x12U\n" +
 	"\x0eListContainers\x12\x1f.protobuf.ListContainersRequest\x1a .protobuf.ListContainersResponse\"\x00\x12R\n" +
 	"\rFindContainer\x12\x1e.protobuf.FindContainerRequest\x1a\x1f.protobuf.FindContainerResponse\"\x00\x12K\n" +
@@ -1749,7 +1834,8 @@ const file_rpc_proto_rawDesc = "" +
 	"\x0fContainerAttach\x12 .protobuf.ContainerAttachRequest\x1a!.protobuf.ContainerAttachResponse\"\x00(\x010\x01\x12s\n" +
 	"\x18UpdateNotificationConfig\x12).protobuf.UpdateNotificationConfigRequest\x1a*.protobuf.UpdateNotificationConfigResponse\"\x00\x12^\n" +
 	"\x11UpdateCloudConfig\x12\".protobuf.UpdateCloudConfigRequest\x1a#.protobuf.UpdateCloudConfigResponse\"\x00\x12g\n" +
-	"\x14GetNotificationStats\x12%.protobuf.GetNotificationStatsRequest\x1a&.protobuf.GetNotificationStatsResponse\"\x00B\x13Z\x11internal/agent/pbb\x06proto3"
+	"\x14GetNotificationStats\x12%.protobuf.GetNotificationStatsRequest\x1a&.protobuf.GetNotificationStatsResponse\"\x00\x12v\n" +
+	"\x19GetNotificationDeliveries\x12*.protobuf.GetNotificationDeliveriesRequest\x1a+.protobuf.GetNotificationDeliveriesResponse\"\x00B\x13Z\x11internal/agent/pbb\x06proto3"
 
 var (
 	file_rpc_proto_rawDescOnce sync.Once
@@ -1763,116 +1849,122 @@ func file_rpc_proto_rawDescGZIP() []byte {
 	return file_rpc_proto_rawDescData
 }
 
-var file_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
+var file_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 37)
 var file_rpc_proto_goTypes = []any{
-	(*ListContainersRequest)(nil),            // 0: protobuf.ListContainersRequest
-	(*RepeatedString)(nil),                   // 1: protobuf.RepeatedString
-	(*ListContainersResponse)(nil),           // 2: protobuf.ListContainersResponse
-	(*FindContainerRequest)(nil),             // 3: protobuf.FindContainerRequest
-	(*FindContainerResponse)(nil),            // 4: protobuf.FindContainerResponse
-	(*StreamLogsRequest)(nil),                // 5: protobuf.StreamLogsRequest
-	(*StreamLogsResponse)(nil),               // 6: protobuf.StreamLogsResponse
-	(*LogsBetweenDatesRequest)(nil),          // 7: protobuf.LogsBetweenDatesRequest
-	(*StreamRawBytesRequest)(nil),            // 8: protobuf.StreamRawBytesRequest
-	(*StreamRawBytesResponse)(nil),           // 9: protobuf.StreamRawBytesResponse
-	(*StreamEventsRequest)(nil),              // 10: protobuf.StreamEventsRequest
-	(*StreamEventsResponse)(nil),             // 11: protobuf.StreamEventsResponse
-	(*StreamStatsRequest)(nil),               // 12: protobuf.StreamStatsRequest
-	(*StreamStatsResponse)(nil),              // 13: protobuf.StreamStatsResponse
-	(*HostInfoRequest)(nil),                  // 14: protobuf.HostInfoRequest
-	(*HostInfoResponse)(nil),                 // 15: protobuf.HostInfoResponse
-	(*StreamContainerStartedRequest)(nil),    // 16: protobuf.StreamContainerStartedRequest
-	(*StreamContainerStartedResponse)(nil),   // 17: protobuf.StreamContainerStartedResponse
-	(*ContainerActionRequest)(nil),           // 18: protobuf.ContainerActionRequest
-	(*ContainerActionResponse)(nil),          // 19: protobuf.ContainerActionResponse
-	(*UpdateContainerRequest)(nil),           // 20: protobuf.UpdateContainerRequest
-	(*UpdateContainerProgress)(nil),          // 21: protobuf.UpdateContainerProgress
-	(*ContainerExecRequest)(nil),             // 22: protobuf.ContainerExecRequest
-	(*ResizePayload)(nil),                    // 23: protobuf.ResizePayload
-	(*ContainerExecResponse)(nil),            // 24: protobuf.ContainerExecResponse
-	(*ContainerAttachRequest)(nil),           // 25: protobuf.ContainerAttachRequest
-	(*ContainerAttachResponse)(nil),          // 26: protobuf.ContainerAttachResponse
-	(*UpdateNotificationConfigRequest)(nil),  // 27: protobuf.UpdateNotificationConfigRequest
-	(*UpdateNotificationConfigResponse)(nil), // 28: protobuf.UpdateNotificationConfigResponse
-	(*UpdateCloudConfigRequest)(nil),         // 29: protobuf.UpdateCloudConfigRequest
-	(*UpdateCloudConfigResponse)(nil),        // 30: protobuf.UpdateCloudConfigResponse
-	(*GetNotificationStatsRequest)(nil),      // 31: protobuf.GetNotificationStatsRequest
-	(*GetNotificationStatsResponse)(nil),     // 32: protobuf.GetNotificationStatsResponse
-	nil,                                      // 33: protobuf.ListContainersRequest.FilterEntry
-	nil,                                      // 34: protobuf.FindContainerRequest.FilterEntry
-	(*Container)(nil),                        // 35: protobuf.Container
-	(*timestamppb.Timestamp)(nil),            // 36: google.protobuf.Timestamp
-	(*LogEvent)(nil),                         // 37: protobuf.LogEvent
-	(*ContainerEvent)(nil),                   // 38: protobuf.ContainerEvent
-	(*ContainerStat)(nil),                    // 39: protobuf.ContainerStat
-	(*Host)(nil),                             // 40: protobuf.Host
-	(ContainerAction)(0),                     // 41: protobuf.ContainerAction
-	(*NotificationSubscription)(nil),         // 42: protobuf.NotificationSubscription
-	(*NotificationDispatcher)(nil),           // 43: protobuf.NotificationDispatcher
-	(*NotificationCloudConfig)(nil),          // 44: protobuf.NotificationCloudConfig
-	(*NotificationSubscriptionStats)(nil),    // 45: protobuf.NotificationSubscriptionStats
+	(*ListContainersRequest)(nil),             // 0: protobuf.ListContainersRequest
+	(*RepeatedString)(nil),                    // 1: protobuf.RepeatedString
+	(*ListContainersResponse)(nil),            // 2: protobuf.ListContainersResponse
+	(*FindContainerRequest)(nil),              // 3: protobuf.FindContainerRequest
+	(*FindContainerResponse)(nil),             // 4: protobuf.FindContainerResponse
+	(*StreamLogsRequest)(nil),                 // 5: protobuf.StreamLogsRequest
+	(*StreamLogsResponse)(nil),                // 6: protobuf.StreamLogsResponse
+	(*LogsBetweenDatesRequest)(nil),           // 7: protobuf.LogsBetweenDatesRequest
+	(*StreamRawBytesRequest)(nil),             // 8: protobuf.StreamRawBytesRequest
+	(*StreamRawBytesResponse)(nil),            // 9: protobuf.StreamRawBytesResponse
+	(*StreamEventsRequest)(nil),               // 10: protobuf.StreamEventsRequest
+	(*StreamEventsResponse)(nil),              // 11: protobuf.StreamEventsResponse
+	(*StreamStatsRequest)(nil),                // 12: protobuf.StreamStatsRequest
+	(*StreamStatsResponse)(nil),               // 13: protobuf.StreamStatsResponse
+	(*HostInfoRequest)(nil),                   // 14: protobuf.HostInfoRequest
+	(*HostInfoResponse)(nil),                  // 15: protobuf.HostInfoResponse
+	(*StreamContainerStartedRequest)(nil),     // 16: protobuf.StreamContainerStartedRequest
+	(*StreamContainerStartedResponse)(nil),    // 17: protobuf.StreamContainerStartedResponse
+	(*ContainerActionRequest)(nil),            // 18: protobuf.ContainerActionRequest
+	(*ContainerActionResponse)(nil),           // 19: protobuf.ContainerActionResponse
+	(*UpdateContainerRequest)(nil),            // 20: protobuf.UpdateContainerRequest
+	(*UpdateContainerProgress)(nil),           // 21: protobuf.UpdateContainerProgress
+	(*ContainerExecRequest)(nil),              // 22: protobuf.ContainerExecRequest
+	(*ResizePayload)(nil),                     // 23: protobuf.ResizePayload
+	(*ContainerExecResponse)(nil),             // 24: protobuf.ContainerExecResponse
+	(*ContainerAttachRequest)(nil),            // 25: protobuf.ContainerAttachRequest
+	(*ContainerAttachResponse)(nil),           // 26: protobuf.ContainerAttachResponse
+	(*UpdateNotificationConfigRequest)(nil),   // 27: protobuf.UpdateNotificationConfigRequest
+	(*UpdateNotificationConfigResponse)(nil),  // 28: protobuf.UpdateNotificationConfigResponse
+	(*UpdateCloudConfigRequest)(nil),          // 29: protobuf.UpdateCloudConfigRequest
+	(*UpdateCloudConfigResponse)(nil),         // 30: protobuf.UpdateCloudConfigResponse
+	(*GetNotificationStatsRequest)(nil),       // 31: protobuf.GetNotificationStatsRequest
+	(*GetNotificationStatsResponse)(nil),      // 32: protobuf.GetNotificationStatsResponse
+	(*GetNotificationDeliveriesRequest)(nil),  // 33: protobuf.GetNotificationDeliveriesRequest
+	(*GetNotificationDeliveriesResponse)(nil), // 34: protobuf.GetNotificationDeliveriesResponse
+	nil,                                   // 35: protobuf.ListContainersRequest.FilterEntry
+	nil,                                   // 36: protobuf.FindContainerRequest.FilterEntry
+	(*Container)(nil),                     // 37: protobuf.Container
+	(*timestamppb.Timestamp)(nil),         // 38: google.protobuf.Timestamp
+	(*LogEvent)(nil),                      // 39: protobuf.LogEvent
+	(*ContainerEvent)(nil),                // 40: protobuf.ContainerEvent
+	(*ContainerStat)(nil),                 // 41: protobuf.ContainerStat
+	(*Host)(nil),                          // 42: protobuf.Host
+	(ContainerAction)(0),                  // 43: protobuf.ContainerAction
+	(*NotificationSubscription)(nil),      // 44: protobuf.NotificationSubscription
+	(*NotificationDispatcher)(nil),        // 45: protobuf.NotificationDispatcher
+	(*NotificationCloudConfig)(nil),       // 46: protobuf.NotificationCloudConfig
+	(*NotificationSubscriptionStats)(nil), // 47: protobuf.NotificationSubscriptionStats
+	(*NotificationDelivery)(nil),          // 48: protobuf.NotificationDelivery
 }
 var file_rpc_proto_depIdxs = []int32{
-	33, // 0: protobuf.ListContainersRequest.filter:type_name -> protobuf.ListContainersRequest.FilterEntry
-	35, // 1: protobuf.ListContainersResponse.containers:type_name -> protobuf.Container
-	34, // 2: protobuf.FindContainerRequest.filter:type_name -> protobuf.FindContainerRequest.FilterEntry
-	35, // 3: protobuf.FindContainerResponse.container:type_name -> protobuf.Container
-	36, // 4: protobuf.StreamLogsRequest.since:type_name -> google.protobuf.Timestamp
-	37, // 5: protobuf.StreamLogsResponse.event:type_name -> protobuf.LogEvent
-	36, // 6: protobuf.LogsBetweenDatesRequest.since:type_name -> google.protobuf.Timestamp
-	36, // 7: protobuf.LogsBetweenDatesRequest.until:type_name -> google.protobuf.Timestamp
-	36, // 8: protobuf.StreamRawBytesRequest.since:type_name -> google.protobuf.Timestamp
-	36, // 9: protobuf.StreamRawBytesRequest.until:type_name -> google.protobuf.Timestamp
-	38, // 10: protobuf.StreamEventsResponse.event:type_name -> protobuf.ContainerEvent
-	39, // 11: protobuf.StreamStatsResponse.stat:type_name -> protobuf.ContainerStat
-	40, // 12: protobuf.HostInfoResponse.host:type_name -> protobuf.Host
-	35, // 13: protobuf.StreamContainerStartedResponse.container:type_name -> protobuf.Container
-	41, // 14: protobuf.ContainerActionRequest.action:type_name -> protobuf.ContainerAction
+	35, // 0: protobuf.ListContainersRequest.filter:type_name -> protobuf.ListContainersRequest.FilterEntry
+	37, // 1: protobuf.ListContainersResponse.containers:type_name -> protobuf.Container
+	36, // 2: protobuf.FindContainerRequest.filter:type_name -> protobuf.FindContainerRequest.FilterEntry
+	37, // 3: protobuf.FindContainerResponse.container:type_name -> protobuf.Container
+	38, // 4: protobuf.StreamLogsRequest.since:type_name -> google.protobuf.Timestamp
+	39, // 5: protobuf.StreamLogsResponse.event:type_name -> protobuf.LogEvent
+	38, // 6: protobuf.LogsBetweenDatesRequest.since:type_name -> google.protobuf.Timestamp
+	38, // 7: protobuf.LogsBetweenDatesRequest.until:type_name -> google.protobuf.Timestamp
+	38, // 8: protobuf.StreamRawBytesRequest.since:type_name -> google.protobuf.Timestamp
+	38, // 9: protobuf.StreamRawBytesRequest.until:type_name -> google.protobuf.Timestamp
+	40, // 10: protobuf.StreamEventsResponse.event:type_name -> protobuf.ContainerEvent
+	41, // 11: protobuf.StreamStatsResponse.stat:type_name -> protobuf.ContainerStat
+	42, // 12: protobuf.HostInfoResponse.host:type_name -> protobuf.Host
+	37, // 13: protobuf.StreamContainerStartedResponse.container:type_name -> protobuf.Container
+	43, // 14: protobuf.ContainerActionRequest.action:type_name -> protobuf.ContainerAction
 	23, // 15: protobuf.ContainerExecRequest.resize:type_name -> protobuf.ResizePayload
 	23, // 16: protobuf.ContainerAttachRequest.resize:type_name -> protobuf.ResizePayload
-	42, // 17: protobuf.UpdateNotificationConfigRequest.subscriptions:type_name -> protobuf.NotificationSubscription
-	43, // 18: protobuf.UpdateNotificationConfigRequest.dispatchers:type_name -> protobuf.NotificationDispatcher
-	44, // 19: protobuf.UpdateCloudConfigRequest.cloudConfig:type_name -> protobuf.NotificationCloudConfig
-	45, // 20: protobuf.GetNotificationStatsResponse.stats:type_name -> protobuf.NotificationSubscriptionStats
-	1,  // 21: protobuf.ListContainersRequest.FilterEntry.value:type_name -> protobuf.RepeatedString
-	1,  // 22: protobuf.FindContainerRequest.FilterEntry.value:type_name -> protobuf.RepeatedString
-	0,  // 23: protobuf.AgentService.ListContainers:input_type -> protobuf.ListContainersRequest
-	3,  // 24: protobuf.AgentService.FindContainer:input_type -> protobuf.FindContainerRequest
-	5,  // 25: protobuf.AgentService.StreamLogs:input_type -> protobuf.StreamLogsRequest
-	7,  // 26: protobuf.AgentService.LogsBetweenDates:input_type -> protobuf.LogsBetweenDatesRequest
-	8,  // 27: protobuf.AgentService.StreamRawBytes:input_type -> protobuf.StreamRawBytesRequest
-	10, // 28: protobuf.AgentService.StreamEvents:input_type -> protobuf.StreamEventsRequest
-	12, // 29: protobuf.AgentService.StreamStats:input_type -> protobuf.StreamStatsRequest
-	16, // 30: protobuf.AgentService.StreamContainerStarted:input_type -> protobuf.StreamContainerStartedRequest
-	14, // 31: protobuf.AgentService.HostInfo:input_type -> protobuf.HostInfoRequest
-	18, // 32: protobuf.AgentService.ContainerAction:input_type -> protobuf.ContainerActionRequest
-	20, // 33: protobuf.AgentService.UpdateContainer:input_type -> protobuf.UpdateContainerRequest
-	22, // 34: protobuf.AgentService.ContainerExec:input_type -> protobuf.ContainerExecRequest
-	25, // 35: protobuf.AgentService.ContainerAttach:input_type -> protobuf.ContainerAttachRequest
-	27, // 36: protobuf.AgentService.UpdateNotificationConfig:input_type -> protobuf.UpdateNotificationConfigRequest
-	29, // 37: protobuf.AgentService.UpdateCloudConfig:input_type -> protobuf.UpdateCloudConfigRequest
-	31, // 38: protobuf.AgentService.GetNotificationStats:input_type -> protobuf.GetNotificationStatsRequest
-	2,  // 39: protobuf.AgentService.ListContainers:output_type -> protobuf.ListContainersResponse
-	4,  // 40: protobuf.AgentService.FindContainer:output_type -> protobuf.FindContainerResponse
-	6,  // 41: protobuf.AgentService.StreamLogs:output_type -> protobuf.StreamLogsResponse
-	6,  // 42: protobuf.AgentService.LogsBetweenDates:output_type -> protobuf.StreamLogsResponse
-	9,  // 43: protobuf.AgentService.StreamRawBytes:output_type -> protobuf.StreamRawBytesResponse
-	11, // 44: protobuf.AgentService.StreamEvents:output_type -> protobuf.StreamEventsResponse
-	13, // 45: protobuf.AgentService.StreamStats:output_type -> protobuf.StreamStatsResponse
-	17, // 46: protobuf.AgentService.StreamContainerStarted:output_type -> protobuf.StreamContainerStartedResponse
-	15, // 47: protobuf.AgentService.HostInfo:output_type -> protobuf.HostInfoResponse
-	19, // 48: protobuf.AgentService.ContainerAction:output_type -> protobuf.ContainerActionResponse
-	21, // 49: protobuf.AgentService.UpdateContainer:output_type -> protobuf.UpdateContainerProgress
-	24, // 50: protobuf.AgentService.ContainerExec:output_type -> protobuf.ContainerExecResponse
-	26, // 51: protobuf.AgentService.ContainerAttach:output_type -> protobuf.ContainerAttachResponse
-	28, // 52: protobuf.AgentService.UpdateNotificationConfig:output_type -> protobuf.UpdateNotificationConfigResponse
-	30, // 53: protobuf.AgentService.UpdateCloudConfig:output_type -> protobuf.UpdateCloudConfigResponse
-	32, // 54: protobuf.AgentService.GetNotificationStats:output_type -> protobuf.GetNotificationStatsResponse
-	39, // [39:55] is the sub-list for method output_type
-	23, // [23:39] is the sub-list for method input_type
-	23, // [23:23] is the sub-list for extension type_name
-	23, // [23:23] is the sub-list for extension extendee
-	0,  // [0:23] is the sub-list for field type_name
+	44, // 17: protobuf.UpdateNotificationConfigRequest.subscriptions:type_name -> protobuf.NotificationSubscription
+	45, // 18: protobuf.UpdateNotificationConfigRequest.dispatchers:type_name -> protobuf.NotificationDispatcher
+	46, // 19: protobuf.UpdateCloudConfigRequest.cloudConfig:type_name -> protobuf.NotificationCloudConfig
+	47, // 20: protobuf.GetNotificationStatsResponse.stats:type_name -> protobuf.NotificationSubscriptionStats
+	48, // 21: protobuf.GetNotificationDeliveriesResponse.deliveries:type_name -> protobuf.NotificationDelivery
+	1,  // 22: protobuf.ListContainersRequest.FilterEntry.value:type_name -> protobuf.RepeatedString
+	1,  // 23: protobuf.FindContainerRequest.FilterEntry.value:type_name -> protobuf.RepeatedString
+	0,  // 24: protobuf.AgentService.ListContainers:input_type -> protobuf.ListContainersRequest
+	3,  // 25: protobuf.AgentService.FindContainer:input_type -> protobuf.FindContainerRequest
+	5,  // 26: protobuf.AgentService.StreamLogs:input_type -> protobuf.StreamLogsRequest
+	7,  // 27: protobuf.AgentService.LogsBetweenDates:input_type -> protobuf.LogsBetweenDatesRequest
+	8,  // 28: protobuf.AgentService.StreamRawBytes:input_type -> protobuf.StreamRawBytesRequest
+	10, // 29: protobuf.AgentService.StreamEvents:input_type -> protobuf.StreamEventsRequest
+	12, // 30: protobuf.AgentService.StreamStats:input_type -> protobuf.StreamStatsRequest
+	16, // 31: protobuf.AgentService.StreamContainerStarted:input_type -> protobuf.StreamContainerStartedRequest
+	14, // 32: protobuf.AgentService.HostInfo:input_type -> protobuf.HostInfoRequest
+	18, // 33: protobuf.AgentService.ContainerAction:input_type -> protobuf.ContainerActionRequest
+	20, // 34: protobuf.AgentService.UpdateContainer:input_type -> protobuf.UpdateContainerRequest
+	22, // 35: protobuf.AgentService.ContainerExec:input_type -> protobuf.ContainerExecRequest
+	25, // 36: protobuf.AgentService.ContainerAttach:input_type -> protobuf.ContainerAttachRequest
+	27, // 37: protobuf.AgentService.UpdateNotificationConfig:input_type -> protobuf.UpdateNotificationConfigRequest
+	29, // 38: protobuf.AgentService.UpdateCloudConfig:input_type -> protobuf.UpdateCloudConfigRequest
+	31, // 39: protobuf.AgentService.GetNotificationStats:input_type -> protobuf.GetNotificationStatsRequest
+	33, // 40: protobuf.AgentService.GetNotificationDeliveries:input_type -> protobuf.GetNotificationDeliveriesRequest
+	2,  // 41: protobuf.AgentService.ListContainers:output_type -> protobuf.ListContainersResponse
+	4,  // 42: protobuf.AgentService.FindContainer:output_type -> protobuf.FindContainerResponse
+	6,  // 43: protobuf.AgentService.StreamLogs:output_type -> protobuf.StreamLogsResponse
+	6,  // 44: protobuf.AgentService.LogsBetweenDates:output_type -> protobuf.StreamLogsResponse
+	9,  // 45: protobuf.AgentService.StreamRawBytes:output_type -> protobuf.StreamRawBytesResponse
+	11, // 46: protobuf.AgentService.StreamEvents:output_type -> protobuf.StreamEventsResponse
+	13, // 47: protobuf.AgentService.StreamStats:output_type -> protobuf.StreamStatsResponse
+	17, // 48: protobuf.AgentService.StreamContainerStarted:output_type -> protobuf.StreamContainerStartedResponse
+	15, // 49: protobuf.AgentService.HostInfo:output_type -> protobuf.HostInfoResponse
+	19, // 50: protobuf.AgentService.ContainerAction:output_type -> protobuf.ContainerActionResponse
+	21, // 51: protobuf.AgentService.UpdateContainer:output_type -> protobuf.UpdateContainerProgress
+	24, // 52: protobuf.AgentService.ContainerExec:output_type -> protobuf.ContainerExecResponse
+	26, // 53: protobuf.AgentService.ContainerAttach:output_type -> protobuf.ContainerAttachResponse
+	28, // 54: protobuf.AgentService.UpdateNotificationConfig:output_type -> protobuf.UpdateNotificationConfigResponse
+	30, // 55: protobuf.AgentService.UpdateCloudConfig:output_type -> protobuf.UpdateCloudConfigResponse
+	32, // 56: protobuf.AgentService.GetNotificationStats:output_type -> protobuf.GetNotificationStatsResponse
+	34, // 57: protobuf.AgentService.GetNotificationDeliveries:output_type -> protobuf.GetNotificationDeliveriesResponse
+	41, // [41:58] is the sub-list for method output_type
+	24, // [24:41] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
 }
 
 func init() { file_rpc_proto_init() }
@@ -1895,7 +1987,7 @@ func file_rpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_proto_rawDesc), len(file_rpc_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   35,
+			NumMessages:   37,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,22 +19,23 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AgentService_ListContainers_FullMethodName           = "/protobuf.AgentService/ListContainers"
-	AgentService_FindContainer_FullMethodName            = "/protobuf.AgentService/FindContainer"
-	AgentService_StreamLogs_FullMethodName               = "/protobuf.AgentService/StreamLogs"
-	AgentService_LogsBetweenDates_FullMethodName         = "/protobuf.AgentService/LogsBetweenDates"
-	AgentService_StreamRawBytes_FullMethodName           = "/protobuf.AgentService/StreamRawBytes"
-	AgentService_StreamEvents_FullMethodName             = "/protobuf.AgentService/StreamEvents"
-	AgentService_StreamStats_FullMethodName              = "/protobuf.AgentService/StreamStats"
-	AgentService_StreamContainerStarted_FullMethodName   = "/protobuf.AgentService/StreamContainerStarted"
-	AgentService_HostInfo_FullMethodName                 = "/protobuf.AgentService/HostInfo"
-	AgentService_ContainerAction_FullMethodName          = "/protobuf.AgentService/ContainerAction"
-	AgentService_UpdateContainer_FullMethodName          = "/protobuf.AgentService/UpdateContainer"
-	AgentService_ContainerExec_FullMethodName            = "/protobuf.AgentService/ContainerExec"
-	AgentService_ContainerAttach_FullMethodName          = "/protobuf.AgentService/ContainerAttach"
-	AgentService_UpdateNotificationConfig_FullMethodName = "/protobuf.AgentService/UpdateNotificationConfig"
-	AgentService_UpdateCloudConfig_FullMethodName        = "/protobuf.AgentService/UpdateCloudConfig"
-	AgentService_GetNotificationStats_FullMethodName     = "/protobuf.AgentService/GetNotificationStats"
+	AgentService_ListContainers_FullMethodName            = "/protobuf.AgentService/ListContainers"
+	AgentService_FindContainer_FullMethodName             = "/protobuf.AgentService/FindContainer"
+	AgentService_StreamLogs_FullMethodName                = "/protobuf.AgentService/StreamLogs"
+	AgentService_LogsBetweenDates_FullMethodName          = "/protobuf.AgentService/LogsBetweenDates"
+	AgentService_StreamRawBytes_FullMethodName            = "/protobuf.AgentService/StreamRawBytes"
+	AgentService_StreamEvents_FullMethodName              = "/protobuf.AgentService/StreamEvents"
+	AgentService_StreamStats_FullMethodName               = "/protobuf.AgentService/StreamStats"
+	AgentService_StreamContainerStarted_FullMethodName    = "/protobuf.AgentService/StreamContainerStarted"
+	AgentService_HostInfo_FullMethodName                  = "/protobuf.AgentService/HostInfo"
+	AgentService_ContainerAction_FullMethodName           = "/protobuf.AgentService/ContainerAction"
+	AgentService_UpdateContainer_FullMethodName           = "/protobuf.AgentService/UpdateContainer"
+	AgentService_ContainerExec_FullMethodName             = "/protobuf.AgentService/ContainerExec"
+	AgentService_ContainerAttach_FullMethodName           = "/protobuf.AgentService/ContainerAttach"
+	AgentService_UpdateNotificationConfig_FullMethodName  = "/protobuf.AgentService/UpdateNotificationConfig"
+	AgentService_UpdateCloudConfig_FullMethodName         = "/protobuf.AgentService/UpdateCloudConfig"
+	AgentService_GetNotificationStats_FullMethodName      = "/protobuf.AgentService/GetNotificationStats"
+	AgentService_GetNotificationDeliveries_FullMethodName = "/protobuf.AgentService/GetNotificationDeliveries"
 )
 
 // AgentServiceClient is the client API for AgentService service.
@@ -57,6 +58,7 @@ type AgentServiceClient interface {
 	UpdateNotificationConfig(ctx context.Context, in *UpdateNotificationConfigRequest, opts ...grpc.CallOption) (*UpdateNotificationConfigResponse, error)
 	UpdateCloudConfig(ctx context.Context, in *UpdateCloudConfigRequest, opts ...grpc.CallOption) (*UpdateCloudConfigResponse, error)
 	GetNotificationStats(ctx context.Context, in *GetNotificationStatsRequest, opts ...grpc.CallOption) (*GetNotificationStatsResponse, error)
+	GetNotificationDeliveries(ctx context.Context, in *GetNotificationDeliveriesRequest, opts ...grpc.CallOption) (*GetNotificationDeliveriesResponse, error)
 }
 
 type agentServiceClient struct {
@@ -296,6 +298,16 @@ func (c *agentServiceClient) GetNotificationStats(ctx context.Context, in *GetNo
 	return out, nil
 }
 
+func (c *agentServiceClient) GetNotificationDeliveries(ctx context.Context, in *GetNotificationDeliveriesRequest, opts ...grpc.CallOption) (*GetNotificationDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetNotificationDeliveriesResponse)
+	err := c.cc.Invoke(ctx, AgentService_GetNotificationDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServiceServer is the server API for AgentService service.
 // All implementations must embed UnimplementedAgentServiceServer
 // for forward compatibility.
@@ -316,6 +328,7 @@ type AgentServiceServer interface {
 	UpdateNotificationConfig(context.Context, *UpdateNotificationConfigRequest) (*UpdateNotificationConfigResponse, error)
 	UpdateCloudConfig(context.Context, *UpdateCloudConfigRequest) (*UpdateCloudConfigResponse, error)
 	GetNotificationStats(context.Context, *GetNotificationStatsRequest) (*GetNotificationStatsResponse, error)
+	GetNotificationDeliveries(context.Context, *GetNotificationDeliveriesRequest) (*GetNotificationDeliveriesResponse, error)
 	mustEmbedUnimplementedAgentServiceServer()
 }
 
@@ -374,6 +387,9 @@ func (UnimplementedAgentServiceServer) UpdateCloudConfig(context.Context, *Updat
 func (UnimplementedAgentServiceServer) GetNotificationStats(context.Context, *GetNotificationStatsRequest) (*GetNotificationStatsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetNotificationStats not implemented")
 }
+func (UnimplementedAgentServiceServer) GetNotificationDeliveries(context.Context, *GetNotificationDeliveriesRequest) (*GetNotificationDeliveriesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetNotificationDeliveries not implemented")
+}
 func (UnimplementedAgentServiceServer) mustEmbedUnimplementedAgentServiceServer() {}
 func (UnimplementedAgentServiceServer) testEmbeddedByValue()                      {}
 
@@ -612,6 +628,24 @@ func _AgentService_GetNotificationStats_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AgentService_GetNotificationDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotificationDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetNotificationDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetNotificationDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetNotificationDeliveries(ctx, req.(*GetNotificationDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AgentService_ServiceDesc is the grpc.ServiceDesc for AgentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -647,6 +681,10 @@ var AgentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetNotificationStats",
 			Handler:    _AgentService_GetNotificationStats_Handler,
 		},
+		{
+			MethodName: "GetNotificationDeliveries",
+			Handler:    _AgentService_GetNotificationDeliveries_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -608,17 +608,19 @@ func (x *LogFragment) GetMessage() string {
 }
 
 type LogEvent struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	ContainerId   string                 `protobuf:"bytes,2,opt,name=containerId,proto3" json:"containerId,omitempty"`
-	Message       *anypb.Any             `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"` // SingleMessage, GroupMessage, or ComplexMessage
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Level         string                 `protobuf:"bytes,5,opt,name=level,proto3" json:"level,omitempty"`
-	Stream        string                 `protobuf:"bytes,6,opt,name=stream,proto3" json:"stream,omitempty"`
-	Type          string                 `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"` // "single", "group", or "complex"
-	RawMessage    string                 `protobuf:"bytes,8,opt,name=rawMessage,proto3" json:"rawMessage,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ContainerId      string                 `protobuf:"bytes,2,opt,name=containerId,proto3" json:"containerId,omitempty"`
+	Message          *anypb.Any             `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"` // SingleMessage, GroupMessage, or ComplexMessage
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Level            string                 `protobuf:"bytes,5,opt,name=level,proto3" json:"level,omitempty"`
+	Stream           string                 `protobuf:"bytes,6,opt,name=stream,proto3" json:"stream,omitempty"`
+	Type             string                 `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"` // "single", "group", or "complex"
+	RawMessage       string                 `protobuf:"bytes,8,opt,name=rawMessage,proto3" json:"rawMessage,omitempty"`
+	Summary          string                 `protobuf:"bytes,9,opt,name=summary,proto3" json:"summary,omitempty"`
+	MessageTimestamp int64                  `protobuf:"varint,10,opt,name=messageTimestamp,proto3" json:"messageTimestamp,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *LogEvent) Reset() {
@@ -707,6 +709,20 @@ func (x *LogEvent) GetRawMessage() string {
 	return ""
 }
 
+func (x *LogEvent) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *LogEvent) GetMessageTimestamp() int64 {
+	if x != nil {
+		return x.MessageTimestamp
+	}
+	return 0
+}
+
 type SingleMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
@@ -1075,6 +1091,8 @@ type NotificationSubscription struct {
 	Cooldown            int32                  `protobuf:"varint,8,opt,name=cooldown,proto3" json:"cooldown,omitempty"`
 	SampleWindow        int32                  `protobuf:"varint,9,opt,name=sampleWindow,proto3" json:"sampleWindow,omitempty"`
 	EventExpression     string                 `protobuf:"bytes,10,opt,name=eventExpression,proto3" json:"eventExpression,omitempty"`
+	GroupBy             string                 `protobuf:"bytes,11,opt,name=groupBy,proto3" json:"groupBy,omitempty"`
+	DigestWindow        int32                  `protobuf:"varint,12,opt,name=digestWindow,proto3" json:"digestWindow,omitempty"`
 	unknownFields       protoimpl.UnknownFields
 	sizeCache           protoimpl.SizeCache
 }
@@ -1179,6 +1197,20 @@ func (x *NotificationSubscription) GetEventExpression() string {
 	return ""
 }
 
+func (x *NotificationSubscription) GetGroupBy() string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ""
+}
+
+func (x *NotificationSubscription) GetDigestWindow() int32 {
+	if x != nil {
+		return x.DigestWindow
+	}
+	return 0
+}
+
 type NotificationDispatcher struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -1187,6 +1219,20 @@ type NotificationDispatcher struct {
 	Url           string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
 	Template      string                 `protobuf:"bytes,5,opt,name=template,proto3" json:"template,omitempty"`
 	Headers       map[string]string      `protobuf:"bytes,6,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Channel       string                 `protobuf:"bytes,10,opt,name=channel,proto3" json:"channel,omitempty"`
+	Topic         string                 `protobuf:"bytes,11,opt,name=topic,proto3" json:"topic,omitempty"`
+	Priority      string                 `protobuf:"bytes,12,opt,name=priority,proto3" json:"priority,omitempty"`
+	Token         string                 `protobuf:"bytes,13,opt,name=token,proto3" json:"token,omitempty"`
+	User          string                 `protobuf:"bytes,14,opt,name=user,proto3" json:"user,omitempty"`
+	DozzleUrl     string                 `protobuf:"bytes,15,opt,name=dozzleUrl,proto3" json:"dozzleUrl,omitempty"`
+	SmtpHost      string                 `protobuf:"bytes,16,opt,name=smtpHost,proto3" json:"smtpHost,omitempty"`
+	SmtpPort      int32                  `protobuf:"varint,17,opt,name=smtpPort,proto3" json:"smtpPort,omitempty"`
+	SmtpSecurity  string                 `protobuf:"bytes,18,opt,name=smtpSecurity,proto3" json:"smtpSecurity,omitempty"`
+	SmtpUsername  string                 `protobuf:"bytes,19,opt,name=smtpUsername,proto3" json:"smtpUsername,omitempty"`
+	SmtpPassword  string                 `protobuf:"bytes,20,opt,name=smtpPassword,proto3" json:"smtpPassword,omitempty"`
+	From          string                 `protobuf:"bytes,21,opt,name=from,proto3" json:"from,omitempty"`
+	To            []string               `protobuf:"bytes,22,rep,name=to,proto3" json:"to,omitempty"`
+	Subject       string                 `protobuf:"bytes,23,opt,name=subject,proto3" json:"subject,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1263,6 +1309,104 @@ func (x *NotificationDispatcher) GetHeaders() map[string]string {
 	return nil
 }
 
+func (x *NotificationDispatcher) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *NotificationDispatcher) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *NotificationDispatcher) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *NotificationDispatcher) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *NotificationDispatcher) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *NotificationDispatcher) GetDozzleUrl() string {
+	if x != nil {
+		return x.DozzleUrl
+	}
+	return ""
+}
+
+func (x *NotificationDispatcher) GetSmtpHost() string {
+	if x != nil {
+		return x.SmtpHost
+	}
+	return ""
+}
+
+func (x *NotificationDispatcher) GetSmtpPort() int32 {
+	if x != nil {
+		return x.SmtpPort
+	}
+	return 0
+}
+
+func (x *NotificationDispatcher) GetSmtpSecurity() string {
+	if x != nil {
+		return x.SmtpSecurity
+	}
+	return ""
+}
+
+func (x *NotificationDispatcher) GetSmtpUsername() string {
+	if x != nil {
+		return x.SmtpUsername
+	}
+	return ""
+}
+
+func (x *NotificationDispatcher) GetSmtpPassword() string {
+	if x != nil {
+		return x.SmtpPassword
+	}
+	return ""
+}
+
+func (x *NotificationDispatcher) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *NotificationDispatcher) GetTo() []string {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *NotificationDispatcher) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
 type NotificationCloudConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ApiKey        string                 `protobuf:"bytes,1,opt,name=apiKey,proto3" json:"apiKey,omitempty"`
@@ -1324,11 +1468,12 @@ func (x *NotificationCloudConfig) GetExpiresAt() *timestamppb.Timestamp {
 }
 
 type NotificationSubscriptionStats struct {
-	state                 protoimpl.MessageState `protogen:"open.v1"`
-	SubscriptionId        int32                  `protobuf:"varint,1,opt,name=subscriptionId,proto3" json:"subscriptionId,omitempty"`
-	TriggerCount          int64                  `protobuf:"varint,2,opt,name=triggerCount,proto3" json:"triggerCount,omitempty"`
-	LastTriggeredAt       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=lastTriggeredAt,proto3" json:"lastTriggeredAt,omitempty"`
-	TriggeredContainerIds []string               `protobuf:"bytes,4,rep,name=triggeredContainerIds,proto3" json:"triggeredContainerIds,omitempty"`
+	state                 protoimpl.MessageState     `protogen:"open.v1"`
+	SubscriptionId        int32                      `protobuf:"varint,1,opt,name=subscriptionId,proto3" json:"subscriptionId,omitempty"`
+	TriggerCount          int64                      `protobuf:"varint,2,opt,name=triggerCount,proto3" json:"triggerCount,omitempty"`
+	LastTriggeredAt       *timestamppb.Timestamp     `protobuf:"bytes,3,opt,name=lastTriggeredAt,proto3" json:"lastTriggeredAt,omitempty"`
+	TriggeredContainerIds []string                   `protobuf:"bytes,4,rep,name=triggeredContainerIds,proto3" json:"triggeredContainerIds,omitempty"`
+	FiringAlerts          []*NotificationFiringAlert `protobuf:"bytes,5,rep,name=firingAlerts,proto3" json:"firingAlerts,omitempty"`
 	unknownFields         protoimpl.UnknownFields
 	sizeCache             protoimpl.SizeCache
 }
@@ -1391,6 +1536,293 @@ func (x *NotificationSubscriptionStats) GetTriggeredContainerIds() []string {
 	return nil
 }
 
+func (x *NotificationSubscriptionStats) GetFiringAlerts() []*NotificationFiringAlert {
+	if x != nil {
+		return x.FiringAlerts
+	}
+	return nil
+}
+
+type NotificationFiringAlert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	ContainerId   string                 `protobuf:"bytes,2,opt,name=containerId,proto3" json:"containerId,omitempty"`
+	ContainerName string                 `protobuf:"bytes,3,opt,name=containerName,proto3" json:"containerName,omitempty"`
+	Image         string                 `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`
+	HostId        string                 `protobuf:"bytes,5,opt,name=hostId,proto3" json:"hostId,omitempty"`
+	HostName      string                 `protobuf:"bytes,6,opt,name=hostName,proto3" json:"hostName,omitempty"`
+	Detail        string                 `protobuf:"bytes,7,opt,name=detail,proto3" json:"detail,omitempty"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationFiringAlert) Reset() {
+	*x = NotificationFiringAlert{}
+	mi := &file_types_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationFiringAlert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationFiringAlert) ProtoMessage() {}
+
+func (x *NotificationFiringAlert) ProtoReflect() protoreflect.Message {
+	mi := &file_types_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationFiringAlert.ProtoReflect.Descriptor instead.
+func (*NotificationFiringAlert) Descriptor() ([]byte, []int) {
+	return file_types_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *NotificationFiringAlert) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *NotificationFiringAlert) GetContainerId() string {
+	if x != nil {
+		return x.ContainerId
+	}
+	return ""
+}
+
+func (x *NotificationFiringAlert) GetContainerName() string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return ""
+}
+
+func (x *NotificationFiringAlert) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *NotificationFiringAlert) GetHostId() string {
+	if x != nil {
+		return x.HostId
+	}
+	return ""
+}
+
+func (x *NotificationFiringAlert) GetHostName() string {
+	if x != nil {
+		return x.HostName
+	}
+	return ""
+}
+
+func (x *NotificationFiringAlert) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *NotificationFiringAlert) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+type NotificationDelivery struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	NotificationId   string                 `protobuf:"bytes,1,opt,name=notificationId,proto3" json:"notificationId,omitempty"`
+	Time             *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Type             string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Status           string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	SubscriptionId   int32                  `protobuf:"varint,5,opt,name=subscriptionId,proto3" json:"subscriptionId,omitempty"`
+	SubscriptionName string                 `protobuf:"bytes,6,opt,name=subscriptionName,proto3" json:"subscriptionName,omitempty"`
+	DispatcherId     int32                  `protobuf:"varint,7,opt,name=dispatcherId,proto3" json:"dispatcherId,omitempty"`
+	DispatcherName   string                 `protobuf:"bytes,8,opt,name=dispatcherName,proto3" json:"dispatcherName,omitempty"`
+	DispatcherType   string                 `protobuf:"bytes,9,opt,name=dispatcherType,proto3" json:"dispatcherType,omitempty"`
+	ContainerId      string                 `protobuf:"bytes,10,opt,name=containerId,proto3" json:"containerId,omitempty"`
+	ContainerName    string                 `protobuf:"bytes,11,opt,name=containerName,proto3" json:"containerName,omitempty"`
+	HostId           string                 `protobuf:"bytes,12,opt,name=hostId,proto3" json:"hostId,omitempty"`
+	HostName         string                 `protobuf:"bytes,13,opt,name=hostName,proto3" json:"hostName,omitempty"`
+	Summary          string                 `protobuf:"bytes,14,opt,name=summary,proto3" json:"summary,omitempty"`
+	Success          bool                   `protobuf:"varint,15,opt,name=success,proto3" json:"success,omitempty"`
+	StatusCode       int32                  `protobuf:"varint,16,opt,name=statusCode,proto3" json:"statusCode,omitempty"`
+	LatencyMs        int64                  `protobuf:"varint,17,opt,name=latencyMs,proto3" json:"latencyMs,omitempty"`
+	Error            string                 `protobuf:"bytes,18,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *NotificationDelivery) Reset() {
+	*x = NotificationDelivery{}
+	mi := &file_types_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationDelivery) ProtoMessage() {}
+
+func (x *NotificationDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_types_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationDelivery.ProtoReflect.Descriptor instead.
+func (*NotificationDelivery) Descriptor() ([]byte, []int) {
+	return file_types_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *NotificationDelivery) GetNotificationId() string {
+	if x != nil {
+		return x.NotificationId
+	}
+	return ""
+}
+
+func (x *NotificationDelivery) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *NotificationDelivery) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *NotificationDelivery) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *NotificationDelivery) GetSubscriptionId() int32 {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return 0
+}
+
+func (x *NotificationDelivery) GetSubscriptionName() string {
+	if x != nil {
+		return x.SubscriptionName
+	}
+	return ""
+}
+
+func (x *NotificationDelivery) GetDispatcherId() int32 {
+	if x != nil {
+		return x.DispatcherId
+	}
+	return 0
+}
+
+func (x *NotificationDelivery) GetDispatcherName() string {
+	if x != nil {
+		return x.DispatcherName
+	}
+	return ""
+}
+
+func (x *NotificationDelivery) GetDispatcherType() string {
+	if x != nil {
+		return x.DispatcherType
+	}
+	return ""
+}
+
+func (x *NotificationDelivery) GetContainerId() string {
+	if x != nil {
+		return x.ContainerId
+	}
+	return ""
+}
+
+func (x *NotificationDelivery) GetContainerName() string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return ""
+}
+
+func (x *NotificationDelivery) GetHostId() string {
+	if x != nil {
+		return x.HostId
+	}
+	return ""
+}
+
+func (x *NotificationDelivery) GetHostName() string {
+	if x != nil {
+		return x.HostName
+	}
+	return ""
+}
+
+func (x *NotificationDelivery) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *NotificationDelivery) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *NotificationDelivery) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *NotificationDelivery) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *NotificationDelivery) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 var File_types_proto protoreflect.FileDescriptor
 
 const file_types_proto_rawDesc = "" +
@@ -1452,7 +1884,7 @@ const file_types_proto_rawDesc = "" +
 	"\tavailable\x18\x05 \x01(\bR\tavailable\x12<\n" +
 	"\vlastChecked\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vlastChecked\"'\n" +
 	"\vLogFragment\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"\x88\x02\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\xce\x02\n" +
 	"\bLogEvent\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\rR\x02id\x12 \n" +
 	"\vcontainerId\x18\x02 \x01(\tR\vcontainerId\x12.\n" +
@@ -1463,7 +1895,10 @@ const file_types_proto_rawDesc = "" +
 	"\x04type\x18\a \x01(\tR\x04type\x12\x1e\n" +
 	"\n" +
 	"rawMessage\x18\b \x01(\tR\n" +
-	"rawMessage\")\n" +
+	"rawMessage\x12\x18\n" +
+	"\asummary\x18\t \x01(\tR\asummary\x12*\n" +
+	"\x10messageTimestamp\x18\n" +
+	" \x01(\x03R\x10messageTimestamp\")\n" +
 	"\rSingleMessage\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\"C\n" +
 	"\fGroupMessage\x123\n" +
@@ -1497,7 +1932,7 @@ const file_types_proto_rawDesc = "" +
 	"\aruntime\x18\r \x01(\tR\aruntime\x1a9\n" +
 	"\vLabelsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xea\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa8\x03\n" +
 	"\x18NotificationSubscription\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
@@ -1509,14 +1944,31 @@ const file_types_proto_rawDesc = "" +
 	"\bcooldown\x18\b \x01(\x05R\bcooldown\x12\"\n" +
 	"\fsampleWindow\x18\t \x01(\x05R\fsampleWindow\x12(\n" +
 	"\x0feventExpression\x18\n" +
-	" \x01(\tR\x0feventExpression\"\x95\x02\n" +
+	" \x01(\tR\x0feventExpression\x12\x18\n" +
+	"\agroupBy\x18\v \x01(\tR\agroupBy\x12\"\n" +
+	"\fdigestWindow\x18\f \x01(\x05R\fdigestWindow\"\x8b\x05\n" +
 	"\x16NotificationDispatcher\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
 	"\x04type\x18\x03 \x01(\tR\x04type\x12\x10\n" +
 	"\x03url\x18\x04 \x01(\tR\x03url\x12\x1a\n" +
 	"\btemplate\x18\x05 \x01(\tR\btemplate\x12G\n" +
-	"\aheaders\x18\x06 \x03(\v2-.protobuf.NotificationDispatcher.HeadersEntryR\aheaders\x1a:\n" +
+	"\aheaders\x18\x06 \x03(\v2-.protobuf.NotificationDispatcher.HeadersEntryR\aheaders\x12\x18\n" +
+	"\achannel\x18\n" +
+	" \x01(\tR\achannel\x12\x14\n" +
+	"\x05topic\x18\v \x01(\tR\x05topic\x12\x1a\n" +
+	"\bpriority\x18\f \x01(\tR\bpriority\x12\x14\n" +
+	"\x05token\x18\r \x01(\tR\x05token\x12\x12\n" +
+	"\x04user\x18\x0e \x01(\tR\x04user\x12\x1c\n" +
+	"\tdozzleUrl\x18\x0f \x01(\tR\tdozzleUrl\x12\x1a\n" +
+	"\bsmtpHost\x18\x10 \x01(\tR\bsmtpHost\x12\x1a\n" +
+	"\bsmtpPort\x18\x11 \x01(\x05R\bsmtpPort\x12\"\n" +
+	"\fsmtpSecurity\x18\x12 \x01(\tR\fsmtpSecurity\x12\"\n" +
+	"\fsmtpUsername\x18\x13 \x01(\tR\fsmtpUsername\x12\"\n" +
+	"\fsmtpPassword\x18\x14 \x01(\tR\fsmtpPassword\x12\x12\n" +
+	"\x04from\x18\x15 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x16 \x03(\tR\x02to\x12\x18\n" +
+	"\asubject\x18\x17 \x01(\tR\asubject\x1a:\n" +
 	"\fHeadersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01J\x04\b\a\x10\bJ\x04\b\b\x10\tJ\x04\b\t\x10\n" +
@@ -1524,12 +1976,44 @@ const file_types_proto_rawDesc = "" +
 	"\x17NotificationCloudConfig\x12\x16\n" +
 	"\x06apiKey\x18\x01 \x01(\tR\x06apiKey\x12\x16\n" +
 	"\x06prefix\x18\x02 \x01(\tR\x06prefix\x128\n" +
-	"\texpiresAt\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\xe7\x01\n" +
+	"\texpiresAt\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\xae\x02\n" +
 	"\x1dNotificationSubscriptionStats\x12&\n" +
 	"\x0esubscriptionId\x18\x01 \x01(\x05R\x0esubscriptionId\x12\"\n" +
 	"\ftriggerCount\x18\x02 \x01(\x03R\ftriggerCount\x12D\n" +
 	"\x0flastTriggeredAt\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0flastTriggeredAt\x124\n" +
-	"\x15triggeredContainerIds\x18\x04 \x03(\tR\x15triggeredContainerIds*?\n" +
+	"\x15triggeredContainerIds\x18\x04 \x03(\tR\x15triggeredContainerIds\x12E\n" +
+	"\ffiringAlerts\x18\x05 \x03(\v2!.protobuf.NotificationFiringAlertR\ffiringAlerts\"\x89\x02\n" +
+	"\x17NotificationFiringAlert\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12 \n" +
+	"\vcontainerId\x18\x02 \x01(\tR\vcontainerId\x12$\n" +
+	"\rcontainerName\x18\x03 \x01(\tR\rcontainerName\x12\x14\n" +
+	"\x05image\x18\x04 \x01(\tR\x05image\x12\x16\n" +
+	"\x06hostId\x18\x05 \x01(\tR\x06hostId\x12\x1a\n" +
+	"\bhostName\x18\x06 \x01(\tR\bhostName\x12\x16\n" +
+	"\x06detail\x18\a \x01(\tR\x06detail\x120\n" +
+	"\x05since\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x05since\"\xe6\x04\n" +
+	"\x14NotificationDelivery\x12&\n" +
+	"\x0enotificationId\x18\x01 \x01(\tR\x0enotificationId\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12&\n" +
+	"\x0esubscriptionId\x18\x05 \x01(\x05R\x0esubscriptionId\x12*\n" +
+	"\x10subscriptionName\x18\x06 \x01(\tR\x10subscriptionName\x12\"\n" +
+	"\fdispatcherId\x18\a \x01(\x05R\fdispatcherId\x12&\n" +
+	"\x0edispatcherName\x18\b \x01(\tR\x0edispatcherName\x12&\n" +
+	"\x0edispatcherType\x18\t \x01(\tR\x0edispatcherType\x12 \n" +
+	"\vcontainerId\x18\n" +
+	" \x01(\tR\vcontainerId\x12$\n" +
+	"\rcontainerName\x18\v \x01(\tR\rcontainerName\x12\x16\n" +
+	"\x06hostId\x18\f \x01(\tR\x06hostId\x12\x1a\n" +
+	"\bhostName\x18\r \x01(\tR\bhostName\x12\x18\n" +
+	"\asummary\x18\x0e \x01(\tR\asummary\x12\x18\n" +
+	"\asuccess\x18\x0f \x01(\bR\asuccess\x12\x1e\n" +
+	"\n" +
+	"statusCode\x18\x10 \x01(\x05R\n" +
+	"statusCode\x12\x1c\n" +
+	"\tlatencyMs\x18\x11 \x01(\x03R\tlatencyMs\x12\x14\n" +
+	"\x05error\x18\x12 \x01(\tR\x05error*?\n" +
 	"\x0fContainerAction\x12\t\n" +
 	"\x05Start\x10\x00\x12\b\n" +
 	"\x04Stop\x10\x01\x12\v\n" +
@@ -1550,7 +2034,7 @@ func file_types_proto_rawDescGZIP() []byte {
 }
 
 var file_types_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_types_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_types_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_types_proto_goTypes = []any{
 	(ContainerAction)(0),                  // 0: protobuf.ContainerAction
 	(*Container)(nil),                     // 1: protobuf.Container
@@ -1568,37 +2052,42 @@ var file_types_proto_goTypes = []any{
 	(*NotificationDispatcher)(nil),        // 13: protobuf.NotificationDispatcher
 	(*NotificationCloudConfig)(nil),       // 14: protobuf.NotificationCloudConfig
 	(*NotificationSubscriptionStats)(nil), // 15: protobuf.NotificationSubscriptionStats
-	nil,                                   // 16: protobuf.Container.LabelsEntry
-	nil,                                   // 17: protobuf.ContainerEvent.ActorAttributesEntry
-	nil,                                   // 18: protobuf.Host.LabelsEntry
-	nil,                                   // 19: protobuf.NotificationDispatcher.HeadersEntry
-	(*timestamppb.Timestamp)(nil),         // 20: google.protobuf.Timestamp
-	(*anypb.Any)(nil),                     // 21: google.protobuf.Any
+	(*NotificationFiringAlert)(nil),       // 16: protobuf.NotificationFiringAlert
+	(*NotificationDelivery)(nil),          // 17: protobuf.NotificationDelivery
+	nil,                                   // 18: protobuf.Container.LabelsEntry
+	nil,                                   // 19: protobuf.ContainerEvent.ActorAttributesEntry
+	nil,                                   // 20: protobuf.Host.LabelsEntry
+	nil,                                   // 21: protobuf.NotificationDispatcher.HeadersEntry
+	(*timestamppb.Timestamp)(nil),         // 22: google.protobuf.Timestamp
+	(*anypb.Any)(nil),                     // 23: google.protobuf.Any
 }
 var file_types_proto_depIdxs = []int32{
-	20, // 0: protobuf.Container.created:type_name -> google.protobuf.Timestamp
-	20, // 1: protobuf.Container.started:type_name -> google.protobuf.Timestamp
-	16, // 2: protobuf.Container.labels:type_name -> protobuf.Container.LabelsEntry
+	22, // 0: protobuf.Container.created:type_name -> google.protobuf.Timestamp
+	22, // 1: protobuf.Container.started:type_name -> google.protobuf.Timestamp
+	18, // 2: protobuf.Container.labels:type_name -> protobuf.Container.LabelsEntry
 	2,  // 3: protobuf.Container.stats:type_name -> protobuf.ContainerStat
-	20, // 4: protobuf.Container.finished:type_name -> google.protobuf.Timestamp
+	22, // 4: protobuf.Container.finished:type_name -> google.protobuf.Timestamp
 	4,  // 5: protobuf.Container.mountStats:type_name -> protobuf.MountStat
 	3,  // 6: protobuf.Container.mounts:type_name -> protobuf.Mount
-	20, // 7: protobuf.MountStat.lastChecked:type_name -> google.protobuf.Timestamp
-	21, // 8: protobuf.LogEvent.message:type_name -> google.protobuf.Any
-	20, // 9: protobuf.LogEvent.timestamp:type_name -> google.protobuf.Timestamp
+	22, // 7: protobuf.MountStat.lastChecked:type_name -> google.protobuf.Timestamp
+	23, // 8: protobuf.LogEvent.message:type_name -> google.protobuf.Any
+	22, // 9: protobuf.LogEvent.timestamp:type_name -> google.protobuf.Timestamp
 	5,  // 10: protobuf.GroupMessage.fragments:type_name -> protobuf.LogFragment
-	20, // 11: protobuf.ContainerEvent.timestamp:type_name -> google.protobuf.Timestamp
-	17, // 12: protobuf.ContainerEvent.actorAttributes:type_name -> protobuf.ContainerEvent.ActorAttributesEntry
+	22, // 11: protobuf.ContainerEvent.timestamp:type_name -> google.protobuf.Timestamp
+	19, // 12: protobuf.ContainerEvent.actorAttributes:type_name -> protobuf.ContainerEvent.ActorAttributesEntry
 	1,  // 13: protobuf.ContainerEvent.container:type_name -> protobuf.Container
-	18, // 14: protobuf.Host.labels:type_name -> protobuf.Host.LabelsEntry
-	19, // 15: protobuf.NotificationDispatcher.headers:type_name -> protobuf.NotificationDispatcher.HeadersEntry
-	20, // 16: protobuf.NotificationCloudConfig.expiresAt:type_name -> google.protobuf.Timestamp
-	20, // 17: protobuf.NotificationSubscriptionStats.lastTriggeredAt:type_name -> google.protobuf.Timestamp
-	18, // [18:18] is the sub-list for method output_type
-	18, // [18:18] is the sub-list for method input_type
-	18, // [18:18] is the sub-list for extension type_name
-	18, // [18:18] is the sub-list for extension extendee
-	0,  // [0:18] is the sub-list for field type_name
+	20, // 14: protobuf.Host.labels:type_name -> protobuf.Host.LabelsEntry
+	21, // 15: protobuf.NotificationDispatcher.headers:type_name -> protobuf.NotificationDispatcher.HeadersEntry
+	22, // 16: protobuf.NotificationCloudConfig.expiresAt:type_name -> google.protobuf.Timestamp
+	22, // 17: protobuf.NotificationSubscriptionStats.lastTriggeredAt:type_name -> google.protobuf.Timestamp
+	16, // 18: protobuf.NotificationSubscriptionStats.firingAlerts:type_name -> protobuf.NotificationFiringAlert
+	22, // 19: protobuf.NotificationFiringAlert.since:type_name -> google.protobuf.Timestamp
+	22, // 20: protobuf.NotificationDelivery.time:type_name -> google.protobuf.Timestamp
+	21, // [21:21] is the sub-list for method output_type
+	21, // [21:21] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_types_proto_init() }
@@ -1612,7 +2101,7 @@ func file_types_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_types_proto_rawDesc), len(file_types_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   19,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -34,6 +34,7 @@ type NotificationConfigHandler interface {
 	SetCloudDispatcher(d dispatcher.Dispatcher)
 	ClearCloudDispatcher()
 	GetNotificationStats() []types.SubscriptionStats
+	Deliveries() []types.NotificationDelivery
 }
 
 // ClientService is the interface for container operations used by the agent server
@@ -461,6 +462,8 @@ func (s *server) UpdateNotificationConfig(ctx context.Context, req *pb.UpdateNot
 			Cooldown:            int(sub.Cooldown),
 			SampleWindow:        int(sub.SampleWindow),
 			EventExpression:     sub.EventExpression,
+			GroupBy:             sub.GroupBy,
+			DigestWindow:        int(sub.DigestWindow),
 		}
 	}
 
@@ -468,12 +471,26 @@ func (s *server) UpdateNotificationConfig(ctx context.Context, req *pb.UpdateNot
 	dispatchers := make([]types.DispatcherConfig, len(req.Dispatchers))
 	for i, d := range req.Dispatchers {
 		dispatchers[i] = types.DispatcherConfig{
-			ID:       int(d.Id),
-			Name:     d.Name,
-			Type:     d.Type,
-			URL:      d.Url,
-			Template: d.Template,
-			Headers:  d.Headers,
+			ID:           int(d.Id),
+			Name:         d.Name,
+			Type:         d.Type,
+			URL:          d.Url,
+			Template:     d.Template,
+			Headers:      d.Headers,
+			Channel:      d.Channel,
+			Topic:        d.Topic,
+			Priority:     d.Priority,
+			Token:        d.Token,
+			User:         d.User,
+			DozzleURL:    d.DozzleUrl,
+			SMTPHost:     d.SmtpHost,
+			SMTPPort:     int(d.SmtpPort),
+			SMTPSecurity: d.SmtpSecurity,
+			SMTPUsername: d.SmtpUsername,
+			SMTPPassword: d.SmtpPassword,
+			From:         d.From,
+			To:           d.To,
+			Subject:      d.Subject,
 		}
 	}
 
@@ -521,12 +538,54 @@ func (s *server) GetNotificationStats(ctx context.Context, req *pb.GetNotificati
 		if s.LastTriggeredAt != nil {
 			pbStat.LastTriggeredAt = timestamppb.New(*s.LastTriggeredAt)
 		}
+		for _, alert := range s.FiringAlerts {
+			pbStat.FiringAlerts = append(pbStat.FiringAlerts, &pb.NotificationFiringAlert{
+				Type:          string(alert.Type),
+				ContainerId:   alert.Container.ID,
+				ContainerName: alert.Container.Name,
+				Image:         alert.Container.Image,
+				HostId:        alert.Container.HostID,
+				HostName:      alert.Container.HostName,
+				Detail:        alert.Detail,
+				Since:         timestamppb.New(alert.Since),
+			})
+		}
 		pbStats[i] = pbStat
 	}
 
 	return &pb.GetNotificationStatsResponse{Stats: pbStats}, nil
 }
 
+func (s *server) GetNotificationDeliveries(ctx context.Context, req *pb.GetNotificationDeliveriesRequest) (*pb.GetNotificationDeliveriesResponse, error) {
+	deliveries := s.notificationConfigHandler.Deliveries()
+
+	pbDeliveries := make([]*pb.NotificationDelivery, len(deliveries))
+	for i, d := range deliveries {
+		pbDeliveries[i] = &pb.NotificationDelivery{
+			NotificationId:   d.NotificationID,
+			Time:             timestamppb.New(d.Time),
+			Type:             string(d.Type),
+			Status:           string(d.Status),
+			SubscriptionId:   int32(d.SubscriptionID),
+			SubscriptionName: d.SubscriptionName,
+			DispatcherId:     int32(d.DispatcherID),
+			DispatcherName:   d.DispatcherName,
+			DispatcherType:   d.DispatcherType,
+			ContainerId:      d.ContainerID,
+			ContainerName:    d.ContainerName,
+			HostId:           d.HostID,
+			HostName:         d.HostName,
+			Summary:          d.Summary,
+			Success:          d.Success,
+			StatusCode:       int32(d.StatusCode),
+			LatencyMs:        d.LatencyMs,
+			Error:            d.Error,
+		}
+	}
+
+	return &pb.GetNotificationDeliveriesResponse{Deliveries: pbDeliveries}, nil
+}
+
 func NewServer(service ClientService, certificates tls.Certificate, dozzleVersion string, notificationHandler NotificationConfigHandler) (*grpc.Server, error) {
 	caCertPool := x509.NewCertPool()
 	c, err := x509.ParseCertificate(certificates.Certificate[0])
@@ -595,14 +654,16 @@ func logEventToPb(event *container.LogEvent) *pb.LogEvent {
 	}
 
 	return &pb.LogEvent{
-		Message:     message,
-		Timestamp:   timestamppb.New(time.Unix(event.Timestamp, 0)),
-		Id:          event.Id,
-		ContainerId: event.ContainerID,
-		Level:       event.Level,
-		Stream:      event.Stream,
-		Type:        string(event.Type),
-		RawMessage:  string(event.RawMessage),
+		Message:          message,
+		Timestamp:        timestamppb.New(time.Unix(event.Timestamp, 0)),
+		Id:               event.Id,
+		ContainerId:      event.ContainerID,
+		Level:            event.Level,
+		Stream:           event.Stream,
+		Type:             string(event.Type),
+		RawMessage:       string(event.RawMessage),
+		Summary:          event.Summary,
+		MessageTimestamp: event.MessageTimestamp,
 	}
 }
 
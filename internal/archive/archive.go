@@ -0,0 +1,158 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/notification"
+	container_support "github.com/amir20/dozzle/internal/support/container"
+	"github.com/rs/zerolog/log"
+)
+
+// pruneInterval is how often retention limits are enforced.
+const pruneInterval = 5 * time.Minute
+
+// Archive records the logs of matching containers into a Store and serves the
+// containers that have since been removed.
+type Archive struct {
+	store    *Store
+	index    *Index
+	config   *Config
+	listener *notification.ContainerLogListener
+	ctx      context.Context
+}
+
+// Start opens the store in dir and begins archiving the logs of the containers
+// of clients that match config. The search index is kept in indexDir.
+func Start(ctx context.Context, config *Config, dir string, indexDir string, clients []container_support.ClientService) (*Archive, error) {
+	store, err := NewStore(dir, config)
+	if err != nil {
+		return nil, err
+	}
+
+	var index *Index
+	if config.Index {
+		if index, err = NewIndex(indexDir, store, config.Retention()); err != nil {
+			store.Close()
+			return nil, err
+		}
+	}
+
+	listener := notification.NewContainerLogListener(ctx, clients)
+	listener.DisableRateLimit()
+	a := &Archive{
+		store:    store,
+		index:    index,
+		config:   config,
+		listener: listener,
+		ctx:      ctx,
+	}
+
+	a.prune(time.Now())
+	if err := listener.Start(a); err != nil {
+		a.close()
+		return nil, err
+	}
+	go a.run()
+
+	log.Info().Str("path", dir).Str("maxAge", config.Retention().String()).Uint64("maxSize", config.maxSize).Msg("Archiving container logs")
+	return a, nil
+}
+
+// ShouldListenToContainer implements notification.ContainerMatcher.
+func (a *Archive) ShouldListenToContainer(c container.Container) bool {
+	return a.config.Matches(c)
+}
+
+func (a *Archive) run() {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	defer a.close()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case event := <-a.listener.LogChannel():
+			a.record(event)
+		case now := <-ticker.C:
+			a.prune(now)
+		}
+	}
+}
+
+func (a *Archive) prune(now time.Time) {
+	a.store.Prune(now)
+	if a.index != nil {
+		a.index.Prune(now)
+	}
+}
+
+func (a *Archive) close() {
+	if a.index != nil {
+		a.index.Close()
+	}
+	a.store.Close()
+}
+
+func (a *Archive) record(event *container.LogEvent) {
+	// The lookup is cached by the listener. It fails once the container is
+	// gone, and the metadata tracked last is kept.
+	if c, host, err := a.listener.FindContainerWithHost(a.ctx, event.ContainerID, nil); err == nil {
+		if err := a.store.Track(c, host); err != nil {
+			log.Error().Err(err).Str("container", c.ID).Msg("Could not archive container")
+			return
+		}
+	}
+
+	location, err := a.store.Append(event)
+	if err != nil {
+		if !errors.Is(err, ErrNotArchived) {
+			log.Error().Err(err).Str("container", event.ContainerID).Msg("Could not archive log")
+		}
+		return
+	}
+
+	if a.index != nil {
+		if err := a.index.Add(event, location, time.Now()); err != nil {
+			log.Error().Err(err).Str("container", event.ContainerID).Msg("Could not index log")
+		}
+	}
+}
+
+// Search finds archived lines with the index.
+func (a *Archive) Search(ctx context.Context, query Query) (SearchResult, error) {
+	if a.index == nil {
+		return SearchResult{}, ErrIndexDisabled
+	}
+	return a.index.Search(ctx, query)
+}
+
+// FindContainer returns an archived container that can only read its logs.
+func (a *Archive) FindContainer(host string, id string, labels container.ContainerLabels) (*container_support.ContainerService, error) {
+	_, h, ok := a.store.Find(host, id)
+	if !ok {
+		return nil, container.ErrContainerNotFound
+	}
+
+	client := &archiveClient{store: a.store, host: h}
+	c, err := client.FindContainer(a.ctx, id, labels)
+	if err != nil {
+		return nil, err
+	}
+	return container_support.NewContainerService(client, c), nil
+}
+
+// Containers returns the archived containers visible with labels.
+func (a *Archive) Containers(labels container.ContainerLabels) []container.Container {
+	containers := make([]container.Container, 0)
+	for _, c := range a.store.Containers() {
+		if matchesLabels(c, labels) {
+			c.State = removedState
+			containers = append(containers, c)
+		}
+	}
+	return containers
+}
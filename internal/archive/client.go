@@ -0,0 +1,182 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/rs/zerolog/log"
+)
+
+// removedState is the state of archived containers, as reported for removed
+// containers in the UI.
+const removedState = "deleted"
+
+var ErrRemoved = errors.New("container has been removed, only its archived logs are available")
+
+// archiveClient serves the archived containers of a single host. It
+// implements container_support.ClientService with every action failing.
+type archiveClient struct {
+	store *Store
+	host  container.Host
+}
+
+func (a *archiveClient) FindContainer(ctx context.Context, id string, labels container.ContainerLabels) (container.Container, error) {
+	c, _, ok := a.store.Find(a.host.ID, id)
+	if !ok || !matchesLabels(c, labels) {
+		return container.Container{}, container.ErrContainerNotFound
+	}
+	c.State = removedState
+	return c, nil
+}
+
+func (a *archiveClient) ListContainers(ctx context.Context, labels container.ContainerLabels) ([]container.Container, error) {
+	var result []container.Container
+	for _, c := range a.store.Containers() {
+		if c.Host == a.host.ID && matchesLabels(c, labels) {
+			c.State = removedState
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (a *archiveClient) Host(ctx context.Context) (container.Host, error) {
+	return a.host, nil
+}
+
+func (a *archiveClient) ContainerAction(ctx context.Context, c container.Container, action container.ContainerAction) error {
+	return ErrRemoved
+}
+
+func (a *archiveClient) UpdateContainer(ctx context.Context, c container.Container, progressCh chan<- container.UpdateProgress) (bool, error) {
+	return false, ErrRemoved
+}
+
+func (a *archiveClient) LogsBetweenDates(ctx context.Context, c container.Container, from time.Time, to time.Time, stdTypes container.StdType) (<-chan *container.LogEvent, error) {
+	if _, _, ok := a.store.Find(a.host.ID, c.ID); !ok {
+		return nil, ErrNotArchived
+	}
+
+	events := make(chan *container.LogEvent)
+	go func() {
+		if err := a.store.Events(ctx, c.ID, from, to, stdTypes, events); err != nil && !errors.Is(err, context.Canceled) {
+			log.Error().Err(err).Str("container", c.ID).Msg("error reading archived logs")
+		}
+	}()
+	return events, nil
+}
+
+// RawLogs writes the archived lines in the format of docker logs --timestamps.
+func (a *archiveClient) RawLogs(ctx context.Context, c container.Container, from time.Time, to time.Time, stdTypes container.StdType) (io.ReadCloser, error) {
+	events, err := a.LogsBetweenDates(ctx, c, from, to, stdTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	in, out := io.Pipe()
+	go func() {
+		var err error
+		for event := range events {
+			if err != nil {
+				continue
+			}
+			timestamp := time.UnixMilli(event.Timestamp).UTC().Format(time.RFC3339Nano)
+			for _, line := range rawLines(event) {
+				if _, err = fmt.Fprintf(out, "%s %s\n", timestamp, line); err != nil {
+					break
+				}
+			}
+		}
+		out.CloseWithError(err)
+	}()
+	return in, nil
+}
+
+func rawLines(event *container.LogEvent) []string {
+	switch message := event.Message.(type) {
+	case []container.LogFragment:
+		lines := make([]string, len(message))
+		for i, fragment := range message {
+			lines[i] = fragment.Message
+		}
+		return lines
+	case string:
+		if event.RawMessage == "" {
+			return []string{message}
+		}
+	}
+	return strings.Split(event.RawMessage, "\n")
+}
+
+func (a *archiveClient) SubscribeStats(ctx context.Context, stats chan<- container.ContainerStat) {
+}
+
+func (a *archiveClient) SubscribeEvents(ctx context.Context, events chan<- container.ContainerEvent) {
+}
+
+func (a *archiveClient) SubscribeContainersStarted(ctx context.Context, containers chan<- container.Container) {
+}
+
+// StreamLogs sends the archived logs since from and ends with io.EOF, like the
+// stream of a container that stopped.
+func (a *archiveClient) StreamLogs(ctx context.Context, c container.Container, from time.Time, stdTypes container.StdType, events chan<- *container.LogEvent) error {
+	archived, err := a.LogsBetweenDates(ctx, c, from, time.Now(), stdTypes)
+	if err != nil {
+		return err
+	}
+	for event := range archived {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return io.EOF
+}
+
+func (a *archiveClient) Attach(ctx context.Context, c container.Container, events container.ExecEventReader, stdout io.Writer) error {
+	return ErrRemoved
+}
+
+func (a *archiveClient) Exec(ctx context.Context, c container.Container, cmd []string, events container.ExecEventReader, stdout io.Writer) error {
+	return ErrRemoved
+}
+
+// matchesLabels applies the label and name filters of a user to an archived
+// container. Other filter keys need the Docker API and never match.
+func matchesLabels(c container.Container, labels container.ContainerLabels) bool {
+	for key, values := range labels {
+		switch key {
+		case "label":
+			for _, value := range values {
+				name, expected, hasValue := strings.Cut(value, "=")
+				actual, ok := c.Labels[name]
+				if !ok || (hasValue && actual != expected) {
+					return false
+				}
+			}
+		case "name":
+			matched := false
+			for _, value := range values {
+				if strings.Contains(c.Name, value) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+// Package archive keeps the logs of selected containers on disk so they stay
+// readable after the container is removed.
+package archive
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/notification"
+	store_support "github.com/amir20/dozzle/internal/support/store"
+	"github.com/amir20/dozzle/types"
+	"github.com/dustin/go-humanize"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DefaultConfigPath = "./data/archive.yml"
+	DefaultDirectory  = "./data/archive"
+
+	// archiveLabel opts a container in or out of the archive regardless of
+	// the rules of archive.yml.
+	archiveLabel = "dev.dozzle.archive"
+
+	defaultMaxAge  = 7 * 24 * time.Hour
+	defaultMaxSize = 1 << 30
+)
+
+var spec = store_support.Spec{Name: "archive", DefaultMaxAge: defaultMaxAge}
+
+// Config is the on-disk format of archive.yml. A container is archived when
+// it carries all of Labels or matches ContainerExpression. Index enables full
+// text search over the archived lines.
+type Config struct {
+	store_support.Config `yaml:",inline"`
+
+	Labels              map[string]string `yaml:"labels,omitempty"`
+	ContainerExpression string            `yaml:"containerExpression,omitempty"`
+	MaxSize             string            `yaml:"maxSize,omitempty"`
+	Index               bool              `yaml:"index,omitempty"`
+
+	program *vm.Program
+	maxSize uint64
+}
+
+// LoadConfig parses and validates archive.yml.
+func LoadConfig(r io.Reader) (*Config, error) {
+	var config Config
+	if err := spec.Decode(r, &config); err != nil {
+		return nil, err
+	}
+
+	config.maxSize = defaultMaxSize
+	if config.MaxSize != "" {
+		maxSize, err := humanize.ParseBytes(config.MaxSize)
+		if err != nil || maxSize == 0 {
+			return nil, fmt.Errorf("invalid maxSize %q", config.MaxSize)
+		}
+		config.maxSize = maxSize
+	}
+
+	if config.ContainerExpression != "" {
+		program, err := expr.Compile(config.ContainerExpression, expr.Env(types.NotificationContainer{}), expr.AsBool())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile container expression: %w", err)
+		}
+		config.program = program
+	}
+
+	return &config, nil
+}
+
+// LoadConfigFile reads the config at path. It returns nil when the file does
+// not exist or is invalid, which leaves the archive disabled.
+func LoadConfigFile(path string) *Config {
+	return store_support.LoadFile(path, spec.Name, LoadConfig)
+}
+
+// Matches reports whether the logs of c should be archived. The
+// dev.dozzle.archive label takes precedence over the configured rules.
+func (c *Config) Matches(item container.Container) bool {
+	if value := strings.TrimSpace(item.Labels[archiveLabel]); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err == nil {
+			return enabled
+		}
+		log.Warn().Str("label", archiveLabel).Str("value", value).Msg("invalid archive label")
+	}
+
+	if len(c.Labels) > 0 {
+		matches := true
+		for key, value := range c.Labels {
+			if item.Labels[key] != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+
+	if c.program != nil {
+		result, err := expr.Run(c.program, notification.FromContainerModel(item, container.Host{}))
+		if err != nil {
+			log.Warn().Err(err).Str("expression", c.ContainerExpression).Msg("archive expression evaluation error")
+			return false
+		}
+		match, ok := result.(bool)
+		return ok && match
+	}
+
+	return false
+}
@@ -0,0 +1,55 @@
+package archive
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Equal(t, defaultMaxAge, config.Retention())
+	assert.Equal(t, uint64(defaultMaxSize), config.maxSize)
+
+	config, err = LoadConfig(strings.NewReader("maxAge: 72h\nmaxSize: 500MB\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 72*time.Hour, config.Retention())
+	assert.Equal(t, uint64(500_000_000), config.maxSize)
+
+	for _, invalid := range []string{"maxAge: soon", "maxSize: lots", "containerExpression: name ==", "containerExpression: name"} {
+		_, err := LoadConfig(strings.NewReader(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestConfig_Matches(t *testing.T) {
+	config, err := LoadConfig(strings.NewReader(`
+labels:
+  com.example.keep: "true"
+containerExpression: name startsWith "db"
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		container container.Container
+		expected  bool
+	}{
+		{"labels", container.Container{Name: "web", Labels: map[string]string{"com.example.keep": "true"}}, true},
+		{"expression", container.Container{Name: "db-1"}, true},
+		{"neither", container.Container{Name: "web"}, false},
+		{"opt in label", container.Container{Name: "web", Labels: map[string]string{"dev.dozzle.archive": "true"}}, true},
+		{"opt out label", container.Container{Name: "db-1", Labels: map[string]string{"dev.dozzle.archive": "false"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, config.Matches(tt.container))
+		})
+	}
+}
@@ -0,0 +1,810 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/rs/zerolog/log"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+const (
+	DefaultIndexDirectory = "./data/index"
+
+	indexMetaFile     = "meta.json"
+	indexDocsFile     = "docs.bin"
+	indexTermsFile    = "terms.bin"
+	indexPostingsFile = "postings.bin"
+
+	// indexSegmentDuration is how long a segment receives new lines before it
+	// is sealed and its terms are written to disk.
+	indexSegmentDuration = time.Hour
+	// indexDocSize is the size of a document record: timestamp, archive
+	// segment and offset as int64 and the container as uint32.
+	indexDocSize = 28
+	// maxTermLength truncates long tokens like base64 blobs.
+	maxTermLength = 64
+
+	DefaultSearchLimit = 100
+)
+
+var (
+	ErrIndexDisabled = errors.New("log index is not enabled")
+	ErrEmptyQuery    = errors.New("query has no searchable terms")
+)
+
+type indexMeta struct {
+	Containers   []string `json:"containers"`
+	MinTimestamp int64    `json:"minTimestamp"`
+	MaxTimestamp int64    `json:"maxTimestamp"`
+	Sealed       bool     `json:"sealed"`
+}
+
+type postingRef struct {
+	offset int64
+	length int64
+}
+
+// indexSegment holds the lines archived during one indexSegmentDuration. The
+// active segment keeps its postings in memory; sealed segments read them from
+// disk, binary searching a sorted term list.
+type indexSegment struct {
+	dir        string
+	start      time.Time
+	meta       indexMeta
+	count      uint32
+	containers map[string]uint32
+
+	docs     *os.File
+	postings map[string][]uint32
+
+	terms []string
+	refs  []postingRef
+}
+
+// Index is an inverted index over the archived lines. Every line is indexed by
+// the lowercase words of its message and by field:value terms for its level,
+// stream and the scalar fields of structured logs.
+type Index struct {
+	dir    string
+	store  *Store
+	maxAge time.Duration
+
+	mu       sync.RWMutex
+	segments []*indexSegment // oldest first, the last one may be active
+}
+
+// NewIndex opens the index in dir. Segments that were not sealed before a
+// restart are rebuilt from the archive.
+func NewIndex(dir string, store *Store, maxAge time.Duration) (*Index, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	index := &Index{dir: dir, store: store, maxAge: maxAge}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		start, err := strconv.ParseInt(file.Name(), 10, 64)
+		if !file.IsDir() || err != nil {
+			continue
+		}
+		seg, err := index.openSegment(filepath.Join(dir, file.Name()), time.Unix(start, 0))
+		if err != nil {
+			log.Warn().Err(err).Str("path", file.Name()).Msg("Skipping unreadable index segment")
+			continue
+		}
+		index.segments = append(index.segments, seg)
+	}
+	slices.SortFunc(index.segments, func(a, b *indexSegment) int {
+		return a.start.Compare(b.start)
+	})
+	return index, nil
+}
+
+func (i *Index) openSegment(dir string, start time.Time) (*indexSegment, error) {
+	seg := &indexSegment{dir: dir, start: start}
+	data, err := os.ReadFile(filepath.Join(dir, indexMetaFile))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &seg.meta); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(filepath.Join(dir, indexDocsFile))
+	if err != nil {
+		return nil, err
+	}
+	seg.count = uint32(info.Size() / indexDocSize)
+
+	if seg.meta.Sealed {
+		return seg, seg.loadTerms()
+	}
+
+	log.Debug().Str("path", dir).Msg("Rebuilding index segment")
+	seg.postings = make(map[string][]uint32)
+	docs, err := os.ReadFile(filepath.Join(dir, indexDocsFile))
+	if err != nil {
+		return nil, err
+	}
+	for n := range seg.count {
+		id, location, timestamp, err := seg.decodeDoc(docs[n*indexDocSize : (n+1)*indexDocSize])
+		if err != nil {
+			continue
+		}
+		if n == 0 || timestamp < seg.meta.MinTimestamp {
+			seg.meta.MinTimestamp = timestamp
+		}
+		seg.meta.MaxTimestamp = max(seg.meta.MaxTimestamp, timestamp)
+		event, err := i.store.Read(id, location)
+		if err != nil {
+			continue
+		}
+		for _, term := range eventTerms(event) {
+			seg.postings[term] = append(seg.postings[term], n)
+		}
+	}
+	return seg, seg.seal()
+}
+
+func (s *indexSegment) decodeDoc(record []byte) (string, Location, int64, error) {
+	timestamp := int64(binary.LittleEndian.Uint64(record[0:8]))
+	location := Location{
+		Segment: int64(binary.LittleEndian.Uint64(record[8:16])),
+		Offset:  int64(binary.LittleEndian.Uint64(record[16:24])),
+	}
+	containerIndex := binary.LittleEndian.Uint32(record[24:28])
+	if int(containerIndex) >= len(s.meta.Containers) {
+		return "", Location{}, 0, fmt.Errorf("unknown container %d", containerIndex)
+	}
+	return s.meta.Containers[containerIndex], location, timestamp, nil
+}
+
+func (s *indexSegment) writeMeta() error {
+	data, err := json.Marshal(s.meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.dir, indexMetaFile), data, 0644)
+}
+
+// seal writes the postings of an active segment to disk.
+func (s *indexSegment) seal() error {
+	if s.docs != nil {
+		s.docs.Close()
+		s.docs = nil
+	}
+
+	terms := make([]string, 0, len(s.postings))
+	for term := range s.postings {
+		terms = append(terms, term)
+	}
+	slices.Sort(terms)
+
+	var postings, dictionary bytes.Buffer
+	refs := make([]postingRef, len(terms))
+	for n, term := range terms {
+		offset := int64(postings.Len())
+		previous := uint32(0)
+		for _, doc := range s.postings[term] {
+			postings.Write(binary.AppendUvarint(nil, uint64(doc-previous)))
+			previous = doc
+		}
+		refs[n] = postingRef{offset: offset, length: int64(postings.Len()) - offset}
+
+		dictionary.Write(binary.AppendUvarint(nil, uint64(len(term))))
+		dictionary.WriteString(term)
+		dictionary.Write(binary.AppendUvarint(nil, uint64(refs[n].offset)))
+		dictionary.Write(binary.AppendUvarint(nil, uint64(refs[n].length)))
+	}
+
+	if err := os.WriteFile(filepath.Join(s.dir, indexPostingsFile), postings.Bytes(), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, indexTermsFile), dictionary.Bytes(), 0644); err != nil {
+		return err
+	}
+	s.meta.Sealed = true
+	if err := s.writeMeta(); err != nil {
+		return err
+	}
+
+	s.terms, s.refs, s.postings, s.containers = terms, refs, nil, nil
+	return nil
+}
+
+func (s *indexSegment) loadTerms() error {
+	data, err := os.ReadFile(filepath.Join(s.dir, indexTermsFile))
+	if err != nil {
+		return err
+	}
+	reader := bytes.NewReader(data)
+	for reader.Len() > 0 {
+		length, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return err
+		}
+		term := make([]byte, length)
+		if _, err := io.ReadFull(reader, term); err != nil {
+			return err
+		}
+		offset, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return err
+		}
+		size, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return err
+		}
+		s.terms = append(s.terms, string(term))
+		s.refs = append(s.refs, postingRef{offset: int64(offset), length: int64(size)})
+	}
+	return nil
+}
+
+// lookup returns the ascending document numbers of term. It must be called
+// with the index lock held.
+func (s *indexSegment) lookup(term string) ([]uint32, error) {
+	if !s.meta.Sealed {
+		return slices.Clone(s.postings[term]), nil
+	}
+
+	n, found := slices.BinarySearch(s.terms, term)
+	if !found {
+		return nil, nil
+	}
+	file, err := os.Open(filepath.Join(s.dir, indexPostingsFile))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(io.NewSectionReader(file, s.refs[n].offset, s.refs[n].length))
+	var docs []uint32
+	previous := uint64(0)
+	for {
+		delta, err := binary.ReadUvarint(reader)
+		if err == io.EOF {
+			return docs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		previous += delta
+		docs = append(docs, uint32(previous))
+	}
+}
+
+// Add indexes an event archived at location.
+func (i *Index) Add(event *container.LogEvent, location Location, now time.Time) error {
+	terms := eventTerms(event)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	seg, err := i.active(now)
+	if err != nil {
+		return err
+	}
+
+	containerIndex, ok := seg.containers[event.ContainerID]
+	if !ok {
+		containerIndex = uint32(len(seg.meta.Containers))
+		seg.containers[event.ContainerID] = containerIndex
+		seg.meta.Containers = append(seg.meta.Containers, event.ContainerID)
+		if err := seg.writeMeta(); err != nil {
+			return err
+		}
+	}
+
+	record := make([]byte, indexDocSize)
+	binary.LittleEndian.PutUint64(record[0:8], uint64(event.Timestamp))
+	binary.LittleEndian.PutUint64(record[8:16], uint64(location.Segment))
+	binary.LittleEndian.PutUint64(record[16:24], uint64(location.Offset))
+	binary.LittleEndian.PutUint32(record[24:28], containerIndex)
+	if _, err := seg.docs.Write(record); err != nil {
+		return err
+	}
+
+	if seg.count == 0 || event.Timestamp < seg.meta.MinTimestamp {
+		seg.meta.MinTimestamp = event.Timestamp
+	}
+	seg.meta.MaxTimestamp = max(seg.meta.MaxTimestamp, event.Timestamp)
+	for _, term := range terms {
+		seg.postings[term] = append(seg.postings[term], seg.count)
+	}
+	seg.count++
+	return nil
+}
+
+// active returns the segment receiving lines at now, sealing the previous one.
+func (i *Index) active(now time.Time) (*indexSegment, error) {
+	if len(i.segments) > 0 {
+		last := i.segments[len(i.segments)-1]
+		if !last.meta.Sealed && now.Before(last.start.Add(indexSegmentDuration)) {
+			return last, nil
+		}
+		if !last.meta.Sealed {
+			if err := last.seal(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	start := now.Truncate(indexSegmentDuration)
+	if len(i.segments) > 0 {
+		if previous := i.segments[len(i.segments)-1].start; !start.After(previous) {
+			start = previous.Add(time.Second)
+		}
+	}
+	seg := &indexSegment{
+		dir:        filepath.Join(i.dir, strconv.FormatInt(start.Unix(), 10)),
+		start:      start,
+		containers: make(map[string]uint32),
+		postings:   make(map[string][]uint32),
+	}
+	if err := os.MkdirAll(seg.dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := seg.writeMeta(); err != nil {
+		return nil, err
+	}
+	docs, err := os.OpenFile(filepath.Join(seg.dir, indexDocsFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	seg.docs = docs
+	i.segments = append(i.segments, seg)
+	return seg, nil
+}
+
+// Prune seals the active segment once its time is over and removes the
+// segments older than the retention.
+func (i *Index) Prune(now time.Time) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if len(i.segments) > 0 {
+		last := i.segments[len(i.segments)-1]
+		if !last.meta.Sealed && !now.Before(last.start.Add(indexSegmentDuration)) {
+			if err := last.seal(); err != nil {
+				log.Error().Err(err).Str("path", last.dir).Msg("Could not seal index segment")
+			}
+		}
+	}
+
+	cutoff := now.Add(-i.maxAge)
+	i.segments = slices.DeleteFunc(i.segments, func(seg *indexSegment) bool {
+		if !seg.meta.Sealed || seg.start.Add(indexSegmentDuration).After(cutoff) {
+			return false
+		}
+		if err := os.RemoveAll(seg.dir); err != nil {
+			log.Warn().Err(err).Str("path", seg.dir).Msg("Could not remove index segment")
+			return false
+		}
+		return true
+	})
+}
+
+// Close closes the active segment without sealing it.
+func (i *Index) Close() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, seg := range i.segments {
+		if seg.docs != nil {
+			seg.docs.Close()
+			seg.docs = nil
+		}
+	}
+	return nil
+}
+
+// Query is a search over the index. Text holds words and "quoted phrases"
+// that must all appear in a line. field:value words such as level:error or
+// user.id:42 match the field exactly or appear in the line as text.
+type Query struct {
+	Text  string
+	From  time.Time
+	To    time.Time
+	Limit int
+	// Allow filters the containers a user can see. nil allows all.
+	Allow func(containerID string) bool
+}
+
+// Hit is an archived line matching a query.
+type Hit struct {
+	Host          string              `json:"host"`
+	HostName      string              `json:"hostName"`
+	ContainerID   string              `json:"containerId"`
+	ContainerName string              `json:"containerName"`
+	Timestamp     time.Time           `json:"timestamp"`
+	Event         *container.LogEvent `json:"event"`
+}
+
+// SearchResult holds the newest hits of a query.
+type SearchResult struct {
+	Hits []Hit `json:"hits"`
+	// Truncated is set when more lines matched than the limit.
+	Truncated bool `json:"truncated"`
+}
+
+// Search returns the lines matching query, newest first.
+func (i *Index) Search(ctx context.Context, query Query) (SearchResult, error) {
+	clauses := parseQuery(query.Text)
+	if !slices.ContainsFunc(clauses, queryClause.indexed) {
+		return SearchResult{}, ErrEmptyQuery
+	}
+	if query.Limit <= 0 {
+		query.Limit = DefaultSearchLimit
+	}
+
+	i.mu.RLock()
+	segments := slices.Clone(i.segments)
+	i.mu.RUnlock()
+
+	result := SearchResult{Hits: []Hit{}}
+	for _, seg := range slices.Backward(segments) {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		i.mu.RLock()
+		count, meta := seg.count, seg.meta
+		meta.Containers = slices.Clone(seg.meta.Containers)
+		if count == 0 || meta.MaxTimestamp < query.From.UnixMilli() || meta.MinTimestamp > query.To.UnixMilli() {
+			i.mu.RUnlock()
+			continue
+		}
+		docs, err := seg.match(clauses)
+		i.mu.RUnlock()
+		if err != nil {
+			return result, err
+		}
+		if len(docs) == 0 {
+			continue
+		}
+
+		hits, truncated, err := i.collect(seg.dir, meta, docs, clauses, query, query.Limit-len(result.Hits))
+		if err != nil {
+			return result, err
+		}
+		result.Hits = append(result.Hits, hits...)
+		if truncated {
+			result.Truncated = true
+			break
+		}
+	}
+
+	sort.SliceStable(result.Hits, func(a, b int) bool {
+		return result.Hits[a].Timestamp.After(result.Hits[b].Timestamp)
+	})
+	return result, nil
+}
+
+// match returns the documents holding every indexed clause.
+func (s *indexSegment) match(clauses []queryClause) ([]uint32, error) {
+	lists := make([][]uint32, 0, len(clauses))
+	for _, clause := range clauses {
+		if !clause.indexed() {
+			continue
+		}
+		docs, err := s.matchTerms(clause.tokens)
+		if err != nil {
+			return nil, err
+		}
+		if clause.field != "" {
+			fieldDocs, err := s.lookup(clause.field)
+			if err != nil {
+				return nil, err
+			}
+			docs = union(docs, fieldDocs)
+		}
+		if len(docs) == 0 {
+			return nil, nil
+		}
+		lists = append(lists, docs)
+	}
+	return intersectAll(lists), nil
+}
+
+// matchTerms intersects the postings of terms.
+func (s *indexSegment) matchTerms(terms []string) ([]uint32, error) {
+	lists := make([][]uint32, 0, len(terms))
+	for _, term := range terms {
+		docs, err := s.lookup(term)
+		if err != nil || len(docs) == 0 {
+			return nil, err
+		}
+		lists = append(lists, docs)
+	}
+	return intersectAll(lists), nil
+}
+
+// intersectAll intersects lists, shortest first.
+func intersectAll(lists [][]uint32) []uint32 {
+	if len(lists) == 0 {
+		return nil
+	}
+	slices.SortFunc(lists, func(a, b []uint32) int { return len(a) - len(b) })
+
+	result := lists[0]
+	for _, list := range lists[1:] {
+		result = intersect(result, list)
+		if len(result) == 0 {
+			break
+		}
+	}
+	return result
+}
+
+func union(a, b []uint32) []uint32 {
+	result := make([]uint32, 0, len(a)+len(b))
+	x, y := 0, 0
+	for x < len(a) && y < len(b) {
+		switch {
+		case a[x] < b[y]:
+			result = append(result, a[x])
+			x++
+		case a[x] > b[y]:
+			result = append(result, b[y])
+			y++
+		default:
+			result = append(result, a[x])
+			x++
+			y++
+		}
+	}
+	result = append(result, a[x:]...)
+	return append(result, b[y:]...)
+}
+
+func intersect(a, b []uint32) []uint32 {
+	result := make([]uint32, 0, min(len(a), len(b)))
+	for x, y := 0, 0; x < len(a) && y < len(b); {
+		switch {
+		case a[x] < b[y]:
+			x++
+		case a[x] > b[y]:
+			y++
+		default:
+			result = append(result, a[x])
+			x++
+			y++
+		}
+	}
+	return result
+}
+
+// collect reads the matching documents of a segment, newest first, and
+// verifies the clauses against the archived lines.
+func (i *Index) collect(dir string, meta indexMeta, docs []uint32, clauses []queryClause, query Query, limit int) ([]Hit, bool, error) {
+	file, err := os.Open(filepath.Join(dir, indexDocsFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	defer file.Close()
+
+	seg := &indexSegment{meta: meta}
+	record := make([]byte, indexDocSize)
+	var hits []Hit
+	for _, doc := range slices.Backward(docs) {
+		if _, err := file.ReadAt(record, int64(doc)*indexDocSize); err != nil {
+			return hits, false, err
+		}
+		id, location, timestamp, err := seg.decodeDoc(record)
+		if err != nil || timestamp < query.From.UnixMilli() || timestamp > query.To.UnixMilli() {
+			continue
+		}
+		if query.Allow != nil && !query.Allow(id) {
+			continue
+		}
+
+		event, err := i.store.Read(id, location)
+		if err != nil {
+			// removed by retention
+			continue
+		}
+		if !matchesAll(event, clauses) {
+			continue
+		}
+
+		if len(hits) == limit {
+			return hits, true, nil
+		}
+		metadata, _ := i.store.lookup(id)
+		hits = append(hits, Hit{
+			Host:          metadata.Host.ID,
+			HostName:      metadata.Host.Name,
+			ContainerID:   id,
+			ContainerName: metadata.Container.Name,
+			Timestamp:     time.UnixMilli(event.Timestamp),
+			Event:         event,
+		})
+	}
+	return hits, false, nil
+}
+
+// matchesAll reports whether event holds the phrase or the field of every
+// clause.
+func matchesAll(event *container.LogEvent, clauses []queryClause) bool {
+	text := strings.ToLower(eventText(event))
+	var terms []string
+	for _, clause := range clauses {
+		if strings.Contains(text, clause.phrase) {
+			continue
+		}
+		if clause.field == "" {
+			return false
+		}
+		if terms == nil {
+			terms = eventTerms(event)
+		}
+		if !slices.Contains(terms, clause.field) {
+			return false
+		}
+	}
+	return true
+}
+
+// queryClause is a word or quoted phrase of a query. A line matches it when it
+// contains phrase, found in the index by tokens, or when it has field. Words
+// such as level:error or user.id:42 are both, so ERROR:db still finds the
+// text.
+type queryClause struct {
+	tokens []string
+	phrase string
+	field  string
+}
+
+// indexed reports whether the clause can be looked up in the index.
+func (c queryClause) indexed() bool {
+	return len(c.tokens) > 0 || c.field != ""
+}
+
+// fieldName matches the keys of field:value words. Anything else before a
+// colon, such as 12 in 12:30, is text.
+var fieldName = regexp.MustCompile(`^[A-Za-z_@][\w.@-]*$`)
+
+// parseQuery splits text into the clauses every matching line must hold.
+func parseQuery(text string) []queryClause {
+	var clauses []queryClause
+	for n, part := range strings.Split(text, `"`) {
+		if n%2 == 1 {
+			if phrase := strings.ToLower(strings.TrimSpace(part)); phrase != "" {
+				clauses = append(clauses, queryClause{tokens: tokenize(phrase), phrase: phrase})
+			}
+			continue
+		}
+		for word := range strings.FieldsSeq(part) {
+			clause := queryClause{tokens: tokenize(word), phrase: strings.ToLower(word)}
+			if key, value, ok := strings.Cut(word, ":"); ok && value != "" && fieldName.MatchString(key) {
+				clause.field = fieldTerm(key, value)
+			}
+			clauses = append(clauses, clause)
+		}
+	}
+	return clauses
+}
+
+// eventTerms returns the distinct terms of an event.
+func eventTerms(event *container.LogEvent) []string {
+	seen := make(map[string]struct{})
+	for _, token := range tokenize(eventText(event)) {
+		seen[token] = struct{}{}
+	}
+	if event.Level != "" {
+		seen[fieldTerm("level", event.Level)] = struct{}{}
+	}
+	if event.Stream != "" {
+		seen[fieldTerm("stream", event.Stream)] = struct{}{}
+	}
+	switch data := event.Message.(type) {
+	case *orderedmap.OrderedMap[string, any]:
+		for pair := data.Oldest(); pair != nil; pair = pair.Next() {
+			addFieldTerms(seen, pair.Key, pair.Value)
+		}
+	case *orderedmap.OrderedMap[string, string]:
+		// logfmt and the access log and syslog parsers
+		for pair := data.Oldest(); pair != nil; pair = pair.Next() {
+			addFieldTerms(seen, pair.Key, pair.Value)
+		}
+	}
+
+	terms := make([]string, 0, len(seen))
+	for term := range seen {
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+func addFieldTerms(terms map[string]struct{}, key string, value any) {
+	switch value := value.(type) {
+	case *orderedmap.OrderedMap[string, any]:
+		for pair := value.Oldest(); pair != nil; pair = pair.Next() {
+			addFieldTerms(terms, key+"."+pair.Key, pair.Value)
+		}
+	case *orderedmap.OrderedMap[string, string]:
+		for pair := value.Oldest(); pair != nil; pair = pair.Next() {
+			addFieldTerms(terms, key+"."+pair.Key, pair.Value)
+		}
+	case map[string]any:
+		for k, v := range value {
+			addFieldTerms(terms, key+"."+k, v)
+		}
+	case []any:
+		for _, v := range value {
+			addFieldTerms(terms, key, v)
+		}
+	case string:
+		terms[fieldTerm(key, value)] = struct{}{}
+	case float64:
+		terms[fieldTerm(key, strconv.FormatFloat(value, 'f', -1, 64))] = struct{}{}
+	case bool:
+		terms[fieldTerm(key, strconv.FormatBool(value))] = struct{}{}
+	}
+}
+
+func fieldTerm(key, value string) string {
+	return truncateTerm(strings.ToLower(key) + ":" + strings.ToLower(value))
+}
+
+// tokenize splits text into lowercase words of letters, digits and
+// underscores.
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	for n, word := range words {
+		words[n] = truncateTerm(word)
+	}
+	return words
+}
+
+func truncateTerm(term string) string {
+	if len(term) <= maxTermLength {
+		return term
+	}
+	return strings.ToValidUTF8(term[:maxTermLength], "")
+}
+
+// eventText returns the full text of an event: the plain message, every line
+// of a group or the raw JSON of a structured log.
+func eventText(event *container.LogEvent) string {
+	switch message := event.Message.(type) {
+	case string:
+		return message
+	case []container.LogFragment:
+		lines := make([]string, len(message))
+		for n, fragment := range message {
+			lines[n] = fragment.Message
+		}
+		return strings.Join(lines, "\n")
+	}
+	if event.RawMessage != "" {
+		return event.RawMessage
+	}
+	data, _ := json.Marshal(event.Message)
+	return string(data)
+}
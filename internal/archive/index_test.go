@@ -0,0 +1,174 @@
+package archive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+func newTestIndex(t *testing.T) (*Store, *Index, string) {
+	t.Helper()
+	store := newTestStore(t, t.TempDir())
+	dir := t.TempDir()
+	index, err := NewIndex(dir, store, 24*time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { index.Close() })
+	return store, index, dir
+}
+
+func addEvent(t *testing.T, store *Store, index *Index, event *container.LogEvent, now time.Time) {
+	t.Helper()
+	location, err := store.Append(event)
+	require.NoError(t, err)
+	require.NoError(t, index.Add(event, location, now))
+}
+
+func hitMessages(result SearchResult) []any {
+	messages := make([]any, len(result.Hits))
+	for i, hit := range result.Hits {
+		messages[i] = hit.Event.Message
+	}
+	return messages
+}
+
+func TestIndex_Search(t *testing.T) {
+	store, index, _ := newTestIndex(t)
+	other := container.Container{ID: "def456", Name: "worker"}
+	require.NoError(t, store.Track(testContainer, testHost))
+	require.NoError(t, store.Track(other, testHost))
+
+	fields := orderedmap.New[string, any]()
+	fields.Set("msg", "charge declined")
+	fields.Set("user", map[string]any{"id": float64(42)})
+
+	now := time.Now()
+	addEvent(t, store, index, &container.LogEvent{Type: container.LogTypeSingle, Message: "payment failed for order 17", Timestamp: testStart.UnixMilli(), ContainerID: testContainer.ID, Level: "error", Stream: "stderr"}, now)
+	addEvent(t, store, index, &container.LogEvent{Type: container.LogTypeSingle, Message: "failed payment retried", Timestamp: testStart.Add(time.Minute).UnixMilli(), ContainerID: other.ID, Level: "info", Stream: "stdout"}, now)
+	addEvent(t, store, index, &container.LogEvent{Type: container.LogTypeComplex, Message: fields, RawMessage: `{"msg":"charge declined","user":{"id":42}}`, Timestamp: testStart.Add(2 * time.Minute).UnixMilli(), ContainerID: testContainer.ID, Level: "warn"}, now)
+
+	search := func(text string) SearchResult {
+		t.Helper()
+		result, err := index.Search(context.Background(), Query{Text: text, From: testStart, To: testStart.Add(time.Hour)})
+		require.NoError(t, err)
+		return result
+	}
+
+	result := search("payment")
+	assert.Equal(t, []any{"failed payment retried", "payment failed for order 17"}, hitMessages(result))
+	assert.Equal(t, "worker", result.Hits[0].ContainerName)
+	assert.Equal(t, testHost.ID, result.Hits[0].Host)
+	assert.Equal(t, testStart.Add(time.Minute), result.Hits[0].Timestamp.UTC())
+
+	assert.Equal(t, []any{"payment failed for order 17"}, hitMessages(search(`"Payment Failed"`)))
+	assert.Equal(t, []any{"payment failed for order 17"}, hitMessages(search("payment level:error")))
+	assert.Len(t, search("user.id:42").Hits, 1)
+	assert.Empty(t, search("refund").Hits)
+
+	result, err := index.Search(context.Background(), Query{Text: "payment", From: testStart, To: testStart.Add(time.Hour), Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, result.Hits, 1)
+	assert.True(t, result.Truncated)
+
+	result, err = index.Search(context.Background(), Query{Text: "payment", From: testStart.Add(30 * time.Second), To: testStart.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Len(t, result.Hits, 1)
+
+	result, err = index.Search(context.Background(), Query{Text: "payment", From: testStart, To: testStart.Add(time.Hour), Allow: func(id string) bool { return id == testContainer.ID }})
+	require.NoError(t, err)
+	assert.Equal(t, []any{"payment failed for order 17"}, hitMessages(result))
+
+	_, err = index.Search(context.Background(), Query{Text: " -- "})
+	assert.ErrorIs(t, err, ErrEmptyQuery)
+}
+
+func TestIndex_SearchLogfmtFields(t *testing.T) {
+	store, index, _ := newTestIndex(t)
+	require.NoError(t, store.Track(testContainer, testHost))
+
+	line := `level=error method=POST path=/checkout status=502 msg="upstream timed out"`
+	fields, err := container.ParseLogFmt(line)
+	require.NoError(t, err)
+	addEvent(t, store, index, &container.LogEvent{Type: container.LogTypeComplex, Message: fields, RawMessage: line, Timestamp: testStart.UnixMilli(), ContainerID: testContainer.ID, Level: "error"}, time.Now())
+
+	search := func(text string) SearchResult {
+		t.Helper()
+		result, err := index.Search(context.Background(), Query{Text: text, From: testStart, To: testStart.Add(time.Hour)})
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.Len(t, search("status:502").Hits, 1)
+	assert.Len(t, search("method:post path:/checkout").Hits, 1)
+	assert.Len(t, search(`"upstream timed out"`).Hits, 1)
+	assert.Empty(t, search("status:200").Hits)
+}
+
+func TestIndex_SealAndReopen(t *testing.T) {
+	store, index, dir := newTestIndex(t)
+	require.NoError(t, store.Track(testContainer, testHost))
+
+	hour := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	addEvent(t, store, index, &container.LogEvent{Type: container.LogTypeSingle, Message: "sealed line", Timestamp: testStart.UnixMilli(), ContainerID: testContainer.ID}, hour)
+	addEvent(t, store, index, &container.LogEvent{Type: container.LogTypeSingle, Message: "active line", Timestamp: testStart.Add(time.Second).UnixMilli(), ContainerID: testContainer.ID}, hour.Add(time.Hour))
+	require.Len(t, index.segments, 2)
+	assert.True(t, index.segments[0].meta.Sealed)
+
+	query := Query{Text: "line", From: testStart, To: testStart.Add(time.Hour)}
+	result, err := index.Search(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"active line", "sealed line"}, hitMessages(result))
+
+	// the active segment is rebuilt from the archive after a restart
+	require.NoError(t, index.Close())
+	reopened, err := NewIndex(dir, store, 24*time.Hour)
+	require.NoError(t, err)
+	defer reopened.Close()
+	require.Len(t, reopened.segments, 2)
+	assert.True(t, reopened.segments[1].meta.Sealed)
+
+	result, err = reopened.Search(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"active line", "sealed line"}, hitMessages(result))
+
+	reopened.Prune(hour.Add(25 * time.Hour))
+	assert.Len(t, reopened.segments, 1)
+	reopened.Prune(hour.Add(26 * time.Hour))
+	assert.Empty(t, reopened.segments)
+}
+
+func TestIndex_SearchTextWithColons(t *testing.T) {
+	store, index, _ := newTestIndex(t)
+	require.NoError(t, store.Track(testContainer, testHost))
+
+	now := time.Now()
+	addEvent(t, store, index, &container.LogEvent{Type: container.LogTypeSingle, Message: "GET http://api.internal/orders failed", Timestamp: testStart.UnixMilli(), ContainerID: testContainer.ID, Level: "info"}, now)
+	addEvent(t, store, index, &container.LogEvent{Type: container.LogTypeSingle, Message: "ERROR:db connection lost at 12:30:05", Timestamp: testStart.Add(time.Second).UnixMilli(), ContainerID: testContainer.ID, Level: "error"}, now)
+
+	search := func(text string) SearchResult {
+		t.Helper()
+		result, err := index.Search(context.Background(), Query{Text: text, From: testStart, To: testStart.Add(time.Hour)})
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.Len(t, search("http://api.internal/orders").Hits, 1)
+	assert.Len(t, search("ERROR:db").Hits, 1)
+	assert.Len(t, search("12:30:05").Hits, 1)
+	assert.Len(t, search("level:error").Hits, 1, "the level field")
+	assert.Len(t, search("level:info orders").Hits, 1)
+	assert.Empty(t, search("ERROR:cache").Hits)
+}
+
+func Test_parseQuery(t *testing.T) {
+	assert.Equal(t, []queryClause{
+		{tokens: []string{"timeout"}, phrase: "timeout"},
+		{tokens: []string{"connection", "reset"}, phrase: "connection reset"},
+		{tokens: []string{"level", "error"}, phrase: "level:error", field: "level:error"},
+		{tokens: []string{"12", "30"}, phrase: "12:30"},
+	}, parseQuery(`timeout "connection reset" level:ERROR 12:30`))
+}
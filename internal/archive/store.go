@@ -0,0 +1,535 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/rs/zerolog/log"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+const (
+	metadataFile     = "container.json"
+	segmentExtension = ".jsonl"
+
+	// minSegmentSize and maxSegmentSize bound the size of a single log file.
+	// Retention removes whole files, so they are kept to about a tenth of the
+	// size limit.
+	minSegmentSize = 64 << 10
+	maxSegmentSize = 8 << 20
+
+	// idleTimeout closes the file of a container that stopped logging.
+	idleTimeout = 5 * time.Minute
+)
+
+var ErrNotArchived = errors.New("container is not archived")
+
+type metadata struct {
+	Container container.Container `json:"container"`
+	Host      container.Host      `json:"host"`
+}
+
+type segment struct {
+	path    string
+	start   int64 // timestamp of the first event in milliseconds
+	size    int64
+	modTime time.Time
+}
+
+type entry struct {
+	dir      string
+	metadata metadata
+	segments []*segment
+	file     *os.File
+	lastUsed time.Time
+}
+
+// Store appends log events to one directory per container and serves them
+// back. Files are JSON lines, rotated by size. It is safe for concurrent use.
+type Store struct {
+	dir         string
+	maxAge      time.Duration
+	maxSize     uint64
+	segmentSize int64
+
+	mu      sync.Mutex
+	entries map[string]*entry // containerID -> entry
+}
+
+// NewStore opens the archive in dir, indexing any previously archived
+// containers.
+func NewStore(dir string, config *Config) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		dir:         dir,
+		maxAge:      config.Retention(),
+		maxSize:     config.maxSize,
+		segmentSize: min(max(int64(config.maxSize/10), minSegmentSize), maxSegmentSize),
+		entries:     make(map[string]*entry),
+	}
+
+	hosts, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, host := range hosts {
+		if !host.IsDir() {
+			continue
+		}
+		containers, err := os.ReadDir(filepath.Join(dir, host.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range containers {
+			if !c.IsDir() {
+				continue
+			}
+			e, err := loadEntry(filepath.Join(dir, host.Name(), c.Name()))
+			if err != nil {
+				log.Warn().Err(err).Str("path", c.Name()).Msg("Skipping unreadable archive")
+				continue
+			}
+			s.entries[e.metadata.Container.ID] = e
+		}
+	}
+
+	return s, nil
+}
+
+func loadEntry(dir string) (*entry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, metadataFile))
+	if err != nil {
+		return nil, err
+	}
+	e := &entry{dir: dir}
+	if err := json.Unmarshal(data, &e.metadata); err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		name, ok := strings.CutSuffix(file.Name(), segmentExtension)
+		if !ok {
+			continue
+		}
+		start, err := strconv.ParseInt(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			return nil, err
+		}
+		e.segments = append(e.segments, &segment{
+			path:    filepath.Join(dir, file.Name()),
+			start:   start,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+	slices.SortFunc(e.segments, func(a, b *segment) int {
+		return cmp.Compare(a.start, b.start)
+	})
+	return e, nil
+}
+
+// Track records the latest metadata of c. It must be called before the first
+// Append of a container.
+func (s *Store) Track(c container.Container, host container.Host) error {
+	c.Host = host.ID
+	c.Stats = nil
+	c.MountStats = nil
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[c.ID]
+	if !ok {
+		e = &entry{dir: filepath.Join(s.dir, url.PathEscape(host.ID), url.PathEscape(c.ID))}
+		if err := os.MkdirAll(e.dir, 0755); err != nil {
+			return err
+		}
+		s.entries[c.ID] = e
+	} else if sameMetadata(e.metadata, c, host) {
+		return nil
+	}
+
+	e.metadata = metadata{Container: c, Host: host}
+	data, err := json.Marshal(e.metadata)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(e.dir, metadataFile), data, 0644)
+}
+
+func sameMetadata(m metadata, c container.Container, host container.Host) bool {
+	return m.Host.ID == host.ID && m.Host.Name == host.Name &&
+		m.Container.Name == c.Name && m.Container.Image == c.Image &&
+		m.Container.State == c.State && m.Container.Health == c.Health &&
+		m.Container.StartedAt.Equal(c.StartedAt) && m.Container.FinishedAt.Equal(c.FinishedAt)
+}
+
+// Location is the position of an archived line.
+type Location struct {
+	Segment int64 // start of the segment
+	Offset  int64
+}
+
+// Append writes event to the archive of its container and returns where it
+// was written.
+func (s *Store) Append(event *container.LogEvent) (Location, error) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return Location{}, err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[event.ContainerID]
+	if !ok {
+		return Location{}, ErrNotArchived
+	}
+
+	now := time.Now()
+	current := e.current()
+	if e.file == nil || current == nil || current.size >= s.segmentSize {
+		if err := s.rotate(e, event, now); err != nil {
+			return Location{}, err
+		}
+		current = e.current()
+	}
+
+	location := Location{Segment: current.start, Offset: current.size}
+	n, err := e.file.Write(line)
+	current.size += int64(n)
+	current.modTime = now
+	e.lastUsed = now
+	return location, err
+}
+
+// Read returns the archived event of the container id at location. It fails
+// with os.ErrNotExist once retention removed the line.
+func (s *Store) Read(id string, location Location) (*container.LogEvent, error) {
+	s.mu.Lock()
+	e, ok := s.entries[id]
+	var path string
+	if ok {
+		for _, seg := range e.segments {
+			if seg.start == location.Segment {
+				path = seg.path
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+	if path == "" {
+		return nil, os.ErrNotExist
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	line, err := bufio.NewReader(io.NewSectionReader(file, location.Offset, maxSegmentSize)).ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	return decodeEvent(line)
+}
+
+func (e *entry) current() *segment {
+	if len(e.segments) == 0 {
+		return nil
+	}
+	return e.segments[len(e.segments)-1]
+}
+
+// rotate closes the open file of e and starts a new segment, unless the last
+// segment is still small enough to be reopened.
+func (s *Store) rotate(e *entry, event *container.LogEvent, now time.Time) error {
+	if e.file != nil {
+		e.file.Close()
+		e.file = nil
+	}
+
+	current := e.current()
+	if current == nil || current.size >= s.segmentSize {
+		start := event.Timestamp
+		if start <= 0 {
+			start = now.UnixMilli()
+		}
+		if current != nil {
+			start = max(start, current.start+1)
+		}
+		current = &segment{path: filepath.Join(e.dir, strconv.FormatInt(start, 10)+segmentExtension), start: start}
+		e.segments = append(e.segments, current)
+	}
+
+	file, err := os.OpenFile(current.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		e.segments = slices.DeleteFunc(e.segments, func(seg *segment) bool { return seg == current && seg.size == 0 })
+		return err
+	}
+	e.file = file
+	return nil
+}
+
+// Find returns the archived metadata of the container id on host.
+func (s *Store) Find(host, id string) (container.Container, container.Host, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[id]
+	if !ok || e.metadata.Host.ID != host || len(e.segments) == 0 {
+		return container.Container{}, container.Host{}, false
+	}
+	return e.metadata.Container, e.metadata.Host, true
+}
+
+// lookup returns the metadata of the archived container id.
+func (s *Store) lookup(id string) (metadata, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[id]
+	if !ok {
+		return metadata{}, false
+	}
+	return e.metadata, true
+}
+
+// Containers returns the metadata of every archived container.
+func (s *Store) Containers() []container.Container {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	containers := make([]container.Container, 0, len(s.entries))
+	for _, e := range s.entries {
+		if len(e.segments) > 0 {
+			containers = append(containers, e.metadata.Container)
+		}
+	}
+	return containers
+}
+
+// Events sends the archived events of the container id between from and to,
+// in the order they were written, and closes the channel.
+func (s *Store) Events(ctx context.Context, id string, from, to time.Time, stdTypes container.StdType, events chan<- *container.LogEvent) error {
+	defer close(events)
+
+	s.mu.Lock()
+	e, ok := s.entries[id]
+	var paths []string
+	if ok {
+		for i, seg := range e.segments {
+			if seg.start > to.UnixMilli() {
+				break
+			}
+			if i+1 < len(e.segments) && e.segments[i+1].start < from.UnixMilli() {
+				continue
+			}
+			paths = append(paths, seg.path)
+		}
+	}
+	s.mu.Unlock()
+	if !ok {
+		return ErrNotArchived
+	}
+
+	for _, path := range paths {
+		if err := readSegment(ctx, path, from, to, stdTypes, events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readSegment(ctx context.Context, path string, from, to time.Time, stdTypes container.StdType, events chan<- *container.LogEvent) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// removed by retention while reading
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			event, decodeErr := decodeEvent(line)
+			if decodeErr != nil {
+				log.Debug().Err(decodeErr).Str("path", path).Msg("Skipping invalid archived line")
+			} else if inRange(event, from, to, stdTypes) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+		if err != nil {
+			// a partial last line is still being written
+			return nil
+		}
+	}
+}
+
+func inRange(event *container.LogEvent, from, to time.Time, stdTypes container.StdType) bool {
+	if event.Timestamp < from.UnixMilli() || event.Timestamp > to.UnixMilli() {
+		return false
+	}
+	switch event.Stream {
+	case container.STDOUT.String():
+		return stdTypes&container.STDOUT != 0
+	case container.STDERR.String():
+		return stdTypes&container.STDERR != 0
+	}
+	return true
+}
+
+// decodeEvent restores the message of an archived event to the type the event
+// generator produced.
+func decodeEvent(line []byte) (*container.LogEvent, error) {
+	var record struct {
+		container.LogEvent
+		Message json.RawMessage `json:"m,omitempty"`
+	}
+	if err := json.Unmarshal(line, &record); err != nil {
+		return nil, err
+	}
+
+	event := record.LogEvent
+	if len(record.Message) == 0 {
+		event.Message = ""
+		return &event, nil
+	}
+
+	switch event.Type {
+	case container.LogTypeComplex:
+		var data *orderedmap.OrderedMap[string, any]
+		if err := json.NewDecoder(bytes.NewReader(record.Message)).Decode(&data); err != nil {
+			return nil, err
+		}
+		event.Message = data
+	case container.LogTypeGroup:
+		var fragments []container.LogFragment
+		if err := json.Unmarshal(record.Message, &fragments); err != nil {
+			return nil, err
+		}
+		event.Message = fragments
+	default:
+		var message string
+		if err := json.Unmarshal(record.Message, &message); err != nil {
+			return nil, err
+		}
+		event.Message = message
+	}
+	return &event, nil
+}
+
+// Prune closes idle files and removes the oldest segments until the archive
+// is within its age and size limits.
+func (s *Store) Prune(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := now.Add(-s.maxAge)
+	var total uint64
+	var all []*segment
+	owners := make(map[*segment]*entry)
+	for _, e := range s.entries {
+		if e.file != nil && now.Sub(e.lastUsed) > idleTimeout {
+			e.file.Close()
+			e.file = nil
+		}
+		e.segments = slices.DeleteFunc(e.segments, func(seg *segment) bool {
+			if seg.modTime.Before(cutoff) {
+				s.removeSegment(e, seg)
+				return true
+			}
+			return false
+		})
+		for _, seg := range e.segments {
+			total += uint64(seg.size)
+			all = append(all, seg)
+			owners[seg] = e
+		}
+	}
+
+	if total > s.maxSize {
+		slices.SortFunc(all, func(a, b *segment) int {
+			return a.modTime.Compare(b.modTime)
+		})
+		for _, seg := range all {
+			if total <= s.maxSize {
+				break
+			}
+			e := owners[seg]
+			s.removeSegment(e, seg)
+			e.segments = slices.DeleteFunc(e.segments, func(other *segment) bool { return other == seg })
+			total -= uint64(seg.size)
+		}
+	}
+
+	for id, e := range s.entries {
+		if len(e.segments) == 0 && e.file == nil && now.Sub(e.lastUsed) > idleTimeout {
+			if err := os.RemoveAll(e.dir); err != nil {
+				log.Warn().Err(err).Str("path", e.dir).Msg("Could not remove archive")
+				continue
+			}
+			delete(s.entries, id)
+		}
+	}
+}
+
+func (s *Store) removeSegment(e *entry, seg *segment) {
+	if e.file != nil && seg == e.current() {
+		e.file.Close()
+		e.file = nil
+	}
+	if err := os.Remove(seg.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warn().Err(err).Str("path", seg.path).Msg("Could not remove archived logs")
+	}
+}
+
+// Close closes all open files.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for _, e := range s.entries {
+		if e.file != nil {
+			errs = append(errs, e.file.Close())
+			e.file = nil
+		}
+	}
+	return errors.Join(errs...)
+}
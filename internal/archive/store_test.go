@@ -0,0 +1,178 @@
+package archive
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+var (
+	testHost      = container.Host{ID: "host-1", Name: "local"}
+	testContainer = container.Container{ID: "abc123", Name: "api", Image: "api:latest", State: "running", Labels: map[string]string{"app": "api"}}
+	testStart     = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func newTestStore(t *testing.T, dir string) *Store {
+	t.Helper()
+	config, err := LoadConfig(strings.NewReader(""))
+	require.NoError(t, err)
+	store, err := NewStore(dir, config)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func collect(t *testing.T, store *Store, from, to time.Time, stdTypes container.StdType) []*container.LogEvent {
+	t.Helper()
+	events := make(chan *container.LogEvent)
+	var result []*container.LogEvent
+	done := make(chan error)
+	go func() {
+		done <- store.Events(context.Background(), testContainer.ID, from, to, stdTypes, events)
+	}()
+	for event := range events {
+		result = append(result, event)
+	}
+	require.NoError(t, <-done)
+	return result
+}
+
+func TestStore_AppendAndRead(t *testing.T) {
+	store := newTestStore(t, t.TempDir())
+
+	data := orderedmap.New[string, any]()
+	data.Set("msg", "payment failed")
+	data.Set("code", float64(42))
+
+	events := []*container.LogEvent{
+		{Type: container.LogTypeSingle, Message: "hello", RawMessage: "hello", Timestamp: testStart.UnixMilli(), Stream: "stdout", ContainerID: testContainer.ID, Level: "info"},
+		{Type: container.LogTypeComplex, Message: data, RawMessage: `{"msg":"payment failed","code":42}`, Timestamp: testStart.Add(time.Second).UnixMilli(), Stream: "stderr", ContainerID: testContainer.ID, Level: "error"},
+		{Type: container.LogTypeGroup, Message: []container.LogFragment{{Message: "panic"}, {Message: "  at main"}}, Timestamp: testStart.Add(2 * time.Second).UnixMilli(), Stream: "stdout", ContainerID: testContainer.ID},
+	}
+
+	_, err := store.Append(events[0])
+	assert.ErrorIs(t, err, ErrNotArchived)
+	require.NoError(t, store.Track(testContainer, testHost))
+	for _, event := range events {
+		_, err = store.Append(event)
+	require.NoError(t, err)
+	}
+
+	actual := collect(t, store, testStart, testStart.Add(time.Minute), container.STDALL)
+	require.Len(t, actual, 3)
+	assert.Equal(t, "hello", actual[0].Message)
+	complex, ok := actual[1].Message.(*orderedmap.OrderedMap[string, any])
+	require.True(t, ok)
+	assert.Equal(t, []string{"msg", "code"}, []string{complex.Oldest().Key, complex.Newest().Key})
+	assert.Equal(t, "error", actual[1].Level)
+	assert.Equal(t, []container.LogFragment{{Message: "panic"}, {Message: "  at main"}}, actual[2].Message)
+
+	actual = collect(t, store, testStart.Add(time.Second), testStart.Add(time.Minute), container.STDOUT)
+	require.Len(t, actual, 1)
+	assert.Equal(t, container.LogTypeGroup, actual[0].Type)
+
+	c, host, ok := store.Find(testHost.ID, testContainer.ID)
+	require.True(t, ok)
+	assert.Equal(t, "api", c.Name)
+	assert.Equal(t, testHost.ID, c.Host)
+	assert.Equal(t, testHost, host)
+	_, _, ok = store.Find("other", testContainer.ID)
+	assert.False(t, ok)
+}
+
+func TestStore_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestStore(t, dir)
+	require.NoError(t, store.Track(testContainer, testHost))
+	_, err := store.Append(&container.LogEvent{Type: container.LogTypeSingle, Message: "before restart", Timestamp: testStart.UnixMilli(), ContainerID: testContainer.ID})
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	reopened := newTestStore(t, dir)
+	containers := reopened.Containers()
+	require.Len(t, containers, 1)
+	assert.Equal(t, testContainer.ID, containers[0].ID)
+	assert.Equal(t, map[string]string{"app": "api"}, containers[0].Labels)
+
+	_, err = reopened.Append(&container.LogEvent{Type: container.LogTypeSingle, Message: "after restart", Timestamp: testStart.Add(time.Second).UnixMilli(), ContainerID: testContainer.ID})
+	require.NoError(t, err)
+	actual := collect(t, reopened, testStart, testStart.Add(time.Minute), container.STDALL)
+	require.Len(t, actual, 2)
+	assert.Equal(t, "after restart", actual[1].Message)
+}
+
+func TestStore_Prune(t *testing.T) {
+	dir := t.TempDir()
+	config, err := LoadConfig(strings.NewReader("maxAge: 1h\nmaxSize: 100KB\n"))
+	require.NoError(t, err)
+	store, err := NewStore(dir, config)
+	require.NoError(t, err)
+	defer store.Close()
+
+	require.NoError(t, store.Track(testContainer, testHost))
+	line := strings.Repeat("x", 1000)
+	for i := range 300 {
+		_, err = store.Append(&container.LogEvent{Type: container.LogTypeSingle, Message: line, Timestamp: testStart.Add(time.Duration(i) * time.Second).UnixMilli(), ContainerID: testContainer.ID})
+	require.NoError(t, err)
+	}
+	require.Greater(t, len(store.entries[testContainer.ID].segments), 2)
+	containerDir := store.entries[testContainer.ID].dir
+
+	store.Prune(time.Now())
+	var total int64
+	for _, seg := range store.entries[testContainer.ID].segments {
+		total += seg.size
+	}
+	assert.LessOrEqual(t, total, int64(100_000))
+
+	// the newest lines survive the size limit
+	actual := collect(t, store, testStart, testStart.Add(time.Hour), container.STDALL)
+	require.NotEmpty(t, actual)
+	assert.Equal(t, testStart.Add(299*time.Second).UnixMilli(), actual[len(actual)-1].Timestamp)
+
+	// everything expires with age
+	store.Prune(time.Now().Add(2 * time.Hour))
+	assert.Empty(t, store.Containers())
+	_, err = os.Stat(containerDir)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestArchiveClient(t *testing.T) {
+	store := newTestStore(t, t.TempDir())
+	require.NoError(t, store.Track(testContainer, testHost))
+	_, err := store.Append(&container.LogEvent{Type: container.LogTypeSingle, Message: "hello", RawMessage: "hello", Timestamp: testStart.UnixMilli(), ContainerID: testContainer.ID})
+	require.NoError(t, err)
+	_, err = store.Append(&container.LogEvent{Type: container.LogTypeGroup, Message: []container.LogFragment{{Message: "one"}, {Message: "two"}}, Timestamp: testStart.Add(time.Second).UnixMilli(), ContainerID: testContainer.ID})
+	require.NoError(t, err)
+
+	a := &Archive{store: store, ctx: context.Background()}
+	service, err := a.FindContainer(testHost.ID, testContainer.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, removedState, service.Container.State)
+
+	_, err = a.FindContainer(testHost.ID, testContainer.ID, container.ContainerLabels{"label": {"app=web"}})
+	assert.ErrorIs(t, err, container.ErrContainerNotFound)
+	assert.Len(t, a.Containers(container.ContainerLabels{"label": {"app=api"}}), 1)
+	assert.Empty(t, a.Containers(container.ContainerLabels{"name": {"web"}}))
+
+	reader, err := service.RawLogs(context.Background(), time.Time{}, time.Now(), container.STDALL)
+	require.NoError(t, err)
+	raw, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-01T12:00:00Z hello\n2024-03-01T12:00:01Z one\n2024-03-01T12:00:01Z two\n", string(raw))
+
+	events := make(chan *container.LogEvent, 10)
+	err = service.StreamLogs(context.Background(), testStart.Add(time.Second), container.STDALL, events)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Len(t, events, 1)
+
+	assert.ErrorIs(t, service.Action(context.Background(), container.Restart), ErrRemoved)
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-chi/jwtauth/v5"
+)
+
+// DefaultShareSecretPath holds the key share links are signed with when the
+// auth provider has none of its own.
+const DefaultShareSecretPath = "./data/share.key"
+
+// shareTokenType marks share tokens so they are never mistaken for sessions.
+const shareTokenType = "share"
+
+var ErrInvalidShareToken = errors.New("invalid or expired share link")
+
+// Share is the slice of logs a share link grants read access to.
+type Share struct {
+	ID          string    `json:"id"`
+	Host        string    `json:"host"`
+	ContainerID string    `json:"containerId"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Filter      string    `json:"filter,omitempty"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// ShareSigner signs and verifies share links. Its key is derived from a
+// secret so share tokens and session tokens can never be used for each other.
+type ShareSigner struct {
+	tokenAuth *jwtauth.JWTAuth
+}
+
+// NewShareSigner signs share links with a key derived from secret.
+func NewShareSigner(secret []byte) *ShareSigner {
+	h := sha256.New()
+	h.Write([]byte(shareTokenType))
+	h.Write(secret)
+	return &ShareSigner{tokenAuth: jwtauth.New("HS256", h.Sum(nil), nil)}
+}
+
+// LoadShareSecret reads the secret at path, creating a random one when the
+// file does not exist.
+func LoadShareSecret(path string) ([]byte, error) {
+	secret, err := os.ReadFile(path)
+	if err == nil && len(secret) > 0 {
+		return secret, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	secret = make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, secret, 0600); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// Sign returns the token of share, valid until share.ExpiresAt.
+func (s *ShareSigner) Sign(share Share) (string, error) {
+	claims := map[string]any{
+		"typ":    shareTokenType,
+		"jti":    share.ID,
+		"host":   share.Host,
+		"cid":    share.ContainerID,
+		"from":   share.From.UTC().Format(time.RFC3339Nano),
+		"to":     share.To.UTC().Format(time.RFC3339Nano),
+		"filter": share.Filter,
+	}
+	jwtauth.SetIssuedNow(claims)
+	jwtauth.SetExpiry(claims, share.ExpiresAt)
+
+	_, token, err := s.tokenAuth.Encode(claims)
+	return token, err
+}
+
+// Verify checks the signature and expiry of token and returns its share.
+func (s *ShareSigner) Verify(token string) (Share, error) {
+	parsed, err := jwtauth.VerifyToken(s.tokenAuth, token)
+	if err != nil {
+		return Share{}, ErrInvalidShareToken
+	}
+
+	var typ, from, to string
+	share := Share{}
+	_ = parsed.Get("typ", &typ)
+	_ = parsed.Get("host", &share.Host)
+	_ = parsed.Get("cid", &share.ContainerID)
+	_ = parsed.Get("filter", &share.Filter)
+	_ = parsed.Get("from", &from)
+	_ = parsed.Get("to", &to)
+	if typ != shareTokenType {
+		return Share{}, ErrInvalidShareToken
+	}
+	share.ID, _ = parsed.JwtID()
+	share.ExpiresAt, _ = parsed.Expiration()
+	if share.From, err = time.Parse(time.RFC3339Nano, from); err != nil {
+		return Share{}, fmt.Errorf("%w: %v", ErrInvalidShareToken, err)
+	}
+	if share.To, err = time.Parse(time.RFC3339Nano, to); err != nil {
+		return Share{}, fmt.Errorf("%w: %v", ErrInvalidShareToken, err)
+	}
+	if share.ID == "" || share.Host == "" || share.ContainerID == "" {
+		return Share{}, ErrInvalidShareToken
+	}
+	return share, nil
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareSignerRoundTrip(t *testing.T) {
+	signer := NewShareSigner([]byte("secret"))
+	share := Share{
+		ID:          "abc",
+		Host:        "localhost",
+		ContainerID: "123456",
+		From:        time.Date(2024, 3, 1, 3, 10, 0, 0, time.UTC),
+		To:          time.Date(2024, 3, 1, 3, 15, 0, 0, time.UTC),
+		Filter:      "?level:error",
+		ExpiresAt:   time.Now().Add(time.Hour).Truncate(time.Second),
+	}
+
+	token, err := signer.Sign(share)
+	require.NoError(t, err)
+
+	verified, err := signer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, share.ID, verified.ID)
+	assert.Equal(t, share.Host, verified.Host)
+	assert.Equal(t, share.ContainerID, verified.ContainerID)
+	assert.True(t, share.From.Equal(verified.From))
+	assert.True(t, share.To.Equal(verified.To))
+	assert.Equal(t, share.Filter, verified.Filter)
+	assert.True(t, share.ExpiresAt.Equal(verified.ExpiresAt))
+
+	_, err = NewShareSigner([]byte("other")).Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidShareToken)
+}
+
+func TestShareSignerRejectsExpiredTokens(t *testing.T) {
+	signer := NewShareSigner([]byte("secret"))
+	token, err := signer.Sign(Share{ID: "abc", Host: "localhost", ContainerID: "123456", From: time.Now().Add(-time.Hour), To: time.Now(), ExpiresAt: time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+
+	_, err = signer.Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidShareToken)
+}
+
+// Share tokens and session tokens are signed with different keys, so neither
+// can be used in place of the other.
+func TestShareTokensAreNotSessions(t *testing.T) {
+	simple := NewSimpleAuth(UserDatabase{Users: map[string]*User{
+		"alice": {Username: "alice", Password: "$2a$11$aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", RolesConfigured: "all"},
+	}}, 0)
+
+	token, err := simple.ShareSigner().Sign(Share{ID: "abc", Host: "localhost", ContainerID: "123456", From: time.Now().Add(-time.Hour), To: time.Now(), ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	_, err = simple.tokenAuth.Decode(token)
+	assert.Error(t, err)
+
+	_, session, err := simple.tokenAuth.Encode(map[string]any{"username": "alice"})
+	require.NoError(t, err)
+	_, err = simple.ShareSigner().Verify(session)
+	assert.ErrorIs(t, err, ErrInvalidShareToken)
+}
+
+func TestLoadShareSecretIsStable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "share.key")
+
+	secret, err := LoadShareSecret(path)
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	again, err := LoadShareSecret(path)
+	require.NoError(t, err)
+	assert.Equal(t, secret, again)
+}
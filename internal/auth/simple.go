@@ -14,6 +14,7 @@ import (
 type simpleAuthContext struct {
 	UserDatabase UserDatabase
 	tokenAuth    *jwtauth.JWTAuth
+	shareSigner  *ShareSigner
 	ttl          time.Duration
 }
 
@@ -31,11 +32,13 @@ func NewSimpleAuth(userDatabase UserDatabase, ttl time.Duration) *simpleAuthCont
 		h.Write([]byte(user.RolesConfigured))
 	}
 
-	tokenAuth := jwtauth.New("HS256", h.Sum(nil), nil)
+	key := h.Sum(nil)
+	tokenAuth := jwtauth.New("HS256", key, nil)
 
 	return &simpleAuthContext{
 		UserDatabase: userDatabase,
 		tokenAuth:    tokenAuth,
+		shareSigner:  NewShareSigner(key),
 		ttl:          ttl,
 	}
 }
@@ -64,3 +67,9 @@ func (a *simpleAuthContext) CreateToken(username, password string) (string, erro
 func (a *simpleAuthContext) AuthMiddleware(next http.Handler) http.Handler {
 	return jwtauth.Verifier(a.tokenAuth)(next)
 }
+
+// ShareSigner signs share links with a key derived from the users, so links
+// are revoked along with sessions when a password or role changes.
+func (a *simpleAuthContext) ShareSigner() *ShareSigner {
+	return a.shareSigner
+}
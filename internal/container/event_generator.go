@@ -26,6 +26,12 @@ type EventGenerator struct {
 	wg          sync.WaitGroup
 	containerID string
 	startedAt   time.Time
+	multiline   *multilineRule
+	parser      LogParser
+	levels      *levelConfig
+	fields      *messageFields
+	timestamps  bool
+	redactor    *Redactor
 	ctx         context.Context
 }
 
@@ -43,6 +49,12 @@ func NewEventGenerator(ctx context.Context, reader LogReader, container Containe
 		Events:      make(chan *LogEvent),
 		containerID: container.ID,
 		startedAt:   container.StartedAt,
+		multiline:   parseMultilineRule(container.Labels),
+		parser:      parserForContainer(container),
+		levels:      parseLevelConfig(container.Labels),
+		fields:      parseMessageFields(container.Labels),
+		timestamps:  UsesMessageTimestamps(container),
+		redactor:    RedactorForContainer(container),
 		ctx:         ctx,
 	}
 	generator.wg.Add(2)
@@ -52,6 +64,16 @@ func NewEventGenerator(ctx context.Context, reader LogReader, container Containe
 }
 
 func (g *EventGenerator) emit(event *LogEvent) bool {
+	// Fallback levels are applied last so they don't affect grouping, which
+	// treats level-less lines as continuations.
+	if g.levels != nil && !event.HasLevel() {
+		event.Level = g.levels.fallbackFor(event)
+	}
+	// Likewise for message timestamps, since grouping compares driver
+	// timestamps of consecutive lines.
+	if g.timestamps {
+		applyMessageTimestamp(event)
+	}
 	select {
 	case g.Events <- event:
 		return true
@@ -134,6 +156,11 @@ func (g *EventGenerator) skipOrphanedLines() *LogEvent {
 	for {
 		isOrphan := current.IsSimple() && !current.HasLevel() && current.Timestamp > 0 &&
 			(lastTimestamp == 0 || math.Abs(float64(lastTimestamp-current.Timestamp)) < maxGroupTimeDelta)
+		if g.multiline != nil {
+			// With a rule, the line's shape says whether it continues something.
+			message, _ := current.Message.(string)
+			isOrphan = current.IsSimple() && current.Timestamp > 0 && g.multiline.continues(message)
+		}
 
 		if !isOrphan {
 			if len(orphanBuffer) > 0 {
@@ -165,7 +192,7 @@ func (g *EventGenerator) skipOrphanedLines() *LogEvent {
 		}
 
 		// Use peek (with timeout) so we don't block forever on a live stream.
-		if next := g.peek(); next == nil {
+		if next := g.peekWithin(g.peekTimeout(orphanBuffer, current)); next == nil {
 			// No more events within the timeout — these aren't orphans.
 			// Emit them as singles, then block for the next event so the
 			// stream continues processing.
@@ -216,10 +243,10 @@ loop:
 		}
 
 		// Simple log - peek ahead to decide grouping
-		next := g.peek()
+		next := g.peekWithin(g.peekTimeout(pendingGroup, current))
 
 		if len(pendingGroup) == 0 {
-			if next != nil && next.IsSimple() && canStartGroup(current, next) {
+			if next != nil && next.IsSimple() && g.canStartGroup(current, next) {
 				next.Level = current.Level
 				pendingGroup = append(pendingGroup, current)
 			} else {
@@ -233,7 +260,7 @@ loop:
 
 		pendingGroup = append(pendingGroup, current)
 
-		if next == nil || !next.IsSimple() || !canContinueGroup(pendingGroup[len(pendingGroup)-1], next, pendingGroup[0].Level) {
+		if next == nil || !next.IsSimple() || !g.canContinueGroup(pendingGroup, next) {
 			if !g.flushGroup(pendingGroup) {
 				break loop
 			}
@@ -257,6 +284,25 @@ func (g *EventGenerator) nextEvent() *LogEvent {
 	return event
 }
 
+// canStartGroup checks if current can start a group with next, using the
+// container's multiline rule when it declares one.
+func (g *EventGenerator) canStartGroup(current, next *LogEvent) bool {
+	if g.multiline != nil {
+		return g.multiline.canContinue(current, next)
+	}
+	return canStartGroup(current, next)
+}
+
+// canContinueGroup checks if next can be appended to group, using the
+// container's multiline rule when it declares one.
+func (g *EventGenerator) canContinueGroup(group []*LogEvent, next *LogEvent) bool {
+	prev := group[len(group)-1]
+	if g.multiline != nil {
+		return len(group) < maxMultilineLines && g.multiline.canContinue(prev, next)
+	}
+	return canContinueGroup(prev, next, group[0].Level)
+}
+
 // canStartGroup checks if current can start a group with next
 func canStartGroup(current, next *LogEvent) bool {
 	return current.HasLevel() && canContinueGroup(current, next, current.Level)
@@ -274,8 +320,21 @@ func (g *EventGenerator) consumeReader() {
 		message, streamType, readerError := g.reader.Read()
 		if message != "" {
 			logEvent := createEvent(message, streamType)
+			if g.parser != nil {
+				applyParser(logEvent, g.parser)
+			}
+			if g.redactor != nil {
+				g.redactor.Redact(logEvent)
+			}
+			if g.fields != nil {
+				g.fields.apply(logEvent)
+			}
 			logEvent.ContainerID = g.containerID
-			logEvent.Level = guessLogLevel(logEvent)
+			if g.levels != nil {
+				logEvent.Level = g.levels.guess(logEvent)
+			} else {
+				logEvent.Level = guessLogLevel(logEvent)
+			}
 			g.buffer <- logEvent
 		}
 
@@ -290,7 +349,16 @@ func (g *EventGenerator) consumeReader() {
 	g.wg.Done()
 }
 
-func (g *EventGenerator) peek() *LogEvent {
+// peekTimeout is how long to wait for the line after current. Only an open
+// rule-based group, or a line that may open one, waits for slow writers.
+func (g *EventGenerator) peekTimeout(group []*LogEvent, current *LogEvent) time.Duration {
+	if g.multiline != nil && (len(group) > 0 || g.multiline.opens(current)) {
+		return multilinePeekTimeout
+	}
+	return defaultPeekTimeout
+}
+
+func (g *EventGenerator) peekWithin(timeout time.Duration) *LogEvent {
 	if g.next != nil {
 		return g.next
 	}
@@ -298,7 +366,7 @@ func (g *EventGenerator) peek() *LogEvent {
 	case event := <-g.buffer:
 		g.next = event
 		return g.next
-	case <-time.After(50 * time.Millisecond):
+	case <-time.After(timeout):
 		return nil
 	}
 }
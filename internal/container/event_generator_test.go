@@ -467,3 +467,144 @@ func TestEventGenerator_NoGroupingWhenTimestampGap(t *testing.T) {
 	require.NotNil(t, event2)
 	assert.Equal(t, LogTypeSingle, event2.Type)
 }
+
+func TestEventGenerator_MultilinePreset_GroupsSlowStackTrace(t *testing.T) {
+	// Lines arrive 200ms apart, far beyond maxGroupTimeDelta, but the java
+	// preset recognises the frames as continuations.
+	messages := []string{
+		"2020-05-13T18:55:37.000Z ERROR Request failed",
+		"2020-05-13T18:55:37.200Z java.lang.NullPointerException: boom",
+		"2020-05-13T18:55:37.400Z \tat com.example.Foo.bar(Foo.java:12)",
+		"2020-05-13T18:55:37.600Z Caused by: java.io.IOException: closed",
+		"2020-05-13T18:55:37.800Z \t... 3 more",
+		"2020-05-13T18:55:38.000Z INFO next request",
+	}
+
+	reader := &mockLogReader{
+		messages: messages,
+		types:    []StdType{STDERR, STDERR, STDERR, STDERR, STDERR, STDOUT},
+	}
+
+	g := NewEventGenerator(context.Background(), reader, Container{Labels: map[string]string{"dev.dozzle.multiline": "java"}})
+
+	event := <-g.Events
+	require.NotNil(t, event)
+	assert.Equal(t, LogTypeSingle, event.Type)
+	assert.Equal(t, "ERROR Request failed", event.Message)
+
+	event = <-g.Events
+	require.NotNil(t, event)
+	require.Equal(t, LogTypeGroup, event.Type)
+	fragments, ok := event.Message.([]LogFragment)
+	require.True(t, ok)
+	assert.Len(t, fragments, 4)
+	assert.Equal(t, "java.lang.NullPointerException: boom", fragments[0].Message)
+
+	event = <-g.Events
+	require.NotNil(t, event)
+	assert.Equal(t, LogTypeSingle, event.Type)
+	assert.Equal(t, "INFO next request", event.Message)
+}
+
+func TestEventGenerator_MultilineStartRegex(t *testing.T) {
+	messages := []string{
+		"2020-05-13T18:55:37.000Z [2020-05-13] first entry",
+		"2020-05-13T18:55:37.500Z more detail",
+		"2020-05-13T18:55:38.000Z even more",
+		"2020-05-13T18:55:38.500Z [2020-05-13] second entry",
+	}
+
+	reader := &mockLogReader{
+		messages: messages,
+		types:    []StdType{STDOUT, STDOUT, STDOUT, STDOUT},
+	}
+
+	labels := map[string]string{"dev.dozzle.multiline.start": `^\[\d{4}-\d{2}-\d{2}\]`}
+	g := NewEventGenerator(context.Background(), reader, Container{Labels: labels})
+
+	event := <-g.Events
+	require.NotNil(t, event)
+	require.Equal(t, LogTypeGroup, event.Type)
+	assert.Len(t, event.Message.([]LogFragment), 3)
+
+	event = <-g.Events
+	require.NotNil(t, event)
+	assert.Equal(t, LogTypeSingle, event.Type)
+	assert.Equal(t, "[2020-05-13] second entry", event.Message)
+}
+
+// liveReader emits its messages with delay between them and then blocks like
+// a live stream until ctx is cancelled.
+type liveReader struct {
+	ctx      context.Context
+	messages []string
+	delay    time.Duration
+	i        int
+}
+
+func (r *liveReader) Read() (string, StdType, error) {
+	if r.i >= len(r.messages) {
+		<-r.ctx.Done()
+		return "", 0, io.EOF
+	}
+	if r.i > 0 {
+		select {
+		case <-r.ctx.Done():
+			return "", 0, io.EOF
+		case <-time.After(r.delay):
+		}
+	}
+	r.i++
+	return r.messages[r.i-1], STDOUT, nil
+}
+
+func TestEventGenerator_MultilineLoneLineNotDelayed(t *testing.T) {
+	ctx := t.Context()
+	now := time.Now()
+	reader := &liveReader{ctx: ctx, messages: []string{now.Format(time.RFC3339Nano) + " INFO request served"}}
+	g := NewEventGenerator(ctx, reader, Container{StartedAt: now, Labels: map[string]string{"dev.dozzle.multiline": "java"}})
+
+	select {
+	case event := <-g.Events:
+		require.NotNil(t, event)
+		assert.Equal(t, "INFO request served", event.Message)
+	case <-time.After(multilinePeekTimeout / 2):
+		t.Fatal("a line that can't open a group waited for the multiline peek timeout")
+	}
+}
+
+func TestEventGenerator_MultilineWaitsForSlowContinuation(t *testing.T) {
+	ctx := t.Context()
+	now := time.Now()
+	reader := &liveReader{
+		ctx: ctx,
+		messages: []string{
+			now.Format(time.RFC3339Nano) + " java.lang.IllegalStateException: boom",
+			now.Add(200*time.Millisecond).Format(time.RFC3339Nano) + " \tat com.example.Foo.bar(Foo.java:12)",
+		},
+		delay: 200 * time.Millisecond,
+	}
+	g := NewEventGenerator(ctx, reader, Container{StartedAt: now, Labels: map[string]string{"dev.dozzle.multiline": "java"}})
+
+	select {
+	case event := <-g.Events:
+		require.NotNil(t, event)
+		require.Equal(t, LogTypeGroup, event.Type)
+		assert.Len(t, event.Message.([]LogFragment), 2)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event within 5s")
+	}
+}
+
+func Test_parseMultilineRule(t *testing.T) {
+	assert.Nil(t, parseMultilineRule(nil))
+	assert.Nil(t, parseMultilineRule(map[string]string{"dev.dozzle.multiline": "cobol"}))
+	assert.Nil(t, parseMultilineRule(map[string]string{"dev.dozzle.multiline.start": "("}))
+
+	rule := parseMultilineRule(map[string]string{"dev.dozzle.multiline": "python"})
+	require.NotNil(t, rule)
+	assert.True(t, rule.continues("Traceback (most recent call last):"))
+	assert.True(t, rule.continues(`  File "app.py", line 3, in <module>`))
+	assert.True(t, rule.continues("ValueError: invalid literal"))
+	assert.False(t, rule.continues("INFO: server started"))
+}
@@ -0,0 +1,218 @@
+package container
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+const (
+	levelKeyLabel     = "dev.dozzle.level.key"
+	levelMapLabel     = "dev.dozzle.level.map"
+	levelDefaultLabel = "dev.dozzle.level.default"
+	levelStderrLabel  = "dev.dozzle.level.stderr"
+)
+
+// levelMapPresets are the named numeric level schemes selectable with
+// dev.dozzle.level.map.
+var levelMapPresets = map[string]map[string]string{
+	// pino and bunyan share the same numeric levels.
+	"pino":   {"10": "trace", "20": "debug", "30": "info", "40": "warn", "50": "error", "60": "fatal"},
+	"bunyan": {"10": "trace", "20": "debug", "30": "info", "40": "warn", "50": "error", "60": "fatal"},
+	// syslog severities, RFC 5424 section 6.2.1.
+	"syslog": {"0": "fatal", "1": "fatal", "2": "fatal", "3": "error", "4": "warn", "5": "info", "6": "info", "7": "debug"},
+}
+
+// levelConfig overrides level detection for a single container.
+type levelConfig struct {
+	keys           []string          // structured keys holding the level, in priority order
+	mapping        map[string]string // raw value (lower-cased) to canonical level
+	fallback       string            // level for lines nothing was detected in
+	stderrFallback string            // fallback for stderr lines, wins over fallback
+}
+
+// parseLevelConfig reads the level labels of a container. The
+// dev.dozzle.json.level path is tried before dev.dozzle.level.key. It returns
+// nil when the container sets none, so the built-in detection is used
+// unchanged. Invalid values are logged and ignored.
+func parseLevelConfig(labels map[string]string) *levelConfig {
+	var config levelConfig
+	found := false
+
+	if path := strings.TrimSpace(labels[jsonLevelLabel]); path != "" {
+		config.keys = append(config.keys, path)
+		found = true
+	}
+
+	if keys := labels[levelKeyLabel]; keys != "" {
+		for key := range strings.SplitSeq(keys, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				config.keys = append(config.keys, key)
+				found = true
+			}
+		}
+	}
+
+	if mapping := labels[levelMapLabel]; mapping != "" {
+		config.mapping = make(map[string]string)
+		for item := range strings.SplitSeq(mapping, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			raw, level, ok := strings.Cut(item, "=")
+			if !ok {
+				preset, ok := levelMapPresets[strings.ToLower(item)]
+				if !ok {
+					log.Warn().Str("preset", item).Msg("unknown level map preset")
+					continue
+				}
+				for raw, level := range preset {
+					config.mapping[raw] = level
+				}
+				continue
+			}
+			canonical := normalizeLogLevel(strings.TrimSpace(level))
+			if canonical == "unknown" {
+				log.Warn().Str("label", levelMapLabel).Str("level", level).Msg("invalid log level")
+				continue
+			}
+			config.mapping[strings.ToLower(strings.TrimSpace(raw))] = canonical
+		}
+		found = found || len(config.mapping) > 0
+	}
+
+	config.fallback = parseLevelLabel(labels, levelDefaultLabel)
+	config.stderrFallback = parseLevelLabel(labels, levelStderrLabel)
+
+	if !found && config.fallback == "" && config.stderrFallback == "" {
+		return nil
+	}
+	return &config
+}
+
+func parseLevelLabel(labels map[string]string, label string) string {
+	value := labels[label]
+	if value == "" {
+		return ""
+	}
+	level := normalizeLogLevel(value)
+	if level == "unknown" {
+		log.Warn().Str("label", label).Str("level", value).Msg("invalid log level")
+		return ""
+	}
+	return level
+}
+
+// guess detects the level of logEvent like guessLogLevel, but reads the
+// configured keys and maps raw values through the configured mapping. Numeric
+// values such as pino's "level": 30 are supported. Fallbacks are not applied
+// here; see fallbackFor.
+func (c *levelConfig) guess(logEvent *LogEvent) string {
+	keys := c.keys
+	if len(keys) == 0 {
+		keys = levelKeys
+	}
+	if value, ok := fieldValue(logEvent.Message, keys); ok {
+		return c.normalize(value)
+	}
+	if message, ok := logEvent.Message.(string); ok {
+		return guessFromString(message)
+	}
+	return "unknown"
+}
+
+// fallbackFor returns the configured level for an event without a detected
+// level, or "unknown" when no fallback applies.
+func (c *levelConfig) fallbackFor(logEvent *LogEvent) string {
+	if logEvent.Stream == STDERR.String() && c.stderrFallback != "" {
+		return c.stderrFallback
+	}
+	if c.fallback != "" {
+		return c.fallback
+	}
+	return "unknown"
+}
+
+func (c *levelConfig) normalize(value any) string {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case float64:
+		raw = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		raw = fmt.Sprint(v)
+	}
+	if level, ok := c.mapping[strings.ToLower(strings.TrimSpace(raw))]; ok {
+		return level
+	}
+	return normalizeLogLevel(raw)
+}
+
+// FieldValue returns the value of key in a structured message, looked up
+// literally first and then as a dotted path into nested objects.
+func FieldValue(message any, key string) (any, bool) {
+	return fieldValue(message, []string{key})
+}
+
+// fieldValue returns the value of the first key present in a structured
+// message. Keys are looked up literally first, so "log.level" still matches a
+// flat key, then as a dotted path into nested objects.
+func fieldValue(message any, keys []string) (any, bool) {
+	switch value := message.(type) {
+	case *orderedmap.OrderedMap[string, any]:
+		if value == nil {
+			return nil, false
+		}
+		for _, key := range keys {
+			if v, ok := value.Get(key); ok {
+				return v, true
+			}
+			if strings.Contains(key, ".") {
+				if v, ok := lookupPath(value, key); ok {
+					return v, true
+				}
+			}
+		}
+
+	case *orderedmap.OrderedMap[string, string]:
+		if value == nil {
+			return nil, false
+		}
+		for _, key := range keys {
+			if v, ok := value.Get(key); ok {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// lookupPath walks a dotted path such as "attributes.severity" through
+// nested JSON objects.
+func lookupPath(data any, path string) (any, bool) {
+	current := data
+	for part := range strings.SplitSeq(path, ".") {
+		switch object := current.(type) {
+		case *orderedmap.OrderedMap[string, any]:
+			value, ok := object.Get(part)
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case map[string]any:
+			value, ok := object[part]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
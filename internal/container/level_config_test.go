@@ -0,0 +1,108 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+func TestLevelConfig_guess(t *testing.T) {
+	jsonMessage := func(s string) *orderedmap.OrderedMap[string, any] {
+		data := orderedmap.New[string, any]()
+		require.NoError(t, json.Unmarshal([]byte(s), &data))
+		return data
+	}
+
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		message  any
+		expected string
+	}{
+		{
+			name:     "pino numeric level",
+			labels:   map[string]string{"dev.dozzle.level.map": "pino"},
+			message:  jsonMessage(`{"level":50,"msg":"boom"}`),
+			expected: "error",
+		},
+		{
+			name:     "custom key",
+			labels:   map[string]string{"dev.dozzle.level.key": "lvl"},
+			message:  jsonMessage(`{"level":"info","lvl":"WARNING"}`),
+			expected: "warn",
+		},
+		{
+			name:     "nested key",
+			labels:   map[string]string{"dev.dozzle.level.key": "attributes.severity"},
+			message:  jsonMessage(`{"body":"hi","attributes":{"severity":"ERROR"}}`),
+			expected: "error",
+		},
+		{
+			name:     "custom mapping on logfmt",
+			labels:   map[string]string{"dev.dozzle.level.key": "sev", "dev.dozzle.level.map": "E=error,W=warn"},
+			message:  orderedmap.New[string, string](orderedmap.WithInitialData(orderedmap.Pair[string, string]{Key: "sev", Value: "w"})),
+			expected: "warn",
+		},
+		{
+			name:     "syslog preset",
+			labels:   map[string]string{"dev.dozzle.level.key": "priority", "dev.dozzle.level.map": "syslog"},
+			message:  jsonMessage(`{"priority":"3"}`),
+			expected: "error",
+		},
+		{
+			name:     "plain text still guessed",
+			labels:   map[string]string{"dev.dozzle.level.map": "pino"},
+			message:  "ERROR: boom",
+			expected: "error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := parseLevelConfig(tt.labels)
+			require.NotNil(t, config)
+			assert.Equal(t, tt.expected, config.guess(&LogEvent{Message: tt.message}))
+		})
+	}
+}
+
+func Test_parseLevelConfig(t *testing.T) {
+	assert.Nil(t, parseLevelConfig(nil))
+	assert.Nil(t, parseLevelConfig(map[string]string{"dev.dozzle.level.default": "loud"}))
+	assert.Nil(t, parseLevelConfig(map[string]string{"dev.dozzle.level.map": "log4j"}))
+
+	config := parseLevelConfig(map[string]string{"dev.dozzle.level.default": "information", "dev.dozzle.level.stderr": "err"})
+	require.NotNil(t, config)
+	assert.Equal(t, "info", config.fallbackFor(&LogEvent{Stream: "stdout"}))
+	assert.Equal(t, "error", config.fallbackFor(&LogEvent{Stream: "stderr"}))
+}
+
+func TestEventGenerator_LevelFallbackKeepsGrouping(t *testing.T) {
+	messages := []string{
+		"2020-05-13T18:55:37.000Z WARN: disk almost full",
+		"2020-05-13T18:55:37.001Z   at /var/lib/data",
+		"2020-05-13T18:55:38.000Z starting worker",
+	}
+
+	reader := &mockLogReader{
+		messages: messages,
+		types:    []StdType{STDOUT, STDOUT, STDERR},
+	}
+
+	labels := map[string]string{"dev.dozzle.level.stderr": "error"}
+	g := NewEventGenerator(context.Background(), reader, Container{Labels: labels})
+
+	event := <-g.Events
+	require.NotNil(t, event)
+	assert.Equal(t, LogTypeGroup, event.Type)
+	assert.Equal(t, "warn", event.Level)
+
+	event = <-g.Events
+	require.NotNil(t, event)
+	assert.Equal(t, LogTypeSingle, event.Type)
+	assert.Equal(t, "error", event.Level)
+}
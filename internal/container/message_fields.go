@@ -0,0 +1,209 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	jsonMessageLabel   = "dev.dozzle.json.message"
+	jsonLevelLabel     = "dev.dozzle.json.level"
+	jsonTimestampLabel = "dev.dozzle.json.timestamp"
+
+	// timestampSourceLabel opts a container into ordering by the timestamps
+	// its application writes, when set to "message".
+	timestampSourceLabel = "dev.dozzle.timestamp"
+)
+
+// messageTimestampKeys are the structured keys read for message timestamps
+// when no dev.dozzle.json.timestamp path is configured.
+var messageTimestampKeys = []string{"ts", "time", "@t"}
+
+// UsesMessageTimestamps reports whether the events of c are ordered by the
+// timestamps in their messages rather than by the log driver.
+func UsesMessageTimestamps(c Container) bool {
+	return strings.EqualFold(strings.TrimSpace(c.Labels[timestampSourceLabel]), "message")
+}
+
+// applyMessageTimestamp sets the message timestamp of logEvent to the one
+// written by the application, read from messageTimestampKeys or a timestamp at
+// the start of a plain line. Groups use the timestamp of their first line.
+// Events that already carry a message timestamp are left alone.
+func applyMessageTimestamp(logEvent *LogEvent) {
+	if logEvent.MessageTimestamp != 0 {
+		return
+	}
+	var value any
+	switch message := logEvent.Message.(type) {
+	case string:
+		value = leadingTimestamp(message)
+	case []LogFragment:
+		if len(message) > 0 {
+			value = leadingTimestamp(message[0].Message)
+		}
+	default:
+		value, _ = fieldValue(message, messageTimestampKeys)
+	}
+	if timestamp, ok := parseTimestampValue(value, logEvent.Timestamp); ok {
+		logEvent.MessageTimestamp = timestamp.UnixMilli()
+	}
+}
+
+// leadingTimestamp returns the timestamp at the start of message, as matched by
+// timestampRegex, or an empty string.
+func leadingTimestamp(message string) string {
+	return strings.TrimSpace(timestampRegex.FindString(StripANSI(message)))
+}
+
+// messageFields names where the message and timestamp live in the structured
+// logs of a container, e.g. "body" and "observedTimestamp" for OpenTelemetry.
+// The level path is handled by levelConfig.
+type messageFields struct {
+	message   string
+	timestamp string
+}
+
+// parseMessageFields reads the JSON path labels of a container. It returns nil
+// when the container sets none.
+func parseMessageFields(labels map[string]string) *messageFields {
+	fields := messageFields{
+		message:   strings.TrimSpace(labels[jsonMessageLabel]),
+		timestamp: strings.TrimSpace(labels[jsonTimestampLabel]),
+	}
+	if fields.message == "" && fields.timestamp == "" {
+		return nil
+	}
+	return &fields
+}
+
+// apply sets the summary and message timestamp of a structured event from the
+// configured paths.
+func (f *messageFields) apply(logEvent *LogEvent) {
+	if logEvent.Type != LogTypeComplex {
+		return
+	}
+	if f.message != "" {
+		if value, ok := fieldValue(logEvent.Message, []string{f.message}); ok {
+			logEvent.Summary = stringifyField(value)
+		}
+	}
+	if f.timestamp != "" {
+		if value, ok := fieldValue(logEvent.Message, []string{f.timestamp}); ok {
+			if timestamp, ok := parseTimestampValue(value, logEvent.Timestamp); ok {
+				logEvent.MessageTimestamp = timestamp.UnixMilli()
+			}
+		}
+	}
+}
+
+func stringifyField(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	case float64, bool:
+		return fmt.Sprint(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
+
+// zonedTimestampLayouts are the textual formats with a zone accepted in
+// messages, tried in order.
+var zonedTimestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+}
+
+// localTimestampLayouts are the textual formats without a zone accepted in
+// messages, tried after zonedTimestampLayouts. They are in the local time of
+// the container, see inDriverOffset.
+var localTimestampLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006/01/02 15:04:05.999999999",
+	"2006-01-02 15:04:05,999999999",
+	"2006-01-02 03:04PM",
+	"2006/01/02 03:04PM",
+}
+
+// minZoneOffset and maxZoneOffset bound the UTC offsets of real time zones.
+const (
+	minZoneOffset = -12 * time.Hour
+	maxZoneOffset = 14 * time.Hour
+)
+
+// parseTimestampValue reads a timestamp from a message field of an event logged
+// by the driver at driverTimestamp, in milliseconds. Strings are parsed with
+// zonedTimestampLayouts and localTimestampLayouts; numbers are Unix epochs whose
+// unit (seconds, milliseconds, microseconds or nanoseconds) is inferred from
+// their magnitude.
+func parseTimestampValue(value any, driverTimestamp int64) (time.Time, bool) {
+	switch v := value.(type) {
+	case float64:
+		return epochToTime(v)
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return time.Time{}, false
+		}
+		for _, layout := range zonedTimestampLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+		for _, layout := range localTimestampLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return inDriverOffset(t, driverTimestamp)
+			}
+		}
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return epochToTime(n)
+		}
+	}
+	return time.Time{}, false
+}
+
+// inDriverOffset reads the wall clock t, parsed as UTC from a value without a
+// zone, in the local time of the container. Docker does not know the zone of
+// the application, so its offset is taken as the difference to the driver
+// timestamp rounded to 15 minutes, the granularity of real zones. Values
+// without a driver timestamp, or too far from it to be in any zone, are
+// rejected.
+func inDriverOffset(t time.Time, driverTimestamp int64) (time.Time, bool) {
+	if driverTimestamp <= 0 {
+		return time.Time{}, false
+	}
+	offset := t.Sub(time.UnixMilli(driverTimestamp)).Round(15 * time.Minute)
+	if offset < minZoneOffset || offset > maxZoneOffset {
+		return time.Time{}, false
+	}
+	return t.Add(-offset), true
+}
+
+func epochToTime(n float64) (time.Time, bool) {
+	if n <= 0 || math.IsNaN(n) || math.IsInf(n, 0) {
+		return time.Time{}, false
+	}
+	switch {
+	case n < 1e11:
+		// fractional seconds are only precise to about a microsecond
+		sec, frac := math.Modf(n)
+		return time.Unix(int64(sec), int64(math.Round(frac*1e6))*1e3), true
+	case n < 1e14:
+		return time.UnixMilli(int64(n)), true
+	case n < 1e17:
+		return time.UnixMicro(int64(n)), true
+	default:
+		return time.Unix(0, int64(n)), true
+	}
+}
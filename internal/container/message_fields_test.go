@@ -0,0 +1,134 @@
+package container
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseTimestampValue(t *testing.T) {
+	expected := time.Date(2024, 3, 1, 12, 30, 45, 123000000, time.UTC)
+	// the driver logged the line shortly after the application wrote it
+	driverTimestamp := expected.Add(200 * time.Millisecond).UnixMilli()
+
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"rfc3339", "2024-03-01T12:30:45.123Z"},
+		{"rfc3339 with offset", "2024-03-01T14:30:45.123+02:00"},
+		{"space separated", "2024-03-01 12:30:45.123"},
+		{"comma fraction", "2024-03-01 12:30:45,123"},
+		{"local time ahead of UTC", "2024-03-01 14:30:45.123"},
+		{"local time behind UTC", "2024-03-01T07:00:45.123"},
+		{"epoch seconds", float64(expected.UnixMilli()) / 1000},
+		{"epoch milliseconds", float64(expected.UnixMilli())},
+		{"epoch nanoseconds", float64(expected.UnixNano())},
+		{"numeric string", "1709296245123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := parseTimestampValue(tt.value, driverTimestamp)
+			require.True(t, ok)
+			assert.Equal(t, expected.UnixMilli(), actual.UnixMilli())
+		})
+	}
+
+	for _, value := range []any{"yesterday", "", true, float64(-1), nil} {
+		_, ok := parseTimestampValue(value, driverTimestamp)
+		assert.False(t, ok, "%v", value)
+	}
+
+	_, ok := parseTimestampValue("2024-03-01 12:30:45.123", 0)
+	assert.False(t, ok, "no zone and no driver timestamp")
+	_, ok = parseTimestampValue("2024-03-03 12:30:45.123", driverTimestamp)
+	assert.False(t, ok, "too far from the driver timestamp for any zone")
+	actual, ok := parseTimestampValue("2024-03-01T14:30:45.123+02:00", 0)
+	require.True(t, ok, "zoned values do not need a driver timestamp")
+	assert.Equal(t, expected.UnixMilli(), actual.UnixMilli())
+}
+
+func TestEventGenerator_JSONPaths(t *testing.T) {
+	messages := []string{
+		`2020-05-13T18:55:37.772853839Z {"body":"payment failed","attributes":{"severity":"ERROR"},"observedTimestamp":"2020-05-13T18:55:30.5Z"}`,
+		`2020-05-13T18:55:38.000000000Z {"body":{"code":42},"attributes":{"severity":"info"},"observedTimestamp":"soon"}`,
+	}
+
+	reader := &mockLogReader{
+		messages: messages,
+		types:    []StdType{STDOUT, STDOUT},
+	}
+
+	labels := map[string]string{
+		"dev.dozzle.json.message":   "body",
+		"dev.dozzle.json.level":     "attributes.severity",
+		"dev.dozzle.json.timestamp": "observedTimestamp",
+	}
+	g := NewEventGenerator(context.Background(), reader, Container{Labels: labels})
+
+	event := <-g.Events
+	require.NotNil(t, event)
+	assert.Equal(t, LogTypeComplex, event.Type)
+	assert.Equal(t, "payment failed", event.Summary)
+	assert.Equal(t, "error", event.Level)
+	assert.Equal(t, time.Date(2020, 5, 13, 18, 55, 37, 772000000, time.UTC).UnixMilli(), event.Timestamp)
+	assert.Equal(t, time.Date(2020, 5, 13, 18, 55, 30, 500000000, time.UTC).UnixMilli(), event.MessageTimestamp)
+
+	event = <-g.Events
+	require.NotNil(t, event)
+	assert.Equal(t, `{"code":42}`, event.Summary)
+	assert.Equal(t, "info", event.Level)
+	assert.Equal(t, time.Date(2020, 5, 13, 18, 55, 38, 0, time.UTC).UnixMilli(), event.Timestamp)
+	assert.Zero(t, event.MessageTimestamp)
+}
+
+func Test_parseMessageFields(t *testing.T) {
+	assert.Nil(t, parseMessageFields(nil))
+	assert.Nil(t, parseMessageFields(map[string]string{"dev.dozzle.json.level": "severity"}))
+	assert.Equal(t, &messageFields{message: "body"}, parseMessageFields(map[string]string{"dev.dozzle.json.message": " body "}))
+}
+
+func TestEventGenerator_MessageTimestamps(t *testing.T) {
+	messages := []string{
+		`2020-05-13T18:55:37.772853839Z {"ts":1589396100.25,"msg":"buffered"}`,
+		"2020-05-13T18:55:38.000000000Z 2020-05-13 20:54:00 ERROR: boom",
+		"2020-05-13T18:55:38.001000000Z   at handler",
+		"2020-05-13T18:55:39.000000000Z no timestamp here",
+	}
+
+	reader := &mockLogReader{
+		messages: messages,
+		types:    []StdType{STDOUT, STDOUT, STDOUT, STDOUT},
+	}
+
+	g := NewEventGenerator(context.Background(), reader, Container{Labels: map[string]string{"dev.dozzle.timestamp": "message"}})
+
+	event := <-g.Events
+	require.NotNil(t, event)
+	assert.Equal(t, time.Date(2020, 5, 13, 18, 55, 37, 772000000, time.UTC).UnixMilli(), event.Timestamp)
+	assert.Equal(t, int64(1589396100250), event.MessageTimestamp)
+	assert.Equal(t, event.MessageTimestamp, event.SortTimestamp())
+
+	event = <-g.Events
+	require.NotNil(t, event)
+	assert.Equal(t, LogTypeGroup, event.Type)
+	assert.Equal(t, time.Date(2020, 5, 13, 18, 55, 38, 0, time.UTC).UnixMilli(), event.Timestamp)
+	// a zone-less time two hours ahead of the driver is read as UTC+2
+	assert.Equal(t, time.Date(2020, 5, 13, 18, 54, 0, 0, time.UTC).UnixMilli(), event.MessageTimestamp)
+
+	event = <-g.Events
+	require.NotNil(t, event)
+	assert.Equal(t, time.Date(2020, 5, 13, 18, 55, 39, 0, time.UTC).UnixMilli(), event.Timestamp)
+	assert.Zero(t, event.MessageTimestamp)
+	assert.Equal(t, event.Timestamp, event.SortTimestamp())
+}
+
+func Test_UsesMessageTimestamps(t *testing.T) {
+	assert.False(t, UsesMessageTimestamps(Container{}))
+	assert.False(t, UsesMessageTimestamps(Container{Labels: map[string]string{"dev.dozzle.timestamp": "driver"}}))
+	assert.True(t, UsesMessageTimestamps(Container{Labels: map[string]string{"dev.dozzle.timestamp": "Message"}}))
+}
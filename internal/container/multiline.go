@@ -0,0 +1,142 @@
+package container
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	multilinePresetLabel   = "dev.dozzle.multiline"
+	multilineStartLabel    = "dev.dozzle.multiline.start"
+	multilineContinueLabel = "dev.dozzle.multiline.continue"
+)
+
+// maxMultilineTimeDelta is the maximum time difference (in milliseconds)
+// between consecutive lines of a rule-based group. Rules identify
+// continuations by shape rather than timing, so this is far more forgiving
+// than maxGroupTimeDelta and only guards against joining unrelated output.
+const maxMultilineTimeDelta = 5000
+
+// defaultPeekTimeout is how long a live stream waits for the line after the
+// current one before emitting it on its own.
+const defaultPeekTimeout = 50 * time.Millisecond
+
+// multilinePeekTimeout is how long a live stream waits for the next line while
+// a rule-based group is open or about to open. Slow writers flush stack traces
+// line by line, so the default peek would split them.
+const multilinePeekTimeout = 1 * time.Second
+
+// maxMultilineLines bounds a single rule-based group. A start-only rule treats
+// every non-matching line as a continuation, so a container that never prints
+// a start line would otherwise grow one group forever.
+const maxMultilineLines = 500
+
+// multilineRule decides group boundaries from the shape of a line. A line
+// matching start always begins a new entry. When continuation is set, only
+// lines matching it are appended to the open entry; otherwise every line that
+// doesn't match start is. Header matches lines that usually open an entry,
+// like the exception line above a stack trace, and only decides how long a
+// live stream waits for their continuations.
+type multilineRule struct {
+	start        *regexp.Regexp
+	continuation *regexp.Regexp
+	header       *regexp.Regexp
+}
+
+// multilinePresets are the named rules selectable with dev.dozzle.multiline.
+var multilinePresets = map[string]multilineRule{
+	// Java/JVM stack traces: "\tat com.foo.Bar(Bar.java:12)", "Caused by: ...",
+	// "\t... 3 more" and "... 12 common frames omitted".
+	"java": {
+		continuation: regexp.MustCompile(`^(\s+at\s|\s*\.\.\. \d+ (more|common frames omitted)|(Caused by|Suppressed):\s)`),
+		header:       regexp.MustCompile(`(Exception|Error|Throwable)(:|$)`),
+	},
+	// Python tracebacks, including chained exceptions and the final
+	// "ValueError: message" line.
+	"python": {
+		continuation: regexp.MustCompile(`^(\s+\S|Traceback \(most recent call last\):|During handling of the above exception|The above exception was the direct cause|[A-Za-z_][\w.]*(Error|Exception|Warning|Exit|Interrupt)(:|$))`),
+		header:       regexp.MustCompile(`Traceback \(most recent call last\):`),
+	},
+	// Any indented line continues the previous one.
+	"indent": {
+		continuation: regexp.MustCompile(`^\s+\S`),
+	},
+}
+
+// parseMultilineRule reads the multiline labels of a container. It returns nil
+// when the container declares no rule, in which case the generator falls back
+// to timing-based grouping. Invalid presets and regexes are logged and ignored.
+func parseMultilineRule(labels map[string]string) *multilineRule {
+	var rule multilineRule
+
+	if name := strings.ToLower(strings.TrimSpace(labels[multilinePresetLabel])); name != "" {
+		preset, ok := multilinePresets[name]
+		if !ok {
+			log.Warn().Str("preset", name).Msg("unknown multiline preset")
+		} else {
+			rule = preset
+		}
+	}
+
+	if pattern := labels[multilineStartLabel]; pattern != "" {
+		if re, err := regexp.Compile(pattern); err != nil {
+			log.Warn().Err(err).Str("label", multilineStartLabel).Msg("invalid multiline regex")
+		} else {
+			rule.start = re
+		}
+	}
+
+	if pattern := labels[multilineContinueLabel]; pattern != "" {
+		if re, err := regexp.Compile(pattern); err != nil {
+			log.Warn().Err(err).Str("label", multilineContinueLabel).Msg("invalid multiline regex")
+		} else {
+			rule.continuation = re
+		}
+	}
+
+	if rule.start == nil && rule.continuation == nil {
+		return nil
+	}
+	return &rule
+}
+
+// continues reports whether message should be appended to the open entry.
+func (r *multilineRule) continues(message string) bool {
+	message = StripANSI(message)
+	if r.start != nil && r.start.MatchString(message) {
+		return false
+	}
+	if r.continuation != nil {
+		return r.continuation.MatchString(message)
+	}
+	return true
+}
+
+// opens reports whether event may be followed by continuations, so a live
+// stream should wait for them.
+func (r *multilineRule) opens(event *LogEvent) bool {
+	message, ok := event.Message.(string)
+	if !ok {
+		return false
+	}
+	message = StripANSI(message)
+	return (r.start != nil && r.start.MatchString(message)) ||
+		(r.header != nil && r.header.MatchString(message)) ||
+		(r.continuation != nil && r.continuation.MatchString(message))
+}
+
+// canContinue checks if next can be appended after prev in a rule-based group.
+func (r *multilineRule) canContinue(prev, next *LogEvent) bool {
+	message, ok := next.Message.(string)
+	if !ok || !r.continues(message) {
+		return false
+	}
+	if prev.Timestamp == 0 || next.Timestamp == 0 {
+		return true
+	}
+	delta := next.Timestamp - prev.Timestamp
+	return delta > -maxMultilineTimeDelta && delta < maxMultilineTimeDelta
+}
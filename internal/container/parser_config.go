@@ -0,0 +1,155 @@
+package container
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	DefaultParserConfigPath = "./data/parsers.yml"
+
+	parserRegexLabel = "dev.dozzle.parser.regex"
+)
+
+// ParserConfig is the on-disk format of parsers.yml. Each entry defines a
+// named regex parser that containers can select with dev.dozzle.parser, and
+// optionally applies it to containers matching Match without any label.
+type ParserConfig struct {
+	Parsers []ParserDefinition `yaml:"parsers"`
+}
+
+type ParserDefinition struct {
+	Name  string       `yaml:"name"`
+	Regex string       `yaml:"regex"`
+	Match *ParserMatch `yaml:"match,omitempty"`
+}
+
+// ParserMatch selects containers by name glob and/or exact label values. All
+// given conditions must hold.
+type ParserMatch struct {
+	Name   string            `yaml:"name,omitempty"`
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+type matchedParser struct {
+	match  ParserMatch
+	parser LogParser
+}
+
+// matchedParsers holds the parsers.yml entries with a match selector, in file
+// order. It is swapped atomically so generators never see a partial load.
+var matchedParsers atomic.Pointer[[]matchedParser]
+
+// NewRegexParser returns a parser that maps the named capture groups of
+// pattern to fields. The pattern must have at least one named group.
+func NewRegexParser(pattern string) (LogParser, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	named := false
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			named = true
+			break
+		}
+	}
+	if !named {
+		return nil, fmt.Errorf("regex %q has no named capture groups", pattern)
+	}
+	return &regexParser{re: re}, nil
+}
+
+// LoadParserConfig reads parsers.yml from r, registers its parsers and replaces
+// the container match rules.
+func LoadParserConfig(r io.Reader) error {
+	var config ParserConfig
+	if err := yaml.NewDecoder(r).Decode(&config); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to decode parser config: %w", err)
+	}
+
+	matched := make([]matchedParser, 0)
+	for _, def := range config.Parsers {
+		if def.Name == "" {
+			return fmt.Errorf("parser name is required")
+		}
+		parser, err := NewRegexParser(def.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid parser %s: %w", def.Name, err)
+		}
+		RegisterLogParser(strings.ToLower(def.Name), parser)
+		if def.Match != nil {
+			matched = append(matched, matchedParser{match: *def.Match, parser: parser})
+		}
+	}
+
+	matchedParsers.Store(&matched)
+	return nil
+}
+
+// LoadParserConfigFile loads the parser config at path. A missing file is not
+// an error.
+func LoadParserConfigFile(path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	if err := LoadParserConfig(file); err != nil {
+		log.Warn().Err(err).Msg("Could not load parser config")
+	} else {
+		log.Debug().Str("path", path).Msg("Loaded parser config")
+	}
+}
+
+func (m ParserMatch) matches(c Container) bool {
+	if m.Name == "" && len(m.Labels) == 0 {
+		return false
+	}
+	if m.Name != "" {
+		if ok, _ := path.Match(m.Name, c.Name); !ok {
+			return false
+		}
+	}
+	for key, value := range m.Labels {
+		if c.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// parserForContainer resolves the parser for c. An inline regex label wins
+// over a named parser label, which wins over a parsers.yml match rule.
+func parserForContainer(c Container) LogParser {
+	if pattern := c.Labels[parserRegexLabel]; pattern != "" {
+		parser, err := NewRegexParser(pattern)
+		if err != nil {
+			log.Warn().Err(err).Str("label", parserRegexLabel).Msg("invalid parser regex")
+		} else {
+			return parser
+		}
+	}
+
+	if parser := parseLogParser(c.Labels); parser != nil {
+		return parser
+	}
+
+	if matched := matchedParsers.Load(); matched != nil {
+		for _, m := range *matched {
+			if m.match.matches(c) {
+				return m.parser
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package container
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+const parserLabel = "dev.dozzle.parser"
+
+// autoParserName selects every registered parser, tried in registration order.
+const autoParserName = "auto"
+
+// LogParser extracts structured fields from a plain text log line.
+type LogParser interface {
+	// Parse returns the fields of message in display order, or false when the
+	// line isn't in the parser's format.
+	Parse(message string) (*orderedmap.OrderedMap[string, string], bool)
+}
+
+var (
+	logParsers     = map[string]LogParser{}
+	logParserOrder []string
+)
+
+// RegisterLogParser makes a parser selectable with the dev.dozzle.parser label.
+// Registering a name twice replaces the earlier parser but keeps its position
+// in auto-detection. It is not safe for concurrent use and should only be
+// called during startup, before any logs are read.
+func RegisterLogParser(name string, parser LogParser) {
+	if _, ok := logParsers[name]; !ok {
+		logParserOrder = append(logParserOrder, name)
+	}
+	logParsers[name] = parser
+}
+
+// regexParser maps the named capture groups of re to fields. Groups that don't
+// participate in the match are omitted. transform may rewrite the fields after
+// extraction, e.g. to decode a syslog priority.
+type regexParser struct {
+	re        *regexp.Regexp
+	transform func(fields *orderedmap.OrderedMap[string, string])
+}
+
+func (p *regexParser) Parse(message string) (*orderedmap.OrderedMap[string, string], bool) {
+	match := p.re.FindStringSubmatch(message)
+	if match == nil {
+		return nil, false
+	}
+	fields := orderedmap.New[string, string]()
+	for i, name := range p.re.SubexpNames() {
+		if i == 0 || name == "" || match[i] == "" {
+			continue
+		}
+		fields.Set(name, match[i])
+	}
+	if p.transform != nil {
+		p.transform(fields)
+	}
+	return fields, true
+}
+
+// autoParser tries every registered parser and keeps the first match.
+type autoParser struct{}
+
+func (autoParser) Parse(message string) (*orderedmap.OrderedMap[string, string], bool) {
+	for _, name := range logParserOrder {
+		if fields, ok := logParsers[name].Parse(message); ok {
+			return fields, true
+		}
+	}
+	return nil, false
+}
+
+// combinedLog matches the NCSA common and combined log formats written by
+// nginx, Apache and most proxies.
+const combinedLog = `^(?P<remote_addr>\S+) \S+ (?P<remote_user>\S+) \[(?P<time>[^\]]+)\] "(?P<method>[A-Z]+) (?P<path>\S+)(?: (?P<protocol>[^"]+))?" (?P<status>\d{3}) (?P<bytes>\d+|-)(?: "(?P<referer>[^"]*)" "(?P<user_agent>[^"]*)")?`
+
+// syslogSeverities maps syslog severities (RFC 5424 section 6.2.1) to Dozzle
+// levels.
+var syslogSeverities = []string{"fatal", "fatal", "fatal", "error", "warn", "info", "info", "debug"}
+
+// decodeSyslogPriority replaces the raw <PRI> value with facility and level.
+func decodeSyslogPriority(fields *orderedmap.OrderedMap[string, string]) {
+	pri, ok := fields.Delete("priority")
+	if !ok {
+		return
+	}
+	n, err := strconv.Atoi(pri)
+	if err != nil || n > 191 {
+		return
+	}
+	fields.Set("facility", strconv.Itoa(n/8))
+	fields.Set("level", syslogSeverities[n%8])
+}
+
+func init() {
+	combined := &regexParser{re: regexp.MustCompile(combinedLog + `$`)}
+	RegisterLogParser("nginx", combined)
+	RegisterLogParser("apache", combined)
+
+	// Traefik's CLF access log appends the request count, router, backend URL
+	// and duration to the combined format.
+	RegisterLogParser("traefik", &regexParser{
+		re: regexp.MustCompile(combinedLog + ` (?P<request_count>\d+) "(?P<router>[^"]*)" "(?P<backend_url>[^"]*)" (?P<duration_ms>\d+)ms$`),
+	})
+
+	RegisterLogParser("syslog", &syslogParser{
+		rfc5424: &regexParser{
+			re:        regexp.MustCompile(`^<(?P<priority>\d{1,3})>1 (?P<timestamp>\S+) (?P<hostname>\S+) (?P<app_name>\S+) (?P<procid>\S+) (?P<msgid>\S+) (?P<structured_data>-|(?:\[(?:[^\]\\]|\\.)*\])+)(?: (?P<message>.*))?$`),
+			transform: decodeSyslogPriority,
+		},
+		rfc3164: &regexParser{
+			re:        regexp.MustCompile(`^(?:<(?P<priority>\d{1,3})>)?(?P<timestamp>[A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}) (?P<hostname>\S+) (?P<app_name>[^:\[\s]+)(?:\[(?P<procid>\d+)\])?: (?P<message>.*)$`),
+			transform: decodeSyslogPriority,
+		},
+	})
+
+	RegisterLogParser("klog", &regexParser{
+		re: regexp.MustCompile(`^(?P<level>[IWEF])(?P<time>\d{4} \d{2}:\d{2}:\d{2}\.\d{6})\s+(?P<thread_id>\d+) (?P<file>[^:\s]+):(?P<line>\d+)\] (?P<message>.*)$`),
+		transform: func(fields *orderedmap.OrderedMap[string, string]) {
+			if level, ok := fields.Get("level"); ok {
+				fields.Set("level", singleLetterToLevel[level[0]])
+			}
+		},
+	})
+}
+
+// syslogParser accepts both RFC 5424 and the older BSD (RFC 3164) format.
+type syslogParser struct {
+	rfc5424 LogParser
+	rfc3164 LogParser
+}
+
+func (p *syslogParser) Parse(message string) (*orderedmap.OrderedMap[string, string], bool) {
+	if fields, ok := p.rfc5424.Parse(message); ok {
+		return fields, true
+	}
+	return p.rfc3164.Parse(message)
+}
+
+// parseLogParser returns the parser selected by the dev.dozzle.parser label, or
+// nil when the container doesn't set one. Unknown names are logged and ignored.
+func parseLogParser(labels map[string]string) LogParser {
+	name := strings.ToLower(strings.TrimSpace(labels[parserLabel]))
+	if name == "" {
+		return nil
+	}
+	if name == autoParserName {
+		return autoParser{}
+	}
+	parser, ok := logParsers[name]
+	if !ok {
+		log.Warn().Str("parser", name).Msg("unknown log parser")
+		return nil
+	}
+	return parser
+}
+
+// applyParser promotes a plain text event to LogTypeComplex when parser
+// recognises it. RawMessage keeps the original line.
+func applyParser(logEvent *LogEvent, parser LogParser) {
+	if logEvent.Type != LogTypeSingle {
+		return
+	}
+	message, ok := logEvent.Message.(string)
+	if !ok || message == "" {
+		return
+	}
+	if fields, ok := parser.Parse(StripANSI(message)); ok {
+		logEvent.Message = fields
+		logEvent.Type = LogTypeComplex
+		logEvent.RawMessage = message
+	}
+}
@@ -0,0 +1,234 @@
+package container
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+func TestLogParsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		parser  string
+		message string
+		want    map[string]string
+	}{
+		{
+			name:    "nginx combined",
+			parser:  "nginx",
+			message: `172.17.0.1 - - [10/Oct/2024:13:55:36 +0000] "GET /api/health HTTP/1.1" 200 612 "-" "curl/8.4.0"`,
+			want: map[string]string{
+				"remote_addr": "172.17.0.1",
+				"remote_user": "-",
+				"time":        "10/Oct/2024:13:55:36 +0000",
+				"method":      "GET",
+				"path":        "/api/health",
+				"protocol":    "HTTP/1.1",
+				"status":      "200",
+				"bytes":       "612",
+				"referer":     "-",
+				"user_agent":  "curl/8.4.0",
+			},
+		},
+		{
+			name:    "apache common",
+			parser:  "apache",
+			message: `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 404 -`,
+			want: map[string]string{
+				"remote_addr": "127.0.0.1",
+				"remote_user": "frank",
+				"time":        "10/Oct/2000:13:55:36 -0700",
+				"method":      "GET",
+				"path":        "/apache_pb.gif",
+				"protocol":    "HTTP/1.0",
+				"status":      "404",
+				"bytes":       "-",
+			},
+		},
+		{
+			name:    "traefik",
+			parser:  "traefik",
+			message: `10.0.0.5 - - [10/Oct/2024:13:55:36 +0000] "POST /login HTTP/2.0" 502 11 "-" "Mozilla/5.0" 42 "web@docker" "http://172.18.0.3:8080" 13ms`,
+			want: map[string]string{
+				"remote_addr":   "10.0.0.5",
+				"remote_user":   "-",
+				"time":          "10/Oct/2024:13:55:36 +0000",
+				"method":        "POST",
+				"path":          "/login",
+				"protocol":      "HTTP/2.0",
+				"status":        "502",
+				"bytes":         "11",
+				"referer":       "-",
+				"user_agent":    "Mozilla/5.0",
+				"request_count": "42",
+				"router":        "web@docker",
+				"backend_url":   "http://172.18.0.3:8080",
+				"duration_ms":   "13",
+			},
+		},
+		{
+			name:    "syslog rfc5424",
+			parser:  "syslog",
+			message: `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3"] An application event`,
+			want: map[string]string{
+				"timestamp":       "2003-10-11T22:14:15.003Z",
+				"hostname":        "mymachine.example.com",
+				"app_name":        "evntslog",
+				"procid":          "-",
+				"msgid":           "ID47",
+				"structured_data": `[exampleSDID@32473 iut="3"]`,
+				"message":         "An application event",
+				"facility":        "20",
+				"level":           "info",
+			},
+		},
+		{
+			name:    "syslog rfc3164",
+			parser:  "syslog",
+			message: `<34>Oct 11 22:14:15 mymachine su[230]: 'su root' failed for lonvick on /dev/pts/8`,
+			want: map[string]string{
+				"timestamp": "Oct 11 22:14:15",
+				"hostname":  "mymachine",
+				"app_name":  "su",
+				"procid":    "230",
+				"message":   "'su root' failed for lonvick on /dev/pts/8",
+				"facility":  "4",
+				"level":     "fatal",
+			},
+		},
+		{
+			name:    "klog",
+			parser:  "klog",
+			message: `E0806 14:55:55.980915       1 fsHandler.go:121] failed to collect filesystem stats`,
+			want: map[string]string{
+				"level":     "error",
+				"time":      "0806 14:55:55.980915",
+				"thread_id": "1",
+				"file":      "fsHandler.go",
+				"line":      "121",
+				"message":   "failed to collect filesystem stats",
+			},
+		},
+		{
+			name:    "auto detects traefik",
+			parser:  "auto",
+			message: `10.0.0.5 - - [10/Oct/2024:13:55:36 +0000] "GET / HTTP/1.1" 200 1 "-" "-" 1 "r" "u" 2ms`,
+			want: map[string]string{
+				"remote_addr":   "10.0.0.5",
+				"remote_user":   "-",
+				"time":          "10/Oct/2024:13:55:36 +0000",
+				"method":        "GET",
+				"path":          "/",
+				"protocol":      "HTTP/1.1",
+				"status":        "200",
+				"bytes":         "1",
+				"referer":       "-",
+				"user_agent":    "-",
+				"request_count": "1",
+				"router":        "r",
+				"backend_url":   "u",
+				"duration_ms":   "2",
+			},
+		},
+		{
+			name:    "no match",
+			parser:  "nginx",
+			message: "server started on port 8080",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := parseLogParser(map[string]string{"dev.dozzle.parser": tt.parser})
+			require.NotNil(t, parser)
+
+			fields, ok := parser.Parse(tt.message)
+			if tt.want == nil {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			got := make(map[string]string)
+			for pair := fields.Oldest(); pair != nil; pair = pair.Next() {
+				got[pair.Key] = pair.Value
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseLogParser_unknown(t *testing.T) {
+	assert.Nil(t, parseLogParser(nil))
+	assert.Nil(t, parseLogParser(map[string]string{"dev.dozzle.parser": "cobol"}))
+}
+
+func TestEventGenerator_ParserLabel(t *testing.T) {
+	input := `2020-05-13T18:55:37.772853839Z <11>1 2020-05-13T18:55:37Z host app 12 - - disk failure`
+
+	labels := map[string]string{"dev.dozzle.parser": "syslog"}
+	g := NewEventGenerator(context.Background(), makeFakeReader(input, STDOUT), Container{Labels: labels})
+	event := <-g.Events
+
+	require.NotNil(t, event)
+	assert.Equal(t, LogTypeComplex, event.Type)
+	assert.Equal(t, "error", event.Level)
+	assert.Equal(t, "<11>1 2020-05-13T18:55:37Z host app 12 - - disk failure", event.RawMessage)
+
+	fields, ok := event.Message.(*orderedmap.OrderedMap[string, string])
+	require.True(t, ok)
+	message, _ := fields.Get("message")
+	assert.Equal(t, "disk failure", message)
+}
+
+func TestLoadParserConfig(t *testing.T) {
+	config := `
+parsers:
+  - name: Billing
+    regex: '^(?P<time>\S+) \[(?P<thread>[^\]]+)\] (?P<level>\w+) (?P<message>.*)$'
+    match:
+      name: billing-*
+`
+	require.NoError(t, LoadParserConfig(strings.NewReader(config)))
+	t.Cleanup(func() { matchedParsers.Store(nil) })
+
+	line := "12:00:01 [worker-3] ERROR charge declined"
+
+	byMatch := parserForContainer(Container{Name: "billing-api"})
+	require.NotNil(t, byMatch)
+	fields, ok := byMatch.Parse(line)
+	require.True(t, ok)
+	thread, _ := fields.Get("thread")
+	assert.Equal(t, "worker-3", thread)
+
+	assert.Nil(t, parserForContainer(Container{Name: "shipping-api"}))
+
+	byLabel := parserForContainer(Container{Name: "shipping-api", Labels: map[string]string{"dev.dozzle.parser": "billing"}})
+	require.NotNil(t, byLabel)
+	_, ok = byLabel.Parse(line)
+	assert.True(t, ok)
+}
+
+func TestLoadParserConfig_invalid(t *testing.T) {
+	assert.Error(t, LoadParserConfig(strings.NewReader("parsers:\n  - name: x\n    regex: '(\\d+)'\n")))
+	assert.Error(t, LoadParserConfig(strings.NewReader("parsers:\n  - regex: '(?P<a>\\d+)'\n")))
+}
+
+func TestEventGenerator_RegexLabel(t *testing.T) {
+	input := "2020-05-13T18:55:37.772853839Z txn=42 took 180ms"
+
+	labels := map[string]string{"dev.dozzle.parser.regex": `^txn=(?P<txn>\d+) took (?P<duration_ms>\d+)ms$`}
+	g := NewEventGenerator(context.Background(), makeFakeReader(input, STDOUT), Container{Labels: labels})
+	event := <-g.Events
+
+	require.NotNil(t, event)
+	assert.Equal(t, LogTypeComplex, event.Type)
+	assert.Equal(t, "txn=42 took 180ms", event.RawMessage)
+	fields := event.Message.(*orderedmap.OrderedMap[string, string])
+	duration, _ := fields.Get("duration_ms")
+	assert.Equal(t, "180", duration)
+}
@@ -0,0 +1,121 @@
+package container
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// rateLimitLabel overrides the live line rate of a single container in lines
+// per second. 0 disables limiting for the container.
+const rateLimitLabel = "dev.dozzle.rate-limit"
+
+// LogRateLimit configures the token bucket applied to live log streams.
+type LogRateLimit struct {
+	// Rate is the sustained number of lines per second. 0 disables limiting.
+	Rate float64
+	// Burst is the number of lines allowed at once. Defaults to Rate.
+	Burst int
+	// Sample keeps one in every Sample lines over the limit instead of
+	// dropping all of them. 0 or 1 drops every excess line.
+	Sample int
+}
+
+var defaultLogRateLimit atomic.Pointer[LogRateLimit]
+
+// SetDefaultLogRateLimit sets the limit for containers without a
+// dev.dozzle.rate-limit label.
+func SetDefaultLogRateLimit(limit LogRateLimit) {
+	defaultLogRateLimit.Store(&limit)
+}
+
+// LogRateLimiter is a token bucket for the live logs of a single container.
+// Error and fatal lines are never dropped. Safe for concurrent use.
+type LogRateLimiter struct {
+	limit LogRateLimit
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	excess  int
+	dropped map[string]int
+}
+
+// NewLogRateLimiter returns the limiter for c, or nil when its logs are not
+// limited.
+func NewLogRateLimiter(c Container) *LogRateLimiter {
+	var limit LogRateLimit
+	if defaults := defaultLogRateLimit.Load(); defaults != nil {
+		limit = *defaults
+	}
+
+	if value := strings.TrimSpace(c.Labels[rateLimitLabel]); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 {
+			log.Warn().Str("label", rateLimitLabel).Str("value", value).Msg("invalid rate limit")
+		} else {
+			limit.Rate = rate
+			limit.Burst = 0
+		}
+	}
+
+	if limit.Rate <= 0 {
+		return nil
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = max(int(limit.Rate), 1)
+	}
+	return &LogRateLimiter{
+		limit:   limit,
+		tokens:  float64(limit.Burst),
+		dropped: make(map[string]int),
+	}
+}
+
+// Allow reports whether event should be forwarded. Dropped events are counted
+// by level until TakeDropped is called.
+func (l *LogRateLimiter) Allow(event *LogEvent, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		elapsed := now.Sub(l.last).Seconds()
+		l.tokens = min(l.tokens+elapsed*l.limit.Rate, float64(l.limit.Burst))
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return true
+	}
+
+	if event.Level == "error" || event.Level == "fatal" {
+		return true
+	}
+
+	l.excess++
+	if l.limit.Sample > 1 && l.excess%l.limit.Sample == 0 {
+		return true
+	}
+
+	l.dropped[event.Level]++
+	return false
+}
+
+// TakeDropped returns the number of dropped lines per level since the last
+// call and resets the counts. It returns nil when nothing was dropped.
+func (l *LogRateLimiter) TakeDropped() map[string]int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.dropped) == 0 {
+		return nil
+	}
+	dropped := l.dropped
+	l.dropped = make(map[string]int)
+	return dropped
+}
@@ -0,0 +1,56 @@
+package container
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogRateLimiter(t *testing.T) {
+	limiter := NewLogRateLimiter(Container{Labels: map[string]string{"dev.dozzle.rate-limit": "2"}})
+	require.NotNil(t, limiter)
+
+	now := time.Now()
+	info := &LogEvent{Level: "info"}
+	assert.True(t, limiter.Allow(info, now))
+	assert.True(t, limiter.Allow(info, now))
+	assert.False(t, limiter.Allow(info, now))
+	assert.False(t, limiter.Allow(&LogEvent{Level: "debug"}, now))
+	assert.True(t, limiter.Allow(&LogEvent{Level: "error"}, now), "errors are never dropped")
+
+	assert.Equal(t, map[string]int{"info": 1, "debug": 1}, limiter.TakeDropped())
+	assert.Nil(t, limiter.TakeDropped())
+
+	// half a second refills one token at 2 lines/s
+	assert.True(t, limiter.Allow(info, now.Add(500*time.Millisecond)))
+	assert.False(t, limiter.Allow(info, now.Add(500*time.Millisecond)))
+}
+
+func TestLogRateLimiter_Sample(t *testing.T) {
+	SetDefaultLogRateLimit(LogRateLimit{Rate: 1, Sample: 3})
+	t.Cleanup(func() { SetDefaultLogRateLimit(LogRateLimit{}) })
+
+	limiter := NewLogRateLimiter(Container{})
+	require.NotNil(t, limiter)
+
+	now := time.Now()
+	allowed := 0
+	for range 10 {
+		if limiter.Allow(&LogEvent{Level: "info"}, now) {
+			allowed++
+		}
+	}
+	// one token, then one in three of the nine excess lines
+	assert.Equal(t, 4, allowed)
+	assert.Equal(t, 6, limiter.TakeDropped()["info"])
+}
+
+func TestNewLogRateLimiter_Disabled(t *testing.T) {
+	SetDefaultLogRateLimit(LogRateLimit{Rate: 100})
+	t.Cleanup(func() { SetDefaultLogRateLimit(LogRateLimit{}) })
+
+	assert.NotNil(t, NewLogRateLimiter(Container{}))
+	assert.Nil(t, NewLogRateLimiter(Container{Labels: map[string]string{"dev.dozzle.rate-limit": "0"}}))
+}
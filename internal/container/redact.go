@@ -0,0 +1,291 @@
+package container
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	DefaultRedactionConfigPath = "./data/redaction.yml"
+
+	redactLabel = "dev.dozzle.redact"
+
+	defaultRedactionReplacement = "[REDACTED]"
+)
+
+// RedactionConfig is the on-disk format of redaction.yml.
+type RedactionConfig struct {
+	Rules []RedactionRuleConfig `yaml:"rules"`
+}
+
+// RedactionRuleConfig is a single masking rule. Either Preset or Regex must be
+// set. Rules without Labels apply to every container; otherwise a container
+// must carry all of the given label values.
+type RedactionRuleConfig struct {
+	Name        string            `yaml:"name,omitempty"`
+	Preset      string            `yaml:"preset,omitempty"`
+	Regex       string            `yaml:"regex,omitempty"`
+	Replacement string            `yaml:"replacement,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+}
+
+// redactionRule masks every match of re. replacement may reference capture
+// groups like regexp.ReplaceAllString. When valid is set, only matches it
+// accepts are masked and replacement is used literally.
+type redactionRule struct {
+	re          *regexp.Regexp
+	replacement string
+	valid       func(match string) bool
+}
+
+func (r *redactionRule) apply(s string) string {
+	if r.valid == nil {
+		return r.re.ReplaceAllString(s, r.replacement)
+	}
+	return r.re.ReplaceAllStringFunc(s, func(match string) string {
+		if r.valid(match) {
+			return r.replacement
+		}
+		return match
+	})
+}
+
+// redactionPresets are the built-in rules selectable by name in redaction.yml
+// or with the dev.dozzle.redact label.
+var redactionPresets = map[string]redactionRule{
+	"bearer": {
+		re:          regexp.MustCompile(`(?i)(\bbearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+		replacement: "${1}" + defaultRedactionReplacement,
+	},
+	"aws": {
+		re:          regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b|(?i)(aws_secret_access_key\s*[=:]\s*["']?)[A-Za-z0-9/+=]{40}`),
+		replacement: "${1}" + defaultRedactionReplacement,
+	},
+	"credit-card": {
+		re:          regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		replacement: defaultRedactionReplacement,
+		valid:       luhnValid,
+	},
+	"email": {
+		re:          regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}\b`),
+		replacement: defaultRedactionReplacement,
+	},
+	"jwt": {
+		re:          regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+		replacement: defaultRedactionReplacement,
+	},
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum used by
+// payment card numbers, which rules out most long numeric IDs.
+func luhnValid(s string) bool {
+	sum, double, digits := 0, false, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		n := int(c - '0')
+		if double {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		double = !double
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
+
+type configuredRedactionRule struct {
+	rule   redactionRule
+	labels map[string]string
+}
+
+// redactionRules holds the rules from redaction.yml. It is swapped atomically
+// so generators never see a partial load.
+var redactionRules atomic.Pointer[[]configuredRedactionRule]
+
+// LoadRedactionConfig reads redaction.yml from r and replaces the active rules.
+func LoadRedactionConfig(r io.Reader) error {
+	var config RedactionConfig
+	if err := yaml.NewDecoder(r).Decode(&config); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to decode redaction config: %w", err)
+	}
+
+	rules := make([]configuredRedactionRule, 0, len(config.Rules))
+	for i, c := range config.Rules {
+		var rule redactionRule
+		switch {
+		case c.Preset != "" && c.Regex != "":
+			return fmt.Errorf("redaction rule %d: preset and regex are mutually exclusive", i)
+		case c.Preset != "":
+			preset, ok := redactionPresets[strings.ToLower(c.Preset)]
+			if !ok {
+				return fmt.Errorf("redaction rule %d: unknown preset %s", i, c.Preset)
+			}
+			rule = preset
+		case c.Regex != "":
+			re, err := regexp.Compile(c.Regex)
+			if err != nil {
+				return fmt.Errorf("redaction rule %d: %w", i, err)
+			}
+			rule = redactionRule{re: re, replacement: defaultRedactionReplacement}
+		default:
+			return fmt.Errorf("redaction rule %d: preset or regex is required", i)
+		}
+		if c.Replacement != "" {
+			rule.replacement = c.Replacement
+		}
+		rules = append(rules, configuredRedactionRule{rule: rule, labels: c.Labels})
+	}
+
+	redactionRules.Store(&rules)
+	return nil
+}
+
+// LoadRedactionConfigFile loads the redaction config at path. A missing file
+// is not an error.
+func LoadRedactionConfigFile(path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	if err := LoadRedactionConfig(file); err != nil {
+		log.Warn().Err(err).Msg("Could not load redaction config")
+	} else {
+		log.Debug().Str("path", path).Msg("Loaded redaction config")
+	}
+}
+
+// Redactor masks secrets in log events of a single container.
+type Redactor struct {
+	rules []redactionRule
+}
+
+// RedactorForContainer returns the redactor for c, combining the rules of
+// redaction.yml that apply to it with the presets listed in its
+// dev.dozzle.redact label. It returns nil when nothing needs redacting.
+func RedactorForContainer(c Container) *Redactor {
+	var rules []redactionRule
+
+	if configured := redactionRules.Load(); configured != nil {
+	outer:
+		for _, r := range *configured {
+			for key, value := range r.labels {
+				if c.Labels[key] != value {
+					continue outer
+				}
+			}
+			rules = append(rules, r.rule)
+		}
+	}
+
+	if names := c.Labels[redactLabel]; names != "" {
+		for name := range strings.SplitSeq(names, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			preset, ok := redactionPresets[name]
+			if !ok {
+				log.Warn().Str("preset", name).Msg("unknown redaction preset")
+				continue
+			}
+			rules = append(rules, preset)
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+	return &Redactor{rules: rules}
+}
+
+// RedactString masks every rule match in s.
+func (r *Redactor) RedactString(s string) string {
+	for i := range r.rules {
+		s = r.rules[i].apply(s)
+	}
+	return s
+}
+
+// Redact masks secrets in the message and raw message of logEvent in place,
+// including string values nested inside structured messages.
+func (r *Redactor) Redact(logEvent *LogEvent) {
+	switch value := logEvent.Message.(type) {
+	case string:
+		logEvent.Message = r.RedactString(value)
+	case []LogFragment:
+		for i := range value {
+			value[i].Message = r.RedactString(value[i].Message)
+		}
+	case *orderedmap.OrderedMap[string, any]:
+		r.redactMapAny(value)
+	case *orderedmap.OrderedMap[string, string]:
+		for pair := value.Oldest(); pair != nil; pair = pair.Next() {
+			pair.Value = r.RedactString(pair.Value)
+		}
+	}
+	if logEvent.RawMessage != "" {
+		logEvent.RawMessage = r.RedactString(logEvent.RawMessage)
+	}
+}
+
+func (r *Redactor) redactMapAny(data *orderedmap.OrderedMap[string, any]) {
+	if data == nil {
+		return
+	}
+	for pair := data.Oldest(); pair != nil; pair = pair.Next() {
+		pair.Value = r.redactValue(pair.Value)
+	}
+}
+
+func (r *Redactor) redactValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return r.RedactString(v)
+	case map[string]any:
+		for key, child := range v {
+			v[key] = r.redactValue(child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = r.redactValue(child)
+		}
+	case *orderedmap.OrderedMap[string, any]:
+		r.redactMapAny(v)
+	}
+	return value
+}
+
+// NewRedactingReader masks secrets line by line in a raw log stream, such as
+// the one returned by RawLogs.
+func NewRedactingReader(reader io.ReadCloser, redactor *Redactor) io.ReadCloser {
+	in, out := io.Pipe()
+	go func() {
+		defer reader.Close()
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			if _, err := io.WriteString(out, redactor.RedactString(scanner.Text())+"\n"); err != nil {
+				return
+			}
+		}
+		out.CloseWithError(scanner.Err())
+	}()
+	return in
+}
@@ -306,12 +306,31 @@ type LogEvent struct {
 	Level       string  `json:"l,omitempty"`
 	Stream      string  `json:"s,omitempty"`
 	ContainerID string  `json:"c,omitempty"`
+	// Summary is the human readable message of a structured event, read from
+	// the path configured with dev.dozzle.json.message.
+	Summary string `json:"sm,omitempty"`
+	// MessageTimestamp is the time written by the application in the message
+	// itself, when the container opts into reading it. Timestamp always stays
+	// the log driver's time, which paging and time windows rely on.
+	MessageTimestamp int64 `json:"mts,omitempty"`
+	// Context marks a line that did not match the filter but was sent because
+	// it surrounds a line that did.
+	Context bool `json:"ctx,omitempty"`
 }
 
 func (l *LogEvent) HasLevel() bool {
 	return l.Level != "unknown"
 }
 
+// SortTimestamp is the time events are ordered by: the message timestamp when
+// there is one, otherwise the log driver's.
+func (l *LogEvent) SortTimestamp() int64 {
+	if l.MessageTimestamp != 0 {
+		return l.MessageTimestamp
+	}
+	return l.Timestamp
+}
+
 func (l *LogEvent) IsSimple() bool {
 	return l.Type == LogTypeSingle || l.Type == LogTypeGroup
 }
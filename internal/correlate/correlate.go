@@ -0,0 +1,159 @@
+// Package correlate finds the lines carrying an ID, such as a trace or request
+// ID, in many containers at once and merges them into one timeline.
+package correlate
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	container_support "github.com/amir20/dozzle/internal/support/container"
+	support_web "github.com/amir20/dozzle/internal/support/web"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultLimit is the number of hits returned when no limit is given.
+const DefaultLimit = 1000
+
+// ErrEmptyID is returned when a query has no ID.
+var ErrEmptyID = errors.New("id is required")
+
+var fieldName = regexp.MustCompile(`^[A-Za-z_@][\w.@-]*$`)
+
+// Query selects the lines containing ID between From and To. With Fields,
+// only structured lines whose value of one of the fields is ID match.
+type Query struct {
+	ID     string
+	Fields []string
+	From   time.Time
+	To     time.Time
+}
+
+// Hit is a matching line with the container it was written by.
+type Hit struct {
+	Host          string              `json:"host"`
+	ContainerID   string              `json:"containerId"`
+	ContainerName string              `json:"containerName"`
+	Event         *container.LogEvent `json:"event"`
+}
+
+// compile turns q into a filter query: a phrase for the ID, or one field term
+// per field.
+func (q Query) compile() (*support_web.Query, error) {
+	id := strings.TrimSpace(q.ID)
+	if id == "" {
+		return nil, ErrEmptyID
+	}
+	quoted := `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(id) + `"`
+
+	if len(q.Fields) == 0 {
+		return support_web.ParseQuery(quoted)
+	}
+
+	terms := make([]string, len(q.Fields))
+	for i, field := range q.Fields {
+		if !fieldName.MatchString(field) {
+			return nil, fmt.Errorf("invalid field %q", field)
+		}
+		terms[i] = field + ":" + quoted
+	}
+	return support_web.ParseQuery(strings.Join(terms, " OR "))
+}
+
+// Search reads the logs of containers in parallel and sends the matching lines
+// oldest first. The channel is closed once every container was read or ctx is
+// done. Containers whose logs cannot be read are skipped.
+func Search(ctx context.Context, containers []*container_support.ContainerService, q Query) (<-chan Hit, error) {
+	query, err := q.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]chan Hit, 0, len(containers))
+	for _, c := range containers {
+		if c.Container.Created.After(q.To) {
+			continue
+		}
+		source := make(chan Hit, 100)
+		sources = append(sources, source)
+		go scan(ctx, c, query, q.From, q.To, source)
+	}
+
+	hits := make(chan Hit)
+	go merge(ctx, sources, hits)
+	return hits, nil
+}
+
+func scan(ctx context.Context, c *container_support.ContainerService, query *support_web.Query, from time.Time, to time.Time, hits chan<- Hit) {
+	defer close(hits)
+
+	events, err := c.LogsBetweenDates(ctx, from, to, container.STDALL)
+	if err != nil {
+		log.Warn().Err(err).Str("container", c.Container.ID).Msg("Could not read logs for correlation")
+		return
+	}
+
+	for event := range events {
+		if !query.Matches(event) {
+			continue
+		}
+		select {
+		case hits <- Hit{Host: c.Container.Host, ContainerID: c.Container.ID, ContainerName: c.Container.Name, Event: event}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// merge sends the hits of all sources in time order. Each source is ordered,
+// so the next hit is always the oldest head.
+func merge(ctx context.Context, sources []chan Hit, hits chan<- Hit) {
+	defer close(hits)
+
+	heads := make(hitHeap, 0, len(sources))
+	for _, source := range sources {
+		if hit, ok := <-source; ok {
+			heads = append(heads, head{hit: hit, source: source})
+		}
+	}
+	heap.Init(&heads)
+
+	for heads.Len() > 0 {
+		next := heads[0]
+		select {
+		case hits <- next.hit:
+		case <-ctx.Done():
+			return
+		}
+
+		if hit, ok := <-next.source; ok {
+			heads[0].hit = hit
+			heap.Fix(&heads, 0)
+		} else {
+			heap.Pop(&heads)
+		}
+	}
+}
+
+type head struct {
+	hit    Hit
+	source chan Hit
+}
+
+type hitHeap []head
+
+func (h hitHeap) Len() int           { return len(h) }
+func (h hitHeap) Less(i, j int) bool { return h[i].hit.Event.Timestamp < h[j].hit.Event.Timestamp }
+func (h hitHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *hitHeap) Push(x any)        { *h = append(*h, x.(head)) }
+func (h *hitHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,71 @@
+package correlate
+
+import (
+	"testing"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+func TestQueryCompile(t *testing.T) {
+	structured := func(key, value string) *container.LogEvent {
+		fields := orderedmap.New[string, any]()
+		fields.Set("msg", "handled")
+		fields.Set(key, value)
+		return &container.LogEvent{Type: container.LogTypeComplex, Message: fields, RawMessage: `{"msg":"handled","` + key + `":"` + value + `"}`}
+	}
+	plain := &container.LogEvent{Message: `GET /orders id="abc-123"`, RawMessage: `GET /orders id="abc-123"`}
+
+	tests := []struct {
+		name   string
+		query  Query
+		event  *container.LogEvent
+		expect bool
+	}{
+		{"text in plain line", Query{ID: "abc-123"}, plain, true},
+		{"text in structured line", Query{ID: "abc-123"}, structured("trace_id", "abc-123"), true},
+		{"text is literal", Query{ID: "abc.123"}, plain, false},
+		{"quotes are escaped", Query{ID: `"abc-123"`}, plain, true},
+		{"field value", Query{ID: "abc-123", Fields: []string{"traceId", "trace_id"}}, structured("trace_id", "abc-123"), true},
+		{"other field", Query{ID: "abc-123", Fields: []string{"request_id"}}, structured("trace_id", "abc-123"), false},
+		{"partial field value", Query{ID: "abc", Fields: []string{"trace_id"}}, structured("trace_id", "abc-123"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := tt.query.compile()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, query.Matches(tt.event))
+		})
+	}
+}
+
+func TestQueryCompileErrors(t *testing.T) {
+	_, err := Query{ID: "  "}.compile()
+	assert.ErrorIs(t, err, ErrEmptyID)
+
+	_, err = Query{ID: "abc", Fields: []string{"trace id"}}.compile()
+	assert.Error(t, err)
+}
+
+func TestMergeOrdersByTime(t *testing.T) {
+	source := func(timestamps ...int64) chan Hit {
+		hits := make(chan Hit, len(timestamps))
+		for _, ts := range timestamps {
+			hits <- Hit{Event: &container.LogEvent{Timestamp: ts}}
+		}
+		close(hits)
+		return hits
+	}
+
+	hits := make(chan Hit)
+	go merge(t.Context(), []chan Hit{source(1, 4, 7), source(), source(2, 3, 9), source(5)}, hits)
+
+	var got []int64
+	for hit := range hits {
+		got = append(got, hit.Event.Timestamp)
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 7, 9}, got)
+}
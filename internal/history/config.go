@@ -0,0 +1,41 @@
+// Package history keeps the stats of containers on disk so they can be charted
+// over a longer range than the few minutes held in memory. Raw samples are
+// kept for an hour and rolled up per minute for the configured retention.
+package history
+
+import (
+	"io"
+	"time"
+
+	store_support "github.com/amir20/dozzle/internal/support/store"
+)
+
+const (
+	DefaultConfigPath = "./data/stats.yml"
+	DefaultDirectory  = "./data/stats"
+
+	defaultMaxAge = 7 * 24 * time.Hour
+)
+
+var spec = store_support.Spec{Name: "stats", DefaultMaxAge: defaultMaxAge, MinMaxAge: rawRetention}
+
+// Config is the on-disk format of stats.yml. MaxAge is how long the minute
+// rollups are kept.
+type Config struct {
+	store_support.Config `yaml:",inline"`
+}
+
+// LoadConfig parses and validates stats.yml.
+func LoadConfig(r io.Reader) (*Config, error) {
+	var config Config
+	if err := spec.Decode(r, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// LoadConfigFile reads the config at path. It returns nil when the file does
+// not exist or is invalid, which leaves the stats history disabled.
+func LoadConfigFile(path string) *Config {
+	return store_support.LoadFile(path, spec.Name, LoadConfig)
+}
@@ -0,0 +1,25 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Equal(t, defaultMaxAge, config.Retention())
+
+	config, err = LoadConfig(strings.NewReader("maxAge: 48h\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 48*time.Hour, config.Retention())
+
+	for _, value := range []string{"maxAge: soon\n", "maxAge: 10m\n"} {
+		_, err := LoadConfig(strings.NewReader(value))
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,77 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/amir20/dozzle/internal/notification"
+	container_support "github.com/amir20/dozzle/internal/support/container"
+	"github.com/rs/zerolog/log"
+)
+
+// pruneInterval is how often old rollups are removed.
+const pruneInterval = time.Hour
+
+// History records the stats of every container of the clients into a Store.
+type History struct {
+	store    *Store
+	listener *notification.ContainerStatsListener
+	ctx      context.Context
+}
+
+// Start opens the store in dir and begins recording the stats of the
+// containers of clients. It keeps the stats collectors of the clients running
+// until ctx is done.
+func Start(ctx context.Context, config *Config, dir string, clients []container_support.ClientService) (*History, error) {
+	store, err := NewStore(dir, config.Retention())
+	if err != nil {
+		return nil, err
+	}
+
+	h := &History{
+		store:    store,
+		listener: notification.NewContainerStatsListener(ctx, clients),
+		ctx:      ctx,
+	}
+
+	store.Prune(time.Now())
+	h.listener.Start()
+	go h.run()
+
+	log.Info().Str("path", dir).Str("maxAge", config.Retention().String()).Msg("Recording container stats history")
+	return h, nil
+}
+
+func (h *History) run() {
+	flush := time.NewTicker(rollupInterval)
+	defer flush.Stop()
+	prune := time.NewTicker(pruneInterval)
+	defer prune.Stop()
+	defer h.store.Close()
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case event := <-h.listener.Channel():
+			// k8s containers carry their node rather than the cluster host
+			host := event.Container.Host
+			if host == "" {
+				host = event.Host.ID
+			}
+			if err := h.store.Add(host, event.Stat, time.Now()); err != nil {
+				log.Error().Err(err).Str("container", event.Stat.ID).Msg("Could not record stats")
+			}
+		case now := <-flush.C:
+			h.store.Flush(now)
+		case now := <-prune.C:
+			h.store.Prune(now)
+		}
+	}
+}
+
+// Query returns the samples of a container between from and to. See
+// Store.Query for how step is applied.
+func (h *History) Query(host string, id string, from time.Time, to time.Time, step time.Duration) ([]Sample, time.Duration, error) {
+	return h.store.Query(host, id, from, to, step, time.Now())
+}
@@ -0,0 +1,371 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	store_support "github.com/amir20/dozzle/internal/support/store"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// rawRetention is how long every sample is kept in memory.
+	rawRetention = time.Hour
+	// rollupInterval is the resolution of the samples kept on disk.
+	rollupInterval = time.Minute
+	// MaxPoints bounds the samples returned by a single query.
+	MaxPoints = 1000
+
+	day = 24 * time.Hour
+)
+
+// ErrDisabled is returned when stats.yml does not exist.
+var ErrDisabled = errors.New("stats history is disabled")
+
+// ErrNotRecorded is returned for the containers of agent hosts, whose stats
+// are not recorded.
+var ErrNotRecorded = errors.New("stats of agent hosts are not recorded")
+
+// Sample is either a single stat or the rollup of the stats in an interval
+// starting at Time. Totals are the last values seen in the interval.
+type Sample struct {
+	Time           time.Time `json:"time"`
+	CPU            float64   `json:"cpu"`
+	CPUMax         float64   `json:"cpuMax"`
+	Memory         float64   `json:"memory"`
+	MemoryMax      float64   `json:"memoryMax"`
+	MemoryUsage    float64   `json:"memoryUsage"`
+	MemoryUsageMax float64   `json:"memoryUsageMax"`
+	NetworkRxTotal uint64    `json:"networkRxTotal"`
+	NetworkTxTotal uint64    `json:"networkTxTotal"`
+	DiskReadTotal  uint64    `json:"diskReadTotal"`
+	DiskWriteTotal uint64    `json:"diskWriteTotal"`
+	Count          int       `json:"count"`
+}
+
+func newSample(stat container.ContainerStat, t time.Time) Sample {
+	return Sample{
+		Time:           t,
+		CPU:            stat.CPUPercent,
+		CPUMax:         stat.CPUPercent,
+		Memory:         stat.MemoryPercent,
+		MemoryMax:      stat.MemoryPercent,
+		MemoryUsage:    stat.MemoryUsage,
+		MemoryUsageMax: stat.MemoryUsage,
+		NetworkRxTotal: stat.NetworkRxTotal,
+		NetworkTxTotal: stat.NetworkTxTotal,
+		DiskReadTotal:  stat.DiskReadTotal,
+		DiskWriteTotal: stat.DiskWriteTotal,
+		Count:          1,
+	}
+}
+
+// merge folds a later sample into s. Averages are weighted by count.
+func (s *Sample) merge(other Sample) {
+	total := float64(s.Count + other.Count)
+	weighted := func(a, b float64) float64 {
+		return (a*float64(s.Count) + b*float64(other.Count)) / total
+	}
+	s.CPU = weighted(s.CPU, other.CPU)
+	s.Memory = weighted(s.Memory, other.Memory)
+	s.MemoryUsage = weighted(s.MemoryUsage, other.MemoryUsage)
+	s.CPUMax = max(s.CPUMax, other.CPUMax)
+	s.MemoryMax = max(s.MemoryMax, other.MemoryMax)
+	s.MemoryUsageMax = max(s.MemoryUsageMax, other.MemoryUsageMax)
+	s.NetworkRxTotal = other.NetworkRxTotal
+	s.NetworkTxTotal = other.NetworkTxTotal
+	s.DiskReadTotal = other.DiskReadTotal
+	s.DiskWriteTotal = other.DiskWriteTotal
+	s.Count += other.Count
+}
+
+type key struct {
+	host string
+	id   string
+}
+
+// series holds the samples of one container that are not on disk yet.
+type series struct {
+	raw     []Sample
+	current Sample
+}
+
+func (s *series) trim(now time.Time) {
+	cutoff := now.Add(-rawRetention)
+	i := 0
+	for i < len(s.raw) && s.raw[i].Time.Before(cutoff) {
+		i++
+	}
+	s.raw = s.raw[i:]
+}
+
+// Store keeps the raw samples of the last hour in memory and appends minute
+// rollups to one file per container and day, laid out as
+// <dir>/<host>/<container>/<day>.jsonl.
+type Store struct {
+	dir    string
+	maxAge time.Duration
+
+	mu     sync.Mutex
+	series map[key]*series
+}
+
+// NewStore opens the store in dir. Rollups older than maxAge are removed by
+// Prune.
+func NewStore(dir string, maxAge time.Duration) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Store{
+		dir:    dir,
+		maxAge: maxAge,
+		series: make(map[key]*series),
+	}, nil
+}
+
+// Add records stat of a container of host seen at now. The rollup of the
+// previous minute is written once a stat of a later minute arrives.
+func (s *Store) Add(host string, stat container.ContainerStat, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key{host: host, id: stat.ID}
+	item, ok := s.series[k]
+	if !ok {
+		item = &series{}
+		s.series[k] = item
+	}
+
+	var err error
+	minute := now.Truncate(rollupInterval)
+	if item.current.Count > 0 && !item.current.Time.Equal(minute) {
+		err = s.write(k, item.current)
+		item.current = Sample{}
+	}
+
+	sample := newSample(stat, now)
+	if item.current.Count == 0 {
+		item.current = sample
+		item.current.Time = minute
+	} else {
+		item.current.merge(sample)
+	}
+
+	item.raw = append(item.raw, sample)
+	item.trim(now)
+	return err
+}
+
+// Flush writes the rollups of the minutes that have ended and forgets the
+// containers that stopped reporting.
+func (s *Store) Flush(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, item := range s.series {
+		if item.current.Count > 0 && !item.current.Time.Add(rollupInterval).After(now) {
+			if err := s.write(k, item.current); err != nil {
+				log.Error().Err(err).Str("container", k.id).Msg("Could not write stats")
+			}
+			item.current = Sample{}
+		}
+		item.trim(now)
+		if item.current.Count == 0 && len(item.raw) == 0 {
+			delete(s.series, k)
+		}
+	}
+}
+
+// Close writes the rollups of the minutes still in progress.
+func (s *Store) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, item := range s.series {
+		if item.current.Count > 0 {
+			if err := s.write(k, item.current); err != nil {
+				log.Error().Err(err).Str("container", k.id).Msg("Could not write stats")
+			}
+			item.current = Sample{}
+		}
+	}
+}
+
+func (s *Store) containerDir(k key) string {
+	return filepath.Join(s.dir, url.PathEscape(k.host), url.PathEscape(k.id))
+}
+
+func dayFile(dir string, t time.Time) string {
+	return filepath.Join(dir, strconv.FormatInt(t.Truncate(day).Unix(), 10)+".jsonl")
+}
+
+func (s *Store) write(k key, sample Sample) error {
+	dir := s.containerDir(k)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(sample)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(dayFile(dir, sample.Time), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// Query returns the samples of a container between from and to, averaged per
+// step. The raw samples are used when step is under a minute and the range is
+// within the last hour; a zero step then returns every sample. Otherwise the
+// minute rollups are used and a zero step is chosen to return at most
+// MaxPoints samples. The step that was applied is returned.
+func (s *Store) Query(host string, id string, from time.Time, to time.Time, step time.Duration, now time.Time) ([]Sample, time.Duration, error) {
+	k := key{host: host, id: id}
+	inRange := func(t time.Time) bool {
+		return !t.Before(from) && !t.After(to)
+	}
+
+	if step < rollupInterval && !from.Before(now.Add(-rawRetention)) {
+		samples := make([]Sample, 0)
+		s.mu.Lock()
+		if item, ok := s.series[k]; ok {
+			for _, sample := range item.raw {
+				if inRange(sample.Time) {
+					samples = append(samples, sample)
+				}
+			}
+		}
+		s.mu.Unlock()
+
+		if step > 0 {
+			samples = downsample(samples, step)
+		}
+		return samples, step, nil
+	}
+
+	if step == 0 {
+		step = to.Sub(from) / MaxPoints
+	}
+	step = max(rollupInterval, (step+rollupInterval-1)/rollupInterval*rollupInterval)
+
+	samples, err := s.readRollups(k, from, to)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.mu.Lock()
+	if item, ok := s.series[k]; ok && item.current.Count > 0 && inRange(item.current.Time) {
+		samples = append(samples, item.current)
+	}
+	s.mu.Unlock()
+
+	return downsample(samples, step), step, nil
+}
+
+func (s *Store) readRollups(k key, from time.Time, to time.Time) ([]Sample, error) {
+	dir := s.containerDir(k)
+	samples := make([]Sample, 0)
+	for start := from.Truncate(day); !start.After(to); start = start.Add(day) {
+		file, err := os.Open(dayFile(dir, start))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = store_support.ReadLines(file, func(sample Sample) {
+			if !sample.Time.Before(from) && !sample.Time.After(to) {
+				samples = append(samples, sample)
+			}
+		})
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A minute is written twice when Dozzle restarts during it.
+	slices.SortStableFunc(samples, func(a, b Sample) int {
+		return a.Time.Compare(b.Time)
+	})
+	return samples, nil
+}
+
+// downsample merges sorted samples into one per step.
+func downsample(samples []Sample, step time.Duration) []Sample {
+	result := make([]Sample, 0, len(samples))
+	for _, sample := range samples {
+		bucket := sample.Time.Truncate(step)
+		if last := len(result) - 1; last >= 0 && result[last].Time.Equal(bucket) {
+			result[last].merge(sample)
+			continue
+		}
+		sample.Time = bucket
+		result = append(result, sample)
+	}
+	return result
+}
+
+// Prune removes the day files whose rollups are all older than maxAge, and
+// the directories left empty.
+func (s *Store) Prune(now time.Time) {
+	cutoff := now.Add(-s.maxAge)
+	hosts, err := os.ReadDir(s.dir)
+	if err != nil {
+		log.Error().Err(err).Str("path", s.dir).Msg("Could not read stats directory")
+		return
+	}
+
+	for _, host := range hosts {
+		if !host.IsDir() {
+			continue
+		}
+		hostDir := filepath.Join(s.dir, host.Name())
+		containers, err := os.ReadDir(hostDir)
+		if err != nil {
+			continue
+		}
+		for _, c := range containers {
+			if !c.IsDir() {
+				continue
+			}
+			containerDir := filepath.Join(hostDir, c.Name())
+			files, err := os.ReadDir(containerDir)
+			if err != nil {
+				continue
+			}
+			remaining := len(files)
+			for _, file := range files {
+				start, err := strconv.ParseInt(strings.TrimSuffix(file.Name(), ".jsonl"), 10, 64)
+				if err != nil || !time.Unix(start, 0).Add(day).Before(cutoff) {
+					continue
+				}
+				if err := os.Remove(filepath.Join(containerDir, file.Name())); err != nil {
+					log.Error().Err(err).Str("file", file.Name()).Msg("Could not remove stats")
+					continue
+				}
+				remaining--
+			}
+			if remaining == 0 {
+				os.Remove(containerDir)
+			}
+		}
+		os.Remove(hostDir) // only succeeds when empty
+	}
+}
@@ -0,0 +1,112 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testStart = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func stat(cpu float64, rx uint64) container.ContainerStat {
+	return container.ContainerStat{ID: "abc123", CPUPercent: cpu, MemoryPercent: cpu / 2, MemoryUsage: cpu * 1000, NetworkRxTotal: rx}
+}
+
+func TestStore_RawAndRollups(t *testing.T) {
+	store, err := NewStore(t.TempDir(), 24*time.Hour)
+	require.NoError(t, err)
+
+	// two minutes of samples every 15 seconds
+	for i := range 8 {
+		require.NoError(t, store.Add("local", stat(float64(i*10), uint64(i)), testStart.Add(time.Duration(i)*15*time.Second)))
+	}
+	now := testStart.Add(2 * time.Minute)
+
+	samples, step, err := store.Query("local", "abc123", testStart, now, 0, now)
+	require.NoError(t, err)
+	assert.Zero(t, step)
+	assert.Len(t, samples, 8)
+
+	samples, step, err = store.Query("local", "abc123", testStart, now, 30*time.Second, now)
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, step)
+	require.Len(t, samples, 4)
+	assert.Equal(t, 5.0, samples[0].CPU)
+	assert.Equal(t, 10.0, samples[0].CPUMax)
+	assert.Equal(t, 2, samples[0].Count)
+
+	// the first minute is on disk, the second one still in memory
+	samples, step, err = store.Query("local", "abc123", testStart, now, time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, step)
+	require.Len(t, samples, 2)
+	assert.Equal(t, testStart, samples[0].Time)
+	assert.Equal(t, 15.0, samples[0].CPU)
+	assert.Equal(t, 30.0, samples[0].CPUMax)
+	assert.Equal(t, uint64(3), samples[0].NetworkRxTotal)
+	assert.Equal(t, 55.0, samples[1].CPU)
+	assert.Equal(t, 4, samples[1].Count)
+
+	store.Flush(now)
+	samples, _, err = store.Query("local", "abc123", testStart, now, 2*time.Minute, now)
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	assert.Equal(t, 35.0, samples[0].CPU)
+	assert.Equal(t, 70.0, samples[0].CPUMax)
+	assert.Equal(t, uint64(7), samples[0].NetworkRxTotal)
+	assert.Equal(t, 8, samples[0].Count)
+
+	// raw samples are gone after an hour but the rollups remain
+	later := now.Add(2 * time.Hour)
+	store.Flush(later)
+	samples, _, err = store.Query("local", "abc123", later.Add(-time.Minute), later, 0, later)
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+	samples, step, err = store.Query("local", "abc123", testStart, later, 0, later)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, step)
+	assert.Len(t, samples, 2)
+}
+
+func TestStore_QueryStep(t *testing.T) {
+	store, err := NewStore(t.TempDir(), 24*time.Hour)
+	require.NoError(t, err)
+
+	now := testStart.Add(48 * time.Hour)
+	_, step, err := store.Query("local", "abc123", testStart, now, 0, now)
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Minute, step)
+
+	_, step, err = store.Query("local", "abc123", testStart, now, 90*time.Second, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, step)
+}
+
+func TestStore_Prune(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir, 24*time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Add("local", stat(10, 0), testStart))
+	require.NoError(t, store.Add("local", stat(10, 0), testStart.Add(48*time.Hour)))
+	store.Close()
+
+	containerDir := filepath.Join(dir, "local", "abc123")
+	files, err := os.ReadDir(containerDir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	store.Prune(testStart.Add(49 * time.Hour))
+	files, err = os.ReadDir(containerDir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	store.Prune(testStart.Add(96 * time.Hour))
+	_, err = os.Stat(filepath.Join(dir, "local"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -10,9 +10,14 @@ import (
 
 	"github.com/amir20/dozzle/internal/auth"
 	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/correlate"
+	"github.com/amir20/dozzle/internal/history"
+	"github.com/amir20/dozzle/internal/patterns"
 	container_support "github.com/amir20/dozzle/internal/support/container"
+	support_web "github.com/amir20/dozzle/internal/support/web"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/rs/zerolog/log"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
 )
 
 // HostService is the subset of web.HostService needed by the MCP server.
@@ -20,6 +25,7 @@ type HostService interface {
 	FindContainer(host string, id string, labels container.ContainerLabels) (*container_support.ContainerService, error)
 	ListAllContainers(labels container.ContainerLabels) ([]container.Container, []error)
 	Hosts() []container.Host
+	StatsHistory(host string, id string, from time.Time, to time.Time, step time.Duration) ([]history.Sample, time.Duration, error)
 }
 
 // Server wraps an MCP server that exposes Dozzle container operations as tools.
@@ -89,11 +95,31 @@ type searchContainerLogsParams struct {
 	SinceMinutes  *int    `json:"since_minutes,omitempty" jsonschema:"Search logs from the last N minutes. Defaults to 5."`
 	Stream        *string `json:"stream,omitempty" jsonschema:"Which output stream to search: stdout, stderr, or all. Defaults to all."`
 	CaseSensitive *bool   `json:"case_sensitive,omitempty" jsonschema:"Whether to perform a case-sensitive search. Defaults to false."`
+	Field         *string `json:"field,omitempty" jsonschema:"Only search the value of this field of structured (JSON, logfmt or parsed) log entries, e.g. status or request_id. Plain text entries never match."`
+	Before        *int    `json:"before,omitempty" jsonschema:"Also return up to N entries before each match, marked with context: true. Defaults to 0, at most 100."`
+	After         *int    `json:"after,omitempty" jsonschema:"Also return up to N entries after each match, marked with context: true. Defaults to 0, at most 100."`
+}
+
+type getLogPatternsParams struct {
+	Host         string  `json:"host" jsonschema:"The host ID where the container is running. Use list_containers to find this."`
+	ContainerID  string  `json:"container_id" jsonschema:"The container ID (or short ID) to summarize logs of. Use list_containers to find this."`
+	SinceMinutes *int    `json:"since_minutes,omitempty" jsonschema:"Summarize logs from the last N minutes. Defaults to 60."`
+	Stream       *string `json:"stream,omitempty" jsonschema:"Which output stream to read: stdout, stderr, or all. Defaults to all."`
+	Limit        *int    `json:"limit,omitempty" jsonschema:"Maximum number of patterns to return, most frequent first. Defaults to 50."`
 }
 
 type getContainerStatsParams struct {
-	Host        string `json:"host" jsonschema:"The host ID where the container is running. Use list_containers to find this."`
-	ContainerID string `json:"container_id" jsonschema:"The container ID to get stats for. Use list_containers to find this."`
+	Host         string `json:"host" jsonschema:"The host ID where the container is running. Use list_containers to find this."`
+	ContainerID  string `json:"container_id" jsonschema:"The container ID to get stats for. Use list_containers to find this."`
+	SinceMinutes *int   `json:"since_minutes,omitempty" jsonschema:"Read the recorded stats history of the last N minutes instead of the in-memory stats of the last ~5 minutes. Requires the stats history to be enabled."`
+	StepSeconds  *int   `json:"step_seconds,omitempty" jsonschema:"Average the stats history per N seconds. Defaults to the raw samples within the last hour and to at most 1000 points otherwise."`
+}
+
+type correlateLogsParams struct {
+	ID           string  `json:"id" jsonschema:"The trace, request or correlation ID to look for."`
+	Fields       *string `json:"fields,omitempty" jsonschema:"Comma-separated JSON fields holding the ID, e.g. trace_id,traceId. Only entries whose field value equals the ID match. Leave empty to match the ID anywhere in the text."`
+	SinceMinutes *int    `json:"since_minutes,omitempty" jsonschema:"Search logs from the last N minutes. Defaults to 60."`
+	Limit        *int    `json:"limit,omitempty" jsonschema:"Maximum number of entries to return. Defaults to 1000."`
 }
 
 func (s *Server) registerTools() {
@@ -111,10 +137,16 @@ func (s *Server) registerTools() {
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "search_container_logs",
-		Description: "Search container logs for a keyword or phrase. Returns only matching log entries, making it efficient for finding specific errors or events without downloading large volumes of logs.",
+		Description: "Search container logs for a keyword or phrase. Returns only matching log entries, making it efficient for finding specific errors or events without downloading large volumes of logs. Pass before and after to include the surrounding entries, like grep -B and -A.",
 		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
 	}, s.handleSearchContainerLogs)
 
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "get_log_patterns",
+		Description: "Summarize container logs into recurring message templates, with variable parts replaced by <*>. Returns each pattern with its count, share of all lines, first and last occurrence, level breakdown and an example line. Use it to see which message shapes dominate the output before reading individual logs.",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, s.handleGetLogPatterns)
+
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "list_hosts",
 		Description: "List all Docker hosts connected to Dozzle.",
@@ -123,9 +155,15 @@ func (s *Server) registerTools() {
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
 		Name:        "get_container_stats",
-		Description: "Get CPU and memory usage stats for a Docker container. Returns the last ~5 minutes of stats history with CPU percentage, memory percentage, and memory usage in bytes.",
+		Description: "Get CPU and memory usage stats for a Docker container. Returns the last ~5 minutes of stats history with CPU percentage, memory percentage, and memory usage in bytes. Pass since_minutes to read longer ranges from the recorded stats history, where each point also has its time and the peak CPU and memory percentage of its interval.",
 		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
 	}, s.handleGetContainerStats)
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "correlate_logs",
+		Description: "Find the log entries of all containers on all hosts that carry a trace or request ID. Returns one timeline, oldest first, where each entry names the host and container that wrote it. Use it to follow a request across services.",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, s.handleCorrelateLogs)
 }
 
 // --- Tool Handlers ---
@@ -192,6 +230,7 @@ type mcpLogEntry struct {
 	Stream    string `json:"stream,omitempty"`
 	Type      string `json:"type"`
 	Message   any    `json:"message"`
+	Context   bool   `json:"context,omitempty"`
 }
 
 func errorResult(text string) *mcp.CallToolResult {
@@ -282,36 +321,36 @@ func newLogEntry(event *container.LogEvent) mcpLogEntry {
 		Stream:    event.Stream,
 		Type:      string(event.Type),
 		Message:   eventMessage(event),
+		Context:   event.Context,
 	}
 }
 
 // collectLogEntries drains events into JSON-encodable entries. keep, when
-// non-nil, filters which entries are included. Collection stops once the encoded
-// size would exceed maxLogSize, in which case truncated is true. scanned counts
-// every event pulled from the channel, matched or not.
-func collectLogEntries(events <-chan *container.LogEvent, keep func(mcpLogEntry) bool) (entries []mcpLogEntry, scanned int, truncated bool) {
+// non-nil, filters which entries are included, and contextLines adds the
+// entries around them. Collection stops once the encoded size would exceed
+// maxLogSize, in which case truncated is true. scanned counts every event
+// pulled from the channel, matched or not.
+func collectLogEntries(events <-chan *container.LogEvent, keep func(mcpLogEntry) bool, contextLines *support_web.ContextLines) (entries []mcpLogEntry, scanned int, truncated bool) {
 	totalSize := 0
 	for event := range events {
 		scanned++
-		entry := newLogEntry(event)
-		if keep != nil && !keep(entry) {
-			continue
-		}
+		matched := keep == nil || keep(newLogEntry(event))
+		for _, line := range contextLines.Next(event, matched) {
+			entry := newLogEntry(line)
+			data, err := json.Marshal(entry)
+			if err != nil {
+				continue
+			}
 
-		line, err := json.Marshal(entry)
-		if err != nil {
-			continue
-		}
+			totalSize += len(data) + 1
+			if totalSize > maxLogSize {
+				return entries, scanned, true
+			}
 
-		totalSize += len(line) + 1
-		if totalSize > maxLogSize {
-			truncated = true
-			break
+			entries = append(entries, entry)
 		}
-
-		entries = append(entries, entry)
 	}
-	return entries, scanned, truncated
+	return entries, scanned, false
 }
 
 // encodeLogEntries renders entries as newline-delimited JSON.
@@ -337,7 +376,7 @@ func (s *Server) handleGetContainerLogs(ctx context.Context, _ *mcp.CallToolRequ
 	}
 	defer cancel()
 
-	entries, _, _ := collectLogEntries(events, nil)
+	entries, _, _ := collectLogEntries(events, nil, support_web.NewContextLines(0, 0))
 	if len(entries) == 0 {
 		return textResult("(no logs in the specified time range)"), nil, nil
 	}
@@ -354,6 +393,17 @@ func (s *Server) handleSearchContainerLogs(ctx context.Context, _ *mcp.CallToolR
 		return errorResult("host, container_id, and query are required"), nil, nil
 	}
 
+	before, after := 0, 0
+	if params.Before != nil {
+		before = *params.Before
+	}
+	if params.After != nil {
+		after = *params.After
+	}
+	if before < 0 || before > support_web.MaxContextLines || after < 0 || after > support_web.MaxContextLines {
+		return errorResult(fmt.Sprintf("before and after must be between 0 and %d", support_web.MaxContextLines)), nil, nil
+	}
+
 	caseSensitive := params.CaseSensitive != nil && *params.CaseSensitive
 	query := params.Query
 	if !caseSensitive {
@@ -366,16 +416,34 @@ func (s *Server) handleSearchContainerLogs(ctx context.Context, _ *mcp.CallToolR
 	}
 	defer cancel()
 
+	field := ""
+	if params.Field != nil {
+		field = *params.Field
+	}
+
 	keep := func(entry mcpLogEntry) bool {
 		haystack := messageToSearchString(entry.Message)
+		if field != "" {
+			value, ok := messageField(entry.Message, field)
+			if !ok {
+				return false
+			}
+			haystack = value
+		}
 		if !caseSensitive {
 			haystack = strings.ToLower(haystack)
 		}
 		return strings.Contains(haystack, query)
 	}
 
-	entries, scanned, truncated := collectLogEntries(events, keep)
-	if len(entries) == 0 {
+	entries, scanned, truncated := collectLogEntries(events, keep, support_web.NewContextLines(before, after))
+	matches := 0
+	for _, entry := range entries {
+		if !entry.Context {
+			matches++
+		}
+	}
+	if matches == 0 {
 		return textResult(fmt.Sprintf("(no matches for %q in %d log entries scanned)", params.Query, scanned)), nil, nil
 	}
 
@@ -385,7 +453,7 @@ func (s *Server) handleSearchContainerLogs(ctx context.Context, _ *mcp.CallToolR
 	}
 
 	var sb strings.Builder
-	fmt.Fprintf(&sb, "Found %d matches for %q (scanned %d entries):\n", len(entries), params.Query, scanned)
+	fmt.Fprintf(&sb, "Found %d matches for %q (scanned %d entries):\n", matches, params.Query, scanned)
 	sb.WriteString(body)
 	if truncated {
 		sb.WriteString("\n(results truncated at 1MB; narrow your query or time range to see more)")
@@ -394,17 +462,83 @@ func (s *Server) handleSearchContainerLogs(ctx context.Context, _ *mcp.CallToolR
 	return textResult(sb.String()), nil, nil
 }
 
+func (s *Server) handleGetLogPatterns(ctx context.Context, _ *mcp.CallToolRequest, params *getLogPatternsParams) (*mcp.CallToolResult, any, error) {
+	if params.Host == "" || params.ContainerID == "" {
+		return errorResult("host and container_id are required"), nil, nil
+	}
+
+	sinceMinutes := 60
+	if params.SinceMinutes != nil && *params.SinceMinutes > 0 {
+		sinceMinutes = *params.SinceMinutes
+	}
+
+	events, cancel, errResult := s.fetchLogs(ctx, params.Host, params.ContainerID, params.Stream, &sinceMinutes)
+	if errResult != nil {
+		return errResult, nil, nil
+	}
+	defer cancel()
+
+	limit := patterns.DefaultLimit
+	if params.Limit != nil && *params.Limit > 0 {
+		limit = *params.Limit
+	}
+
+	summary := patterns.Summarize(events, limit)
+	if summary.Total == 0 {
+		return textResult("(no logs in the specified time range)"), nil, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d log entries form %d patterns; the top %d are:\n", summary.Total, summary.Clusters, len(summary.Patterns))
+	encoder := json.NewEncoder(&sb)
+	for _, pattern := range summary.Patterns {
+		if err := encoder.Encode(pattern); err != nil {
+			return nil, nil, fmt.Errorf("failed to encode pattern: %w", err)
+		}
+	}
+	return textResult(strings.TrimRight(sb.String(), "\n")), nil, nil
+}
+
 func messageToSearchString(msg any) string {
 	switch v := msg.(type) {
 	case string:
 		return v
 	case []string:
 		return strings.Join(v, "\n")
+	case *orderedmap.OrderedMap[string, any], *orderedmap.OrderedMap[string, string]:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
 	default:
 		return fmt.Sprintf("%v", v)
 	}
 }
 
+// messageField returns the value of a top-level field of a structured message.
+func messageField(msg any, field string) (string, bool) {
+	switch v := msg.(type) {
+	case *orderedmap.OrderedMap[string, string]:
+		return v.Get(field)
+	case *orderedmap.OrderedMap[string, any]:
+		value, ok := v.Get(field)
+		if !ok {
+			return "", false
+		}
+		if s, ok := value.(string); ok {
+			return s, true
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprintf("%v", value), true
+		}
+		return string(data), true
+	default:
+		return "", false
+	}
+}
+
 func (s *Server) handleListHosts(ctx context.Context, _ *mcp.CallToolRequest, _ *struct{}) (*mcp.CallToolResult, any, error) {
 	hosts := s.hostService.Hosts()
 
@@ -460,9 +594,12 @@ func (s *Server) handleGetContainerStats(ctx context.Context, _ *mcp.CallToolReq
 	c := containerSvc.Container
 
 	type statEntry struct {
-		CPUPercent    float64 `json:"cpuPercent"`
-		MemoryPercent float64 `json:"memoryPercent"`
-		MemoryUsage   float64 `json:"memoryUsageBytes"`
+		Time             *time.Time `json:"time,omitempty"`
+		CPUPercent       float64    `json:"cpuPercent"`
+		CPUMaxPercent    float64    `json:"cpuMaxPercent,omitempty"`
+		MemoryPercent    float64    `json:"memoryPercent"`
+		MemoryMaxPercent float64    `json:"memoryMaxPercent,omitempty"`
+		MemoryUsage      float64    `json:"memoryUsageBytes"`
 	}
 
 	type statsResponse struct {
@@ -470,29 +607,56 @@ func (s *Server) handleGetContainerStats(ctx context.Context, _ *mcp.CallToolReq
 		ContainerName string      `json:"containerName"`
 		MemoryLimit   uint64      `json:"memoryLimitBytes,omitempty"`
 		CPULimit      float64     `json:"cpuLimit,omitempty"`
+		StepSeconds   float64     `json:"stepSeconds,omitempty"`
 		DataPoints    int         `json:"dataPoints"`
 		Stats         []statEntry `json:"stats"`
 	}
 
-	entries := []statEntry{}
-	if c.Stats != nil {
+	resp := statsResponse{
+		ContainerID:   c.ID,
+		ContainerName: c.Name,
+		MemoryLimit:   c.MemoryLimit,
+		CPULimit:      c.CPULimit,
+		Stats:         []statEntry{},
+	}
+
+	if params.SinceMinutes != nil && *params.SinceMinutes > 0 {
+		var step time.Duration
+		if params.StepSeconds != nil && *params.StepSeconds > 0 {
+			step = time.Duration(*params.StepSeconds) * time.Second
+		}
+		to := time.Now()
+		samples, step, err := s.hostService.StatsHistory(c.Host, c.ID, to.Add(-time.Duration(*params.SinceMinutes)*time.Minute), to, step)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("failed to read stats history: %v", err)}},
+				IsError: true,
+			}, nil, nil
+		}
+		if len(samples) > history.MaxPoints {
+			samples = samples[len(samples)-history.MaxPoints:]
+		}
+		resp.StepSeconds = step.Seconds()
+		for _, sample := range samples {
+			resp.Stats = append(resp.Stats, statEntry{
+				Time:             &sample.Time,
+				CPUPercent:       sample.CPU,
+				CPUMaxPercent:    sample.CPUMax,
+				MemoryPercent:    sample.Memory,
+				MemoryMaxPercent: sample.MemoryMax,
+				MemoryUsage:      sample.MemoryUsage,
+			})
+		}
+	} else if c.Stats != nil {
 		for _, stat := range c.Stats.Data() {
-			entries = append(entries, statEntry{
+			resp.Stats = append(resp.Stats, statEntry{
 				CPUPercent:    stat.CPUPercent,
 				MemoryPercent: stat.MemoryPercent,
 				MemoryUsage:   stat.MemoryUsage,
 			})
 		}
 	}
-
-	resp := statsResponse{
-		ContainerID:   c.ID,
-		ContainerName: c.Name,
-		MemoryLimit:   c.MemoryLimit,
-		CPULimit:      c.CPULimit,
-		DataPoints:    len(entries),
-		Stats:         entries,
-	}
+	resp.DataPoints = len(resp.Stats)
 
 	data, err := json.Marshal(resp)
 	if err != nil {
@@ -503,3 +667,93 @@ func (s *Server) handleGetContainerStats(ctx context.Context, _ *mcp.CallToolReq
 		Content: []mcp.Content{&mcp.TextContent{Text: string(data)}},
 	}, nil, nil
 }
+
+// correlatedLogEntry is a log entry attributed to the container that wrote it.
+type correlatedLogEntry struct {
+	Host          string `json:"host"`
+	ContainerID   string `json:"container_id"`
+	ContainerName string `json:"container_name"`
+	mcpLogEntry
+}
+
+func (s *Server) handleCorrelateLogs(ctx context.Context, _ *mcp.CallToolRequest, params *correlateLogsParams) (*mcp.CallToolResult, any, error) {
+	if strings.TrimSpace(params.ID) == "" {
+		return errorResult("id is required"), nil, nil
+	}
+
+	var fields []string
+	if params.Fields != nil {
+		for field := range strings.SplitSeq(*params.Fields, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	minutes := 60
+	if params.SinceMinutes != nil && *params.SinceMinutes > 0 {
+		minutes = *params.SinceMinutes
+	}
+
+	limit := correlate.DefaultLimit
+	if params.Limit != nil && *params.Limit > 0 {
+		limit = *params.Limit
+	}
+
+	labels := s.resolveLabels(ctx)
+	containers, errs := s.hostService.ListAllContainers(labels)
+	for _, err := range errs {
+		log.Warn().Err(err).Msg("error listing containers for MCP")
+	}
+
+	services := make([]*container_support.ContainerService, 0, len(containers))
+	for _, c := range containers {
+		if service, err := s.hostService.FindContainer(c.Host, c.ID, labels); err == nil {
+			services = append(services, service)
+		}
+	}
+
+	searchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	now := time.Now()
+	hits, err := correlate.Search(searchCtx, services, correlate.Query{
+		ID:     params.ID,
+		Fields: fields,
+		From:   now.Add(-time.Duration(minutes) * time.Minute),
+		To:     now,
+	})
+	if err != nil {
+		return errorResult(err.Error()), nil, nil
+	}
+
+	var sb strings.Builder
+	encoder := json.NewEncoder(&sb)
+	count, truncated := 0, false
+	for hit := range hits {
+		if count >= limit || sb.Len() > maxLogSize {
+			truncated = true
+			break
+		}
+		entry := correlatedLogEntry{
+			Host:          hit.Host,
+			ContainerID:   hit.ContainerID,
+			ContainerName: hit.ContainerName,
+			mcpLogEntry:   newLogEntry(hit.Event),
+		}
+		if err := encoder.Encode(entry); err != nil {
+			return nil, nil, fmt.Errorf("failed to encode log entry: %w", err)
+		}
+		count++
+	}
+
+	if count == 0 {
+		return textResult(fmt.Sprintf("(no entries with %q in %d containers)", params.ID, len(services))), nil, nil
+	}
+
+	result := fmt.Sprintf("Found %d entries with %q in %d containers:\n%s", count, params.ID, len(services), strings.TrimRight(sb.String(), "\n"))
+	if truncated {
+		result += "\n(results truncated; narrow the time range or raise the limit to see more)"
+	}
+	return textResult(result), nil, nil
+}
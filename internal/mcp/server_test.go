@@ -10,11 +10,13 @@ import (
 
 	"github.com/amir20/dozzle/internal/auth"
 	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/history"
 	container_support "github.com/amir20/dozzle/internal/support/container"
 	"github.com/amir20/dozzle/internal/utils"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
 )
 
 type mockHostService struct {
@@ -24,6 +26,8 @@ type mockHostService struct {
 	listErrs   []error
 	logEvents  []*container.LogEvent
 	logErr     error
+	samples    []history.Sample
+	historyErr error
 
 	// gotLabels records the last label filter passed to a lookup so tests can
 	// assert the requesting user's filter is applied instead of the global one.
@@ -81,6 +85,10 @@ func (m *mockHostService) Hosts() []container.Host {
 	return m.hosts
 }
 
+func (m *mockHostService) StatsHistory(host string, id string, from time.Time, to time.Time, step time.Duration) ([]history.Sample, time.Duration, error) {
+	return m.samples, time.Minute, m.historyErr
+}
+
 func TestReadToolsUseRequestingUsersFilter(t *testing.T) {
 	svc := &mockHostService{
 		containers: []container.Container{{ID: "abc123", Name: "web", Host: "local"}},
@@ -253,6 +261,30 @@ func TestGetContainerStats(t *testing.T) {
 	assert.Contains(t, text, "2048000")
 }
 
+func TestGetContainerStatsHistory(t *testing.T) {
+	svc := &mockHostService{
+		containers: []container.Container{{ID: "abc123", Name: "web", Host: "local"}},
+		samples: []history.Sample{
+			{Time: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), CPU: 12.5, CPUMax: 80, Memory: 40, MemoryMax: 45, Count: 60},
+		},
+	}
+	s := NewServer(svc, nil, "test")
+
+	since := 24 * 60
+	result, _, err := s.handleGetContainerStats(context.Background(), nil, &getContainerStatsParams{Host: "local", ContainerID: "abc123", SinceMinutes: &since})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, `"stepSeconds":60`)
+	assert.Contains(t, text, `"cpuMaxPercent":80`)
+	assert.Contains(t, text, "2024-03-01T12:00:00Z")
+
+	svc.historyErr = history.ErrDisabled
+	result, _, err = s.handleGetContainerStats(context.Background(), nil, &getContainerStatsParams{Host: "local", ContainerID: "abc123", SinceMinutes: &since})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
 func TestGetContainerStatsNotFound(t *testing.T) {
 	svc := &mockHostService{
 		containers: []container.Container{},
@@ -332,7 +364,9 @@ func TestNewServerRegistersTools(t *testing.T) {
 	assert.Contains(t, toolNames, "search_container_logs")
 	assert.Contains(t, toolNames, "list_hosts")
 	assert.Contains(t, toolNames, "get_container_stats")
-	assert.Len(t, tools.Tools, 5)
+	assert.Contains(t, toolNames, "get_log_patterns")
+	assert.Contains(t, toolNames, "correlate_logs")
+	assert.Len(t, tools.Tools, 7)
 }
 
 func TestGetContainerLogs(t *testing.T) {
@@ -572,3 +606,172 @@ func TestSearchContainerLogsTruncates(t *testing.T) {
 	text := result.Content[0].(*mcp.TextContent).Text
 	assert.Contains(t, text, "results truncated")
 }
+
+func TestSearchContainerLogsField(t *testing.T) {
+	now := time.Now()
+	fields := func(status, path string) *orderedmap.OrderedMap[string, string] {
+		m := orderedmap.New[string, string]()
+		m.Set("status", status)
+		m.Set("path", path)
+		return m
+	}
+	svc := &mockHostService{
+		containers: []container.Container{{ID: "abc123", Name: "web", Host: "local"}},
+		logEvents: []*container.LogEvent{
+			{Timestamp: now.UnixMilli(), Level: "unknown", Stream: "stdout", Type: container.LogTypeComplex, Message: fields("200", "/500-page")},
+			{Timestamp: now.UnixMilli(), Level: "unknown", Stream: "stdout", Type: container.LogTypeComplex, Message: fields("500", "/checkout")},
+			{Timestamp: now.UnixMilli(), Level: "error", Stream: "stderr", Type: container.LogTypeSingle, RawMessage: "status 500"},
+		},
+	}
+
+	s := NewServer(svc, nil, "test")
+
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+
+	_, err := s.mcpServer.Connect(ctx, st, nil)
+	require.NoError(t, err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(ctx, ct, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "search_container_logs",
+		Arguments: map[string]any{"host": "local", "container_id": "abc123", "query": "500", "field": "status"},
+	})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "Found 1 matches")
+	assert.Contains(t, text, "/checkout")
+}
+
+func TestGetLogPatterns(t *testing.T) {
+	now := time.Now()
+	svc := &mockHostService{
+		containers: []container.Container{{ID: "abc123", Name: "web", Host: "local"}},
+		logEvents: []*container.LogEvent{
+			{Timestamp: now.UnixMilli(), Level: "info", Stream: "stdout", Type: container.LogTypeSingle, Message: "GET /users/17 took 12ms"},
+			{Timestamp: now.UnixMilli(), Level: "info", Stream: "stdout", Type: container.LogTypeSingle, Message: "GET /users/17 took 40ms"},
+			{Timestamp: now.UnixMilli(), Level: "error", Stream: "stderr", Type: container.LogTypeSingle, Message: "connection refused"},
+		},
+	}
+
+	s := NewServer(svc, nil, "test")
+
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+
+	_, err := s.mcpServer.Connect(ctx, st, nil)
+	require.NoError(t, err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(ctx, ct, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "get_log_patterns",
+		Arguments: map[string]any{"host": "local", "container_id": "abc123"},
+	})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "3 log entries form 2 patterns")
+	assert.Contains(t, text, `"template":"GET /users/17 took \u003c*\u003e"`)
+}
+
+func TestCorrelateLogs(t *testing.T) {
+	now := time.Now()
+	svc := &mockHostService{
+		containers: []container.Container{
+			{ID: "abc123", Name: "api", Host: "local"},
+			{ID: "def456", Name: "worker", Host: "remote"},
+		},
+		logEvents: []*container.LogEvent{
+			{Timestamp: now.UnixMilli(), Level: "info", Type: container.LogTypeSingle, Message: "handling req-42", RawMessage: "handling req-42"},
+			{Timestamp: now.UnixMilli(), Level: "info", Type: container.LogTypeSingle, Message: "handling req-7", RawMessage: "handling req-7"},
+		},
+	}
+
+	s := NewServer(svc, nil, "test")
+
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+
+	_, err := s.mcpServer.Connect(ctx, st, nil)
+	require.NoError(t, err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(ctx, ct, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "correlate_logs",
+		Arguments: map[string]any{"id": "req-42"},
+	})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "Found 2 entries")
+	assert.Contains(t, text, `"container_name":"api"`)
+	assert.Contains(t, text, `"host":"remote"`)
+	assert.NotContains(t, text, "req-7")
+
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "correlate_logs",
+		Arguments: map[string]any{"id": "req-42", "fields": "trace id"},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestSearchContainerLogsWithContext(t *testing.T) {
+	now := time.Now()
+	svc := &mockHostService{
+		containers: []container.Container{
+			{ID: "abc123", Name: "web", Host: "local"},
+		},
+		logEvents: []*container.LogEvent{
+			{Timestamp: now.UnixMilli(), Level: "info", Type: container.LogTypeSingle, RawMessage: "connecting to gateway"},
+			{Timestamp: now.UnixMilli(), Level: "error", Type: container.LogTypeSingle, RawMessage: "payment gateway timeout"},
+			{Timestamp: now.UnixMilli(), Level: "info", Type: container.LogTypeSingle, RawMessage: "retrying in 5s"},
+			{Timestamp: now.UnixMilli(), Level: "info", Type: container.LogTypeSingle, RawMessage: "unrelated"},
+		},
+	}
+
+	s := NewServer(svc, nil, "test")
+
+	ctx := context.Background()
+	ct, st := mcp.NewInMemoryTransports()
+
+	_, err := s.mcpServer.Connect(ctx, st, nil)
+	require.NoError(t, err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(ctx, ct, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "search_container_logs",
+		Arguments: map[string]any{"host": "local", "container_id": "abc123", "query": "timeout", "before": 1, "after": 1},
+	})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "Found 1 matches")
+	assert.Contains(t, text, `"message":"connecting to gateway","context":true`)
+	assert.Contains(t, text, `"message":"retrying in 5s","context":true`)
+	assert.NotContains(t, text, "unrelated")
+
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "search_container_logs",
+		Arguments: map[string]any{"host": "local", "container_id": "abc123", "query": "timeout", "after": 500},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,127 @@
+package notification
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/amir20/dozzle/types"
+	"github.com/rs/zerolog/log"
+)
+
+// metricResolveRatio is the share of matching samples in the window at or below which a
+// firing metric alert resolves. It is well below the 80% needed to fire so alerts
+// hovering around the threshold don't flap.
+const metricResolveRatio = 0.2
+
+// AlertState is a metric or event alert that fired for a container and hasn't resolved yet.
+// Containers without a state are ok.
+type AlertState struct {
+	Type      types.NotificationType
+	Container types.NotificationContainer
+	Detail    string
+	Since     time.Time
+	Event     *types.NotificationEvent // event that fired an event alert
+}
+
+// FiringAlerts returns the alerts of this subscription that are currently firing
+func (s *Subscription) FiringAlerts() []types.FiringAlert {
+	var alerts []types.FiringAlert
+	if s.AlertStates == nil {
+		return alerts
+	}
+	s.AlertStates.Range(func(_ string, state *AlertState) bool {
+		alerts = append(alerts, types.FiringAlert{
+			SubscriptionID:   s.ID,
+			SubscriptionName: s.Name,
+			Type:             state.Type,
+			Container:        state.Container,
+			Detail:           state.Detail,
+			Since:            state.Since,
+		})
+		return true
+	})
+	slices.SortFunc(alerts, func(a, b types.FiringAlert) int {
+		return a.Since.Compare(b.Since)
+	})
+	return alerts
+}
+
+// IsMetricResolved returns true if few enough samples in the window still match
+// for a firing metric alert to resolve.
+func (s *Subscription) IsMetricResolved(containerID string, matched bool) bool {
+	if s.GetSampleWindowSeconds() <= 1 {
+		return !matched
+	}
+
+	buf, ok := s.MetricSampleBuffers.Load(containerID)
+	if !ok || buf.Len() == 0 {
+		return !matched
+	}
+
+	trueCount := 0
+	for _, v := range buf.Data() {
+		if v {
+			trueCount++
+		}
+	}
+
+	return float64(trueCount)/float64(buf.Len()) <= metricResolveRatio
+}
+
+// canRecover returns true if an event alert fired by event resolves on a later event,
+// like a container becoming healthy again or starting after it died.
+func canRecover(event types.NotificationEvent) bool {
+	switch event.Name {
+	case "health_status":
+		return event.Attributes["healthStatus"] != "healthy"
+	case "die", "oom", "kill", "stop":
+		return true
+	}
+	return false
+}
+
+// isRecovery returns true if event resolves an event alert that fired on firing
+func isRecovery(firing, event types.NotificationEvent) bool {
+	switch firing.Name {
+	case "health_status":
+		return event.Name == "health_status" && event.Attributes["healthStatus"] == "healthy"
+	case "die", "oom", "kill", "stop":
+		return event.Name == "start"
+	}
+	return false
+}
+
+// setFiring records a fired alert, keeping the time it started firing if it already was
+func (s *Subscription) setFiring(containerID string, state *AlertState) {
+	if existing, ok := s.AlertStates.Load(containerID); ok {
+		state.Since = existing.Since
+	}
+	s.AlertStates.Store(containerID, state)
+}
+
+// sendResolved sends a resolved notification for the firing alert of a container
+// and returns it to ok. Dozzle Cloud only receives firing notifications.
+func (m *Manager) sendResolved(sub *Subscription, containerID string, notification types.Notification) {
+	state, ok := sub.AlertStates.LoadAndDelete(containerID)
+	if !ok {
+		return
+	}
+
+	log.Debug().
+		Str("containerID", containerID).
+		Str("subscription", sub.Name).
+		Dur("duration", time.Since(state.Since)).
+		Msg("Alert resolved")
+
+	notification.ID = fmt.Sprintf("%s-resolved-%d", containerID, time.Now().UnixNano())
+	notification.Status = types.StatusResolved
+	notification.Timestamp = time.Now()
+
+	if sub.DispatcherID == 0 {
+		return
+	}
+	if d, ok := m.getDispatcher(sub.DispatcherID); ok {
+		go m.sendNotification(d, notification, sub.DispatcherID)
+	}
+}
@@ -0,0 +1,170 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/utils"
+	"github.com/amir20/dozzle/types"
+	"github.com/puzpuzpuz/xsync/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAlertSubscription(t *testing.T, sub *Subscription) *Subscription {
+	t.Helper()
+	sub.ID = 1
+	sub.Name = "Alert"
+	sub.Enabled = true
+	sub.DispatcherID = 1
+	sub.ContainerExpression = `name == "api"`
+	sub.MetricCooldowns = xsync.NewMap[string, time.Time]()
+	sub.MetricSampleBuffers = xsync.NewMap[string, *utils.RingBuffer[bool]]()
+	sub.EventCooldowns = xsync.NewMap[string, time.Time]()
+	sub.AlertStates = xsync.NewMap[string, *AlertState]()
+	sub.TriggeredContainerIDs = xsync.NewMap[string, struct{}]()
+	require.NoError(t, sub.CompileExpressions())
+	return sub
+}
+
+func statEvent(cpu float64) *ContainerStatEvent {
+	return &ContainerStatEvent{
+		Stat:      container.ContainerStat{ID: "abc", CPUPercent: cpu},
+		Container: container.Container{ID: "abc", Name: "api"},
+		Host:      container.Host{ID: "local", NCPU: 1},
+	}
+}
+
+func dockerEvent(name string, attributes map[string]string) *ContainerEventEntry {
+	return &ContainerEventEntry{
+		Event:     container.ContainerEvent{Name: name, ActorID: "abc", ActorAttributes: attributes},
+		Container: container.Container{ID: "abc", Name: "api"},
+	}
+}
+
+func TestProcessStatEvent_FiringAndResolved(t *testing.T) {
+	m, d := newTestManager(t)
+	sub := newAlertSubscription(t, &Subscription{MetricExpression: "cpu > 80", SampleWindow: 1, Cooldown: 300})
+	m.subscriptions.Store(sub.ID, sub)
+
+	m.processStatEvent(statEvent(95))
+	n := receive(t, d)
+	assert.Equal(t, types.StatusFiring, n.Status)
+
+	alerts := sub.FiringAlerts()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "api", alerts[0].Container.Name)
+	assert.Equal(t, "CPU: 95.0%, Memory: 0.0%", alerts[0].Detail)
+
+	// Still firing, within the cooldown
+	m.processStatEvent(statEvent(90))
+	assertNothingSent(t, d)
+
+	m.processStatEvent(statEvent(10))
+	n = receive(t, d)
+	assert.Equal(t, types.StatusResolved, n.Status)
+	assert.Equal(t, types.MetricNotification, n.Type)
+	assert.Empty(t, sub.FiringAlerts())
+
+	// Nothing more to resolve
+	m.processStatEvent(statEvent(10))
+	assertNothingSent(t, d)
+}
+
+func TestSubscription_IsMetricResolved(t *testing.T) {
+	sub := &Subscription{SampleWindow: 5, MetricSampleBuffers: xsync.NewMap[string, *utils.RingBuffer[bool]]()}
+	for range 5 {
+		sub.RecordMetricSample("abc", true)
+	}
+	sub.RecordMetricSample("abc", false)
+	sub.RecordMetricSample("abc", false)
+	assert.False(t, sub.IsMetricResolved("abc", false), "60% still matching")
+
+	sub.RecordMetricSample("abc", false)
+	sub.RecordMetricSample("abc", false)
+	assert.True(t, sub.IsMetricResolved("abc", false), "20% matching")
+}
+
+func TestProcessDockerEvent_UnhealthyThenHealthy(t *testing.T) {
+	m, d := newTestManager(t)
+	sub := newAlertSubscription(t, &Subscription{EventExpression: `name == "health_status" && attributes["healthStatus"] == "unhealthy"`})
+	m.subscriptions.Store(sub.ID, sub)
+
+	m.processDockerEvent(dockerEvent("health_status", map[string]string{"healthStatus": "unhealthy"}))
+	n := receive(t, d)
+	assert.Equal(t, types.StatusFiring, n.Status)
+	assert.Equal(t, "Container event: health_status (unhealthy)", n.Detail)
+	require.Len(t, sub.FiringAlerts(), 1)
+
+	m.processDockerEvent(dockerEvent("start", nil))
+	assertNothingSent(t, d)
+
+	m.processDockerEvent(dockerEvent("health_status", map[string]string{"healthStatus": "healthy"}))
+	n = receive(t, d)
+	assert.Equal(t, types.StatusResolved, n.Status)
+	assert.Equal(t, "Container event: health_status (healthy)", n.Detail)
+	assert.Empty(t, sub.FiringAlerts())
+}
+
+func TestProcessDockerEvent_OneOffEvents(t *testing.T) {
+	m, d := newTestManager(t)
+	sub := newAlertSubscription(t, &Subscription{EventExpression: `name == "restart"`})
+	m.subscriptions.Store(sub.ID, sub)
+
+	m.processDockerEvent(dockerEvent("restart", nil))
+	n := receive(t, d)
+	assert.Empty(t, n.Status)
+	assert.Empty(t, sub.FiringAlerts())
+}
+
+func TestSendResolved_SkipsCloud(t *testing.T) {
+	m, _ := newTestManager(t)
+	cloud := &recordingDispatcher{sent: make(chan types.Notification, 1)}
+	m.SetCloudDispatcher(cloud)
+	sub := newAlertSubscription(t, &Subscription{EventExpression: `name == "die"`})
+	sub.DispatcherID = 0
+	m.subscriptions.Store(sub.ID, sub)
+
+	m.processDockerEvent(dockerEvent("die", map[string]string{"exitCode": "1"}))
+	assert.Equal(t, types.StatusFiring, receive(t, cloud).Status)
+
+	m.processDockerEvent(dockerEvent("start", nil))
+	assertNothingSent(t, cloud)
+	assert.Empty(t, sub.FiringAlerts())
+}
+
+func TestProcessEvents_OnlyResolveTheirOwnAlerts(t *testing.T) {
+	m, d := newTestManager(t)
+
+	// a rule edited from metric to event alerts keeps its firing alerts
+	eventSub := newAlertSubscription(t, &Subscription{EventExpression: `name == "die"`})
+	eventSub.AlertStates.Store("abc", &AlertState{Type: types.MetricNotification, Since: time.Now()})
+	m.subscriptions.Store(eventSub.ID, eventSub)
+
+	m.processDockerEvent(dockerEvent("start", nil))
+	assertNothingSent(t, d)
+	assert.Len(t, eventSub.FiringAlerts(), 1)
+	m.subscriptions.Delete(eventSub.ID)
+
+	dieEvent := types.NotificationEvent{Name: "die", Attributes: map[string]string{"exitCode": "1"}}
+	metricSub := newAlertSubscription(t, &Subscription{MetricExpression: "cpu > 80", SampleWindow: 1})
+	metricSub.ID = 2
+	metricSub.AlertStates.Store("abc", &AlertState{Type: types.EventNotification, Event: &dieEvent, Since: time.Now()})
+	m.subscriptions.Store(metricSub.ID, metricSub)
+
+	m.processStatEvent(statEvent(10))
+	assertNothingSent(t, d)
+	assert.Len(t, metricSub.FiringAlerts(), 1)
+}
+
+func TestSubscription_RejectsMoreThanOneAlertKind(t *testing.T) {
+	for _, sub := range []*Subscription{
+		{LogExpression: `level == "error"`, MetricExpression: "cpu > 80"},
+		{MetricExpression: "cpu > 80", EventExpression: `name == "die"`},
+		{LogExpression: `level == "error"`, EventExpression: `name == "die"`},
+	} {
+		assert.EqualError(t, sub.ValidateAlertKind(), "only one of log, metric and event expressions can be set")
+	}
+	assert.NoError(t, (&Subscription{EventExpression: `name == "die"`}).ValidateAlertKind())
+}
@@ -58,18 +58,34 @@ func (m *Manager) LoadConfig(r io.Reader) error {
 			EventExpression:     sub.EventExpression,
 			Cooldown:            sub.Cooldown,
 			SampleWindow:        sub.SampleWindow,
+			GroupBy:             sub.GroupBy,
+			DigestWindow:        sub.DigestWindow,
 		}
 	}
 
 	dispatchers := make([]types.DispatcherConfig, len(config.Dispatchers))
 	for i, d := range config.Dispatchers {
 		dispatchers[i] = types.DispatcherConfig{
-			ID:       d.ID,
-			Name:     d.Name,
-			Type:     d.Type,
-			URL:      d.URL,
-			Template: d.Template,
-			Headers:  d.Headers,
+			ID:           d.ID,
+			Name:         d.Name,
+			Type:         d.Type,
+			URL:          d.URL,
+			Template:     d.Template,
+			Headers:      d.Headers,
+			Channel:      d.Channel,
+			Topic:        d.Topic,
+			Priority:     d.Priority,
+			Token:        d.Token,
+			User:         d.User,
+			DozzleURL:    d.DozzleURL,
+			SMTPHost:     d.SMTPHost,
+			SMTPPort:     d.SMTPPort,
+			SMTPSecurity: d.SMTPSecurity,
+			SMTPUsername: d.SMTPUsername,
+			SMTPPassword: d.SMTPPassword,
+			From:         d.From,
+			To:           d.To,
+			Subject:      d.Subject,
 		}
 	}
 
@@ -79,6 +95,8 @@ func (m *Manager) LoadConfig(r io.Reader) error {
 // HandleNotificationConfig implements agent.NotificationConfigHandler interface
 // It atomically replaces all subscriptions and dispatchers with new state from the main server
 func (m *Manager) HandleNotificationConfig(subscriptions []types.SubscriptionConfig, dispatchers []types.DispatcherConfig) error {
+	subscriptions = splitAlertKinds(subscriptions)
+
 	// Snapshot existing subscriptions to preserve runtime stats
 	existing := make(map[int]*Subscription)
 	m.subscriptions.Range(func(id int, sub *Subscription) bool {
@@ -128,6 +146,8 @@ func (m *Manager) HandleNotificationConfig(subscriptions []types.SubscriptionCon
 			EventExpression:     sub.EventExpression,
 			Cooldown:            sub.Cooldown,
 			SampleWindow:        sub.SampleWindow,
+			GroupBy:             sub.GroupBy,
+			DigestWindow:        sub.DigestWindow,
 		}
 
 		if old, ok := existing[sub.ID]; ok {
@@ -160,11 +180,26 @@ func (m *Manager) HandleNotificationConfig(subscriptions []types.SubscriptionCon
 				})
 			}
 
+			s.LogCooldowns = xsync.NewMap[string, time.Time]()
+			if old.LogCooldowns != nil {
+				old.LogCooldowns.Range(func(key string, t time.Time) bool {
+					s.LogCooldowns.Store(key, t)
+					return true
+				})
+			}
+
+			// Open digests are shared so pending flushes still see matches added after the reload
+			s.LogDigests = old.LogDigests
+
+			// Firing alerts keep firing so they can still resolve
+			s.AlertStates = old.AlertStates
+
 			// MetricSampleBuffers: start fresh since ring buffers can't be safely cloned
 		}
 
 		if err := m.loadSubscription(s); err != nil {
-			return fmt.Errorf("failed to load subscription %s: %w", sub.Name, err)
+			log.Warn().Err(err).Int("id", sub.ID).Str("name", sub.Name).Msg("Skipping invalid subscription")
+			continue
 		}
 	}
 
@@ -174,15 +209,29 @@ func (m *Manager) HandleNotificationConfig(subscriptions []types.SubscriptionCon
 			continue
 		}
 		d, err := createDispatcher(DispatcherConfig{
-			ID:       dc.ID,
-			Name:     dc.Name,
-			Type:     dc.Type,
-			URL:      dc.URL,
-			Template: dc.Template,
-			Headers:  dc.Headers,
+			ID:           dc.ID,
+			Name:         dc.Name,
+			Type:         dc.Type,
+			URL:          dc.URL,
+			Template:     dc.Template,
+			Headers:      dc.Headers,
+			Channel:      dc.Channel,
+			Topic:        dc.Topic,
+			Priority:     dc.Priority,
+			Token:        dc.Token,
+			User:         dc.User,
+			DozzleURL:    dc.DozzleURL,
+			SMTPHost:     dc.SMTPHost,
+			SMTPPort:     dc.SMTPPort,
+			SMTPSecurity: dc.SMTPSecurity,
+			SMTPUsername: dc.SMTPUsername,
+			SMTPPassword: dc.SMTPPassword,
+			From:         dc.From,
+			To:           dc.To,
+			Subject:      dc.Subject,
 		})
 		if err != nil {
-			log.Warn().Err(err).Str("name", dc.Name).Str("type", dc.Type).Msg("Skipping unknown dispatcher type")
+			log.Warn().Err(err).Str("name", dc.Name).Str("type", dc.Type).Msg("Skipping invalid dispatcher")
 			continue
 		}
 		m.dispatchers.Store(dc.ID, d)
@@ -195,17 +244,99 @@ func (m *Manager) HandleNotificationConfig(subscriptions []types.SubscriptionCon
 	return nil
 }
 
+// splitAlertKinds splits the rules that set more than one of the log, metric
+// and event expressions, which were accepted before a rule alerted on one kind
+// only. The log, metric or event part keeps the ID of the rule, in that order,
+// and the others become new rules.
+func splitAlertKinds(subscriptions []types.SubscriptionConfig) []types.SubscriptionConfig {
+	maxID := 0
+	for _, sub := range subscriptions {
+		maxID = max(maxID, sub.ID)
+	}
+
+	result := make([]types.SubscriptionConfig, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		parts := make([]types.SubscriptionConfig, 0, 3)
+		for _, kind := range []struct {
+			name       string
+			expression string
+		}{{"log", sub.LogExpression}, {"metric", sub.MetricExpression}, {"event", sub.EventExpression}} {
+			if kind.expression == "" {
+				continue
+			}
+			part := sub
+			part.LogExpression, part.MetricExpression, part.EventExpression = "", "", ""
+			switch kind.name {
+			case "log":
+				part.LogExpression = kind.expression
+			case "metric":
+				part.MetricExpression = kind.expression
+			case "event":
+				part.EventExpression = kind.expression
+			}
+			if len(parts) > 0 {
+				maxID++
+				part.ID = maxID
+				part.Name = fmt.Sprintf("%s (%s)", sub.Name, kind.name)
+			}
+			parts = append(parts, part)
+		}
+
+		if len(parts) > 1 {
+			log.Warn().Int("id", sub.ID).Str("name", sub.Name).Int("rules", len(parts)).Msg("Splitting subscription with more than one alert kind")
+			result = append(result, parts...)
+		} else {
+			result = append(result, sub)
+		}
+	}
+	return result
+}
+
 // createDispatcher creates a dispatcher from a DispatcherConfig.
 // Cloud dispatchers are not created here; they are managed via cloud.yml and SetCloudDispatcher.
 func createDispatcher(config DispatcherConfig) (dispatcher.Dispatcher, error) {
 	switch config.Type {
 	case "webhook":
 		return dispatcher.NewWebhookDispatcher(config.Name, config.URL, config.Template, config.Headers)
+	case dispatcher.TypeSlack, dispatcher.TypeDiscord, dispatcher.TypeTeams, dispatcher.TypeTelegram,
+		dispatcher.TypeNtfy, dispatcher.TypeGotify, dispatcher.TypePushover:
+		return dispatcher.NewNativeDispatcher(config.Name, config.Type, config.NativeSettings())
+	case dispatcher.TypeEmail:
+		return dispatcher.NewEmailDispatcher(config.Name, config.EmailSettings())
 	default:
 		return nil, fmt.Errorf("unknown dispatcher type: %s", config.Type)
 	}
 }
 
+// NativeSettings returns the settings of a native dispatcher.
+func (d DispatcherConfig) NativeSettings() dispatcher.NativeSettings {
+	return dispatcher.NativeSettings{
+		URL:       d.URL,
+		Channel:   d.Channel,
+		Topic:     d.Topic,
+		Priority:  d.Priority,
+		Token:     d.Token,
+		User:      d.User,
+		DozzleURL: d.DozzleURL,
+	}
+}
+
+// EmailSettings returns the settings of an email dispatcher.
+func (d DispatcherConfig) EmailSettings() dispatcher.EmailSettings {
+	return dispatcher.EmailSettings{
+		Host:      d.SMTPHost,
+		Port:      d.SMTPPort,
+		Security:  d.SMTPSecurity,
+		Username:  d.SMTPUsername,
+		Password:  d.SMTPPassword,
+		From:      d.From,
+		To:        d.To,
+		Subject:   d.Subject,
+		Body:      d.Template,
+		DozzleURL: d.DozzleURL,
+	}
+}
+
 // loadSubscription loads a subscription with its existing ID (used when loading from config)
 func (m *Manager) loadSubscription(sub *Subscription) error {
 	if err := sub.CompileExpressions(); err != nil {
@@ -221,6 +352,15 @@ func (m *Manager) loadSubscription(sub *Subscription) error {
 	if sub.EventCooldowns == nil {
 		sub.EventCooldowns = xsync.NewMap[string, time.Time]()
 	}
+	if sub.LogCooldowns == nil {
+		sub.LogCooldowns = xsync.NewMap[string, time.Time]()
+	}
+	if sub.LogDigests == nil {
+		sub.LogDigests = xsync.NewMap[string, *LogDigest]()
+	}
+	if sub.AlertStates == nil {
+		sub.AlertStates = xsync.NewMap[string, *AlertState]()
+	}
 
 	m.subscriptions.Store(sub.ID, sub)
 	log.Debug().Str("name", sub.Name).Int("id", sub.ID).Msg("Loaded subscription")
@@ -0,0 +1,116 @@
+package notification
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/amir20/dozzle/internal/notification/dispatcher"
+	"github.com/puzpuzpuz/xsync/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDispatcher_NativeRoundTrip(t *testing.T) {
+	config := DispatcherConfig{
+		ID:        3,
+		Name:      "Phones",
+		Type:      dispatcher.TypeGotify,
+		URL:       "https://gotify.example.com",
+		Priority:  "8",
+		Token:     "app-token",
+		DozzleURL: "https://dozzle.example.com",
+	}
+
+	d, err := createDispatcher(config)
+	require.NoError(t, err)
+
+	m := &Manager{dispatchers: xsync.NewMap[int, dispatcher.Dispatcher]()}
+	m.dispatchers.Store(config.ID, d)
+	assert.Equal(t, []DispatcherConfig{config}, m.Dispatchers())
+}
+
+func TestCreateDispatcher_InvalidNativeSettings(t *testing.T) {
+	_, err := createDispatcher(DispatcherConfig{Name: "Chat", Type: dispatcher.TypeTelegram, Token: "123:abc"})
+	assert.ErrorContains(t, err, "telegram requires a bot token and a chat ID")
+}
+
+func TestCreateDispatcher_EmailRoundTrip(t *testing.T) {
+	config := DispatcherConfig{
+		ID:           4,
+		Name:         "On-call email",
+		Type:         dispatcher.TypeEmail,
+		Template:     "<p>{{ .Detail }}</p>",
+		SMTPHost:     "smtp.example.com",
+		SMTPPort:     465,
+		SMTPSecurity: dispatcher.SecurityTLS,
+		SMTPUsername: "dozzle",
+		SMTPPassword: "secret",
+		From:         "dozzle@example.com",
+		To:           []string{"oncall@example.com"},
+		Subject:      "{{ .Container.Name }}",
+	}
+
+	d, err := createDispatcher(config)
+	require.NoError(t, err)
+
+	m := &Manager{dispatchers: xsync.NewMap[int, dispatcher.Dispatcher]()}
+	m.dispatchers.Store(config.ID, d)
+	assert.Equal(t, []DispatcherConfig{config}, m.Dispatchers())
+}
+
+func TestLoadConfig_SplitsRulesWithSeveralAlertKinds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	m := NewManager(NewContainerLogListener(ctx, nil), NewContainerStatsListener(ctx, nil), NewContainerEventListener(ctx, nil))
+	t.Cleanup(m.cancel)
+
+	config := `
+subscriptions:
+  - id: 1
+    name: API
+    enabled: true
+    dispatcherId: 1
+    containerExpression: name == "api"
+    logExpression: level == "error"
+    metricExpression: cpu > 80
+  - id: 2
+    name: Broken
+    enabled: true
+    dispatcherId: 1
+    containerExpression: name ==
+    logExpression: level == "error"
+  - id: 3
+    name: Restarts
+    enabled: true
+    dispatcherId: 1
+    containerExpression: name == "db"
+    eventExpression: name == "restart"
+dispatchers:
+  - id: 1
+    name: Hook
+    type: webhook
+    url: https://example.com/hook
+`
+	require.NoError(t, m.LoadConfig(strings.NewReader(config)))
+
+	subs := m.Subscriptions()
+	require.Len(t, subs, 3, "the broken rule is skipped")
+	assert.Equal(t, 1, subs[0].ID)
+	assert.Equal(t, "API", subs[0].Name)
+	assert.Equal(t, `level == "error"`, subs[0].LogExpression)
+	assert.Empty(t, subs[0].MetricExpression)
+	assert.Equal(t, 3, subs[1].ID)
+	assert.Equal(t, 4, subs[2].ID)
+	assert.Equal(t, "API (metric)", subs[2].Name)
+	assert.Equal(t, "cpu > 80", subs[2].MetricExpression)
+	assert.Equal(t, `name == "api"`, subs[2].ContainerExpression)
+	assert.Empty(t, subs[2].LogExpression)
+
+	require.Len(t, m.Dispatchers(), 1, "dispatchers are loaded")
+
+	// New rules don't reuse the IDs of split ones
+	sub := &Subscription{Name: "New", ContainerExpression: "true", LogExpression: "true"}
+	require.NoError(t, m.AddSubscription(sub))
+	assert.Equal(t, 5, sub.ID)
+}
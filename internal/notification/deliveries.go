@@ -0,0 +1,272 @@
+package notification
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amir20/dozzle/internal/notification/dispatcher"
+	store_support "github.com/amir20/dozzle/internal/support/store"
+	"github.com/amir20/dozzle/types"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DefaultDeliveryLogPath = "./data/notification_deliveries.jsonl"
+
+	// MaxDeliveries bounds the deliveries kept by a DeliveryLog
+	MaxDeliveries = 1000
+	// maxDeliverySummary bounds the summary of the notification kept with a delivery
+	maxDeliverySummary = 200
+)
+
+// DeliveryLog keeps the last MaxDeliveries deliveries of notifications in memory and
+// appends them to a JSON lines file so they survive restarts. The file is rewritten
+// with the kept deliveries once it holds twice as many. Safe for concurrent use.
+type DeliveryLog struct {
+	path string
+
+	mu         sync.Mutex
+	deliveries []types.NotificationDelivery // oldest first
+	lines      int                          // lines in the file, including the ones no longer kept
+}
+
+// NewDeliveryLog loads the deliveries at path. A missing file has no deliveries, and an
+// empty path keeps them in memory only.
+func NewDeliveryLog(path string) *DeliveryLog {
+	l := &DeliveryLog{path: path}
+	if path == "" {
+		return l
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return l
+	}
+	defer file.Close()
+
+	l.lines, err = store_support.ReadLines(file, func(delivery types.NotificationDelivery) {
+		l.deliveries = append(l.deliveries, delivery)
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("Could not read notification deliveries")
+	}
+	if extra := len(l.deliveries) - MaxDeliveries; extra > 0 {
+		l.deliveries = slices.Delete(l.deliveries, 0, extra)
+	}
+
+	log.Debug().Str("path", path).Int("deliveries", len(l.deliveries)).Msg("Loaded notification deliveries")
+	return l
+}
+
+// Add records a delivery, dropping the oldest one when MaxDeliveries are kept
+func (l *DeliveryLog) Add(delivery types.NotificationDelivery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.deliveries = append(l.deliveries, delivery)
+	if extra := len(l.deliveries) - MaxDeliveries; extra > 0 {
+		l.deliveries = slices.Delete(l.deliveries, 0, extra)
+	}
+
+	if l.path == "" {
+		return
+	}
+
+	var err error
+	if l.lines >= 2*MaxDeliveries {
+		err = l.rewrite()
+	} else {
+		err = l.append(delivery)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("path", l.path).Msg("Could not write notification delivery")
+	}
+}
+
+// List returns the kept deliveries, newest first
+func (l *DeliveryLog) List() []types.NotificationDelivery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	deliveries := slices.Clone(l.deliveries)
+	slices.Reverse(deliveries)
+	return deliveries
+}
+
+func (l *DeliveryLog) append(delivery types.NotificationDelivery) error {
+	if err := ensureDir(l.path); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	l.lines++
+	return nil
+}
+
+// rewrite replaces the file with the kept deliveries
+func (l *DeliveryLog) rewrite() error {
+	if err := ensureDir(l.path); err != nil {
+		return err
+	}
+
+	tmp := l.path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	encoder := json.NewEncoder(w)
+	for _, delivery := range l.deliveries {
+		if err := encoder.Encode(delivery); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := errors.Join(w.Flush(), file.Close()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return err
+	}
+	l.lines = len(l.deliveries)
+	return nil
+}
+
+// DeliveryFilter selects deliveries. Zero values match every delivery.
+type DeliveryFilter struct {
+	SubscriptionID int
+	DispatcherID   *int // 0 is Dozzle Cloud
+	Type           types.NotificationType
+	Success        *bool
+	Container      string // ID or name of the container
+	Since          time.Time
+	Until          time.Time
+	Limit          int
+}
+
+// Matches returns true if delivery is selected by the filter
+func (f DeliveryFilter) Matches(delivery types.NotificationDelivery) bool {
+	switch {
+	case f.SubscriptionID != 0 && delivery.SubscriptionID != f.SubscriptionID:
+		return false
+	case f.DispatcherID != nil && delivery.DispatcherID != *f.DispatcherID:
+		return false
+	case f.Type != "" && delivery.Type != f.Type:
+		return false
+	case f.Success != nil && delivery.Success != *f.Success:
+		return false
+	case f.Container != "" && delivery.ContainerID != f.Container && delivery.ContainerName != f.Container:
+		return false
+	case !f.Since.IsZero() && delivery.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && delivery.Time.After(f.Until):
+		return false
+	}
+	return true
+}
+
+// FilterDeliveries returns the deliveries selected by filter, newest first and at most
+// filter.Limit of them when it is set.
+func FilterDeliveries(deliveries []types.NotificationDelivery, filter DeliveryFilter) []types.NotificationDelivery {
+	result := make([]types.NotificationDelivery, 0)
+	for _, delivery := range deliveries {
+		if filter.Matches(delivery) {
+			result = append(result, delivery)
+		}
+	}
+	slices.SortStableFunc(result, func(a, b types.NotificationDelivery) int {
+		return b.Time.Compare(a.Time)
+	})
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result
+}
+
+// SetDeliveryLog records every delivery of a notification in l
+func (m *Manager) SetDeliveryLog(l *DeliveryLog) {
+	m.deliveryLog.Store(l)
+}
+
+// Deliveries returns the recorded deliveries of notifications, newest first
+func (m *Manager) Deliveries() []types.NotificationDelivery {
+	l := m.deliveryLog.Load()
+	if l == nil {
+		return []types.NotificationDelivery{}
+	}
+	return l.List()
+}
+
+// recordDelivery adds the outcome of sending notification through d to the delivery log
+func (m *Manager) recordDelivery(d dispatcher.Dispatcher, notification types.Notification, dispatcherID int, latency time.Duration, statusCode int, err error) {
+	l := m.deliveryLog.Load()
+	if l == nil {
+		return
+	}
+
+	name, dispatcherType := dispatcherInfo(d)
+	delivery := types.NotificationDelivery{
+		NotificationID:   notification.ID,
+		Time:             time.Now(),
+		Type:             notification.Type,
+		Status:           notification.Status,
+		SubscriptionID:   notification.Subscription.ID,
+		SubscriptionName: notification.Subscription.Name,
+		DispatcherID:     dispatcherID,
+		DispatcherName:   name,
+		DispatcherType:   dispatcherType,
+		ContainerID:      notification.Container.ID,
+		ContainerName:    notification.Container.Name,
+		HostID:           notification.Container.HostID,
+		HostName:         notification.Container.HostName,
+		Summary:          deliverySummary(notification),
+		Success:          err == nil,
+		StatusCode:       statusCode,
+		LatencyMs:        latency.Milliseconds(),
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	l.Add(delivery)
+}
+
+// deliverySummary is the first line of the notification detail, truncated
+func deliverySummary(notification types.Notification) string {
+	summary, _, _ := strings.Cut(notification.Detail, "\n")
+	return dispatcher.Truncate(summary, maxDeliverySummary)
+}
+
+// dispatcherInfo returns the name and type of d
+func dispatcherInfo(d dispatcher.Dispatcher) (string, string) {
+	switch v := d.(type) {
+	case *dispatcher.WebhookDispatcher:
+		return v.Name, "webhook"
+	case *dispatcher.NativeDispatcher:
+		return v.Name, v.Type
+	case *dispatcher.EmailDispatcher:
+		return v.Name, dispatcher.TypeEmail
+	case *dispatcher.CloudDispatcher:
+		return v.Name, "cloud"
+	}
+	return "", ""
+}
@@ -0,0 +1,145 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/notification/dispatcher"
+	"github.com/amir20/dozzle/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testingDispatcher struct {
+	result dispatcher.TestResult
+}
+
+func (d *testingDispatcher) Send(ctx context.Context, n types.Notification) error {
+	return fmt.Errorf("send should not be called")
+}
+
+func (d *testingDispatcher) Deliver(_ context.Context, _ types.Notification) dispatcher.TestResult {
+	return d.result
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines++
+	}
+	return lines
+}
+
+func TestDeliveryLog_BoundedAndPersisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "deliveries.jsonl")
+	l := NewDeliveryLog(path)
+
+	start := time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC)
+	total := 2*MaxDeliveries + 10
+	for i := range total {
+		l.Add(types.NotificationDelivery{NotificationID: fmt.Sprint(i), Time: start.Add(time.Duration(i) * time.Second)})
+	}
+
+	deliveries := l.List()
+	require.Len(t, deliveries, MaxDeliveries)
+	assert.Equal(t, fmt.Sprint(total-1), deliveries[0].NotificationID, "newest first")
+	assert.Equal(t, fmt.Sprint(total-MaxDeliveries), deliveries[MaxDeliveries-1].NotificationID)
+	assert.LessOrEqual(t, countLines(t, path), 2*MaxDeliveries, "file is compacted")
+
+	reloaded := NewDeliveryLog(path).List()
+	require.Len(t, reloaded, MaxDeliveries)
+	assert.Equal(t, deliveries[0].NotificationID, reloaded[0].NotificationID)
+	assert.True(t, deliveries[0].Time.Equal(reloaded[0].Time))
+}
+
+func TestDeliveryLog_SkipsPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deliveries.jsonl")
+	content := `{"notificationId":"a","success":true}` + "\n" + `{"notificationId":"b","succ`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	deliveries := NewDeliveryLog(path).List()
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, "a", deliveries[0].NotificationID)
+}
+
+func TestFilterDeliveries(t *testing.T) {
+	start := time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC)
+	deliveries := []types.NotificationDelivery{
+		{NotificationID: "1", Time: start, SubscriptionID: 1, DispatcherID: 0, Type: types.LogNotification, Success: true, ContainerName: "api"},
+		{NotificationID: "2", Time: start.Add(time.Minute), SubscriptionID: 2, DispatcherID: 1, Type: types.MetricNotification, Success: false, ContainerID: "abc"},
+		{NotificationID: "3", Time: start.Add(2 * time.Minute), SubscriptionID: 1, DispatcherID: 1, Type: types.LogNotification, Success: false, ContainerName: "web"},
+	}
+	ids := func(result []types.NotificationDelivery) []string {
+		var ids []string
+		for _, d := range result {
+			ids = append(ids, d.NotificationID)
+		}
+		return ids
+	}
+	cloud, failed := 0, false
+
+	assert.Equal(t, []string{"3", "2", "1"}, ids(FilterDeliveries(deliveries, DeliveryFilter{})))
+	assert.Equal(t, []string{"3", "1"}, ids(FilterDeliveries(deliveries, DeliveryFilter{SubscriptionID: 1})))
+	assert.Equal(t, []string{"1"}, ids(FilterDeliveries(deliveries, DeliveryFilter{DispatcherID: &cloud})))
+	assert.Equal(t, []string{"2"}, ids(FilterDeliveries(deliveries, DeliveryFilter{Type: types.MetricNotification})))
+	assert.Equal(t, []string{"3", "2"}, ids(FilterDeliveries(deliveries, DeliveryFilter{Success: &failed})))
+	assert.Equal(t, []string{"2"}, ids(FilterDeliveries(deliveries, DeliveryFilter{Container: "abc"})))
+	assert.Equal(t, []string{"3"}, ids(FilterDeliveries(deliveries, DeliveryFilter{Container: "web"})))
+	assert.Equal(t, []string{"2"}, ids(FilterDeliveries(deliveries, DeliveryFilter{Since: start.Add(time.Second), Until: start.Add(time.Minute)})))
+	assert.Equal(t, []string{"3"}, ids(FilterDeliveries(deliveries, DeliveryFilter{Limit: 1})))
+}
+
+func TestSendNotification_RecordsDelivery(t *testing.T) {
+	m, d := newTestManager(t)
+	m.SetDeliveryLog(NewDeliveryLog(""))
+
+	notification := types.Notification{
+		ID:           "n1",
+		Type:         types.LogNotification,
+		Detail:       "first line\nsecond line",
+		Container:    types.NotificationContainer{ID: "c1", Name: "api", HostID: "h1", HostName: "prod"},
+		Subscription: types.SubscriptionConfig{ID: 3, Name: "Errors"},
+	}
+	m.sendNotification(d, notification, 1)
+	receive(t, d)
+
+	m.sendNotification(&testingDispatcher{result: dispatcher.TestResult{StatusCode: 503, Error: "webhook returned status code 503"}}, notification, 2)
+
+	deliveries := m.Deliveries()
+	require.Len(t, deliveries, 2)
+
+	failed := deliveries[0]
+	assert.False(t, failed.Success)
+	assert.Equal(t, 2, failed.DispatcherID)
+	assert.Equal(t, 503, failed.StatusCode)
+	assert.Equal(t, "webhook returned status code 503", failed.Error)
+
+	sent := deliveries[1]
+	assert.True(t, sent.Success)
+	assert.Equal(t, "n1", sent.NotificationID)
+	assert.Equal(t, 3, sent.SubscriptionID)
+	assert.Equal(t, "Errors", sent.SubscriptionName)
+	assert.Equal(t, 1, sent.DispatcherID)
+	assert.Equal(t, "api", sent.ContainerName)
+	assert.Equal(t, "prod", sent.HostName)
+	assert.Equal(t, "first line", sent.Summary)
+	assert.Empty(t, sent.Error)
+}
+
+func TestDispatcherInfo(t *testing.T) {
+	d, err := dispatcher.NewWebhookDispatcher("Hook", "https://example.com/hook", "", nil)
+	require.NoError(t, err)
+	name, dispatcherType := dispatcherInfo(d)
+	assert.Equal(t, "Hook", name)
+	assert.Equal(t, "webhook", dispatcherType)
+}
@@ -0,0 +1,154 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amir20/dozzle/internal/notification/dispatcher"
+	"github.com/amir20/dozzle/types"
+	"github.com/puzpuzpuz/xsync/v4"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxDigestSamples is the number of log lines a digest notification shows
+	maxDigestSamples = 5
+	// maxDigestSampleLength bounds a single sample so stack traces don't bloat payloads
+	maxDigestSampleLength = 500
+)
+
+// LogDigest collects the log lines that matched a subscription for one group key
+// until its window closes.
+type LogDigest struct {
+	Container types.NotificationContainer
+	Log       types.NotificationLog // first matching log
+	Count     int
+	Samples   []string
+	Since     time.Time
+}
+
+func (d *LogDigest) add(detail string) {
+	d.Count++
+	if len(d.Samples) < maxDigestSamples {
+		d.Samples = append(d.Samples, truncateSample(detail))
+	}
+}
+
+// detail renders the count and samples as the notification detail
+func (d *LogDigest) detail() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d matching log lines in the last %s", d.Count, time.Since(d.Since).Round(time.Second))
+	for _, sample := range d.Samples {
+		sb.WriteString("\n")
+		sb.WriteString(sample)
+	}
+	if more := d.Count - len(d.Samples); more > 0 {
+		fmt.Fprintf(&sb, "\n… and %d more", more)
+	}
+	return sb.String()
+}
+
+func truncateSample(s string) string {
+	return dispatcher.Truncate(s, maxDigestSampleLength)
+}
+
+// handleLogMatch applies the subscription's digest window or cooldown to a matching log
+// and sends a notification when one is due.
+func (m *Manager) handleLogMatch(sub *Subscription, key string, c types.NotificationContainer, l types.NotificationLog, detail string) {
+	if window := sub.GetDigestWindowSeconds(); window > 0 {
+		m.addToLogDigest(sub, key, c, l, detail, time.Duration(window)*time.Second)
+		return
+	}
+
+	if sub.IsLogCooldownActive(key) {
+		return
+	}
+	if sub.GetCooldownSeconds() > 0 {
+		sub.SetLogCooldown(key)
+	}
+
+	m.sendLogNotification(sub, c, l, detail, nil)
+}
+
+// addToLogDigest adds a match to the open digest of key, opening one and scheduling
+// its flush when there is none.
+func (m *Manager) addToLogDigest(sub *Subscription, key string, c types.NotificationContainer, l types.NotificationLog, detail string, window time.Duration) {
+	sub.LogDigests.Compute(key, func(digest *LogDigest, loaded bool) (*LogDigest, xsync.ComputeOp) {
+		if !loaded {
+			digest = &LogDigest{Container: c, Log: l, Since: time.Now()}
+			time.AfterFunc(window, func() { m.flushLogDigest(sub, key) })
+		}
+		digest.add(detail)
+		return digest, xsync.UpdateOp
+	})
+}
+
+// flushLogDigest closes the digest of key and sends it as one notification. The
+// rule is looked up again, so a digest of a rule that was deleted or disabled
+// while it was open is dropped, and one of a replaced rule goes to its current
+// dispatcher.
+func (m *Manager) flushLogDigest(sub *Subscription, key string) {
+	digest, ok := sub.LogDigests.LoadAndDelete(key)
+	if !ok || m.ctx.Err() != nil {
+		return
+	}
+
+	current, ok := m.subscriptions.Load(sub.ID)
+	if !ok || !current.Enabled {
+		log.Debug().Int("subscription", sub.ID).Int("count", digest.Count).Msg("Dropping log digest of removed or disabled subscription")
+		return
+	}
+	sub = current
+
+	// A single match is sent as is
+	if digest.Count == 1 {
+		m.sendLogNotification(sub, digest.Container, digest.Log, digest.Samples[0], nil)
+		return
+	}
+
+	log.Debug().
+		Str("containerID", digest.Container.ID).
+		Int("count", digest.Count).
+		Str("subscription", sub.Name).
+		Msg("Flushing log digest")
+
+	m.sendLogNotification(sub, digest.Container, digest.Log, digest.detail(), &types.NotificationDigest{
+		Count:   digest.Count,
+		Samples: digest.Samples,
+		Since:   digest.Since,
+	})
+}
+
+// sendLogNotification updates the subscription stats and sends a log notification to its dispatcher
+func (m *Manager) sendLogNotification(sub *Subscription, c types.NotificationContainer, l types.NotificationLog, detail string, digest *types.NotificationDigest) {
+	sub.AddTriggeredContainer(c.ID)
+	sub.TriggerCount.Add(1)
+	now := time.Now()
+	sub.LastTriggeredAt.Store(&now)
+
+	notification := types.Notification{
+		ID:        fmt.Sprintf("%s-%d", c.ID, time.Now().UnixNano()),
+		Type:      types.LogNotification,
+		Detail:    detail,
+		Container: c,
+		Log:       &l,
+		Digest:    digest,
+		Subscription: types.SubscriptionConfig{
+			ID:                  sub.ID,
+			Name:                sub.Name,
+			Enabled:             sub.Enabled,
+			DispatcherID:        sub.DispatcherID,
+			LogExpression:       sub.LogExpression,
+			ContainerExpression: sub.ContainerExpression,
+			Cooldown:            sub.Cooldown,
+			GroupBy:             sub.GroupBy,
+			DigestWindow:        sub.DigestWindow,
+		},
+		Timestamp: time.Now(),
+	}
+
+	if d, ok := m.getDispatcher(sub.DispatcherID); ok {
+		go m.sendNotification(d, notification, sub.DispatcherID)
+	}
+}
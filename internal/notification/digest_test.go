@@ -0,0 +1,185 @@
+package notification
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/amir20/dozzle/internal/notification/dispatcher"
+	"github.com/amir20/dozzle/types"
+	"github.com/puzpuzpuz/xsync/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/semaphore"
+)
+
+type recordingDispatcher struct {
+	sent chan types.Notification
+}
+
+func (d *recordingDispatcher) Send(_ context.Context, n types.Notification) error {
+	d.sent <- n
+	return nil
+}
+
+func newTestManager(t *testing.T) (*Manager, *recordingDispatcher) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	d := &recordingDispatcher{sent: make(chan types.Notification, 100)}
+	m := &Manager{
+		subscriptions: xsync.NewMap[int, *Subscription](),
+		dispatchers:   xsync.NewMap[int, dispatcher.Dispatcher](),
+		ctx:           ctx,
+		cancel:        cancel,
+		sendSem:       semaphore.NewWeighted(5),
+	}
+	m.dispatchers.Store(1, d)
+	return m, d
+}
+
+func newLogSubscription(cooldown, digestWindow int) *Subscription {
+	return &Subscription{
+		ID:                    1,
+		Name:                  "Errors",
+		Enabled:               true,
+		DispatcherID:          1,
+		Cooldown:              cooldown,
+		DigestWindow:          digestWindow,
+		LogCooldowns:          xsync.NewMap[string, time.Time](),
+		LogDigests:            xsync.NewMap[string, *LogDigest](),
+		TriggeredContainerIDs: xsync.NewMap[string, struct{}](),
+	}
+}
+
+func receive(t *testing.T, d *recordingDispatcher) types.Notification {
+	t.Helper()
+	select {
+	case n := <-d.sent:
+		return n
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no notification sent")
+		return types.Notification{}
+	}
+}
+
+func assertNothingSent(t *testing.T, d *recordingDispatcher) {
+	t.Helper()
+	select {
+	case n := <-d.sent:
+		assert.Failf(t, "unexpected notification", "%s", n.Detail)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHandleLogMatch_WithoutCooldown(t *testing.T) {
+	m, d := newTestManager(t)
+	sub := newLogSubscription(0, 0)
+	c := types.NotificationContainer{ID: "abc"}
+
+	for range 3 {
+		m.handleLogMatch(sub, "abc", c, types.NotificationLog{}, "panic")
+	}
+	for range 3 {
+		assert.Equal(t, "panic", receive(t, d).Detail)
+	}
+	assert.Equal(t, int64(3), sub.TriggerCount.Load())
+}
+
+func TestHandleLogMatch_Cooldown(t *testing.T) {
+	m, d := newTestManager(t)
+	sub := newLogSubscription(60, 0)
+	c := types.NotificationContainer{ID: "abc"}
+
+	m.handleLogMatch(sub, "abc|a", c, types.NotificationLog{}, "first")
+	m.handleLogMatch(sub, "abc|a", c, types.NotificationLog{}, "suppressed")
+	m.handleLogMatch(sub, "abc|b", c, types.NotificationLog{}, "other group")
+
+	details := []string{receive(t, d).Detail, receive(t, d).Detail}
+	assert.ElementsMatch(t, []string{"first", "other group"}, details)
+	assertNothingSent(t, d)
+	assert.Equal(t, int64(2), sub.TriggerCount.Load())
+}
+
+func TestHandleLogMatch_Digest(t *testing.T) {
+	m, d := newTestManager(t)
+	sub := newLogSubscription(0, 60)
+	m.subscriptions.Store(sub.ID, sub)
+	c := types.NotificationContainer{ID: "abc"}
+
+	for i := range 8 {
+		m.handleLogMatch(sub, "abc", c, types.NotificationLog{ID: uint32(i)}, "error "+strings.Repeat("x", i))
+	}
+	assertNothingSent(t, d)
+
+	m.flushLogDigest(sub, "abc")
+	n := receive(t, d)
+	require.NotNil(t, n.Digest)
+	assert.Equal(t, 8, n.Digest.Count)
+	assert.Len(t, n.Digest.Samples, maxDigestSamples)
+	assert.Equal(t, uint32(0), n.Log.ID)
+	assert.True(t, strings.HasPrefix(n.Detail, "8 matching log lines in the last"))
+	assert.Contains(t, n.Detail, "\nerror xxxx\n… and 3 more")
+	assert.Equal(t, int64(1), sub.TriggerCount.Load())
+
+	m.flushLogDigest(sub, "abc")
+	assertNothingSent(t, d)
+}
+
+func TestHandleLogMatch_DigestOfOne(t *testing.T) {
+	m, d := newTestManager(t)
+	sub := newLogSubscription(0, 60)
+	m.subscriptions.Store(sub.ID, sub)
+
+	m.handleLogMatch(sub, "abc", types.NotificationContainer{ID: "abc"}, types.NotificationLog{}, "only once")
+	m.flushLogDigest(sub, "abc")
+
+	n := receive(t, d)
+	assert.Nil(t, n.Digest)
+	assert.Equal(t, "only once", n.Detail)
+}
+
+func TestFlushLogDigest_UsesCurrentSubscription(t *testing.T) {
+	m, d := newTestManager(t)
+	other := &recordingDispatcher{sent: make(chan types.Notification, 100)}
+	m.dispatchers.Store(2, other)
+	c := types.NotificationContainer{ID: "abc"}
+
+	// Removed while the digest was open
+	sub := newLogSubscription(0, 60)
+	m.handleLogMatch(sub, "abc", c, types.NotificationLog{}, "error")
+	m.flushLogDigest(sub, "abc")
+	assertNothingSent(t, d)
+
+	// Disabled while the digest was open
+	sub = newLogSubscription(0, 60)
+	m.subscriptions.Store(sub.ID, sub)
+	m.handleLogMatch(sub, "abc", c, types.NotificationLog{}, "error")
+	disabled := newLogSubscription(0, 60)
+	disabled.Enabled = false
+	m.subscriptions.Store(sub.ID, disabled)
+	m.flushLogDigest(sub, "abc")
+	assertNothingSent(t, d)
+
+	// Replaced with a rule that sends to another dispatcher
+	sub = newLogSubscription(0, 60)
+	m.subscriptions.Store(sub.ID, sub)
+	m.handleLogMatch(sub, "abc", c, types.NotificationLog{}, "error")
+	replaced := newLogSubscription(0, 60)
+	replaced.DispatcherID = 2
+	m.subscriptions.Store(sub.ID, replaced)
+	m.flushLogDigest(sub, "abc")
+	assertNothingSent(t, d)
+	n := receive(t, other)
+	assert.Equal(t, 2, n.Subscription.DispatcherID)
+}
+
+func TestTruncateSample(t *testing.T) {
+	assert.Equal(t, "short", truncateSample("short"))
+	long := strings.Repeat("é", maxDigestSampleLength+1)
+	truncated := truncateSample(long)
+	assert.Equal(t, maxDigestSampleLength, utf8.RuneCountInString(truncated))
+	assert.True(t, strings.HasSuffix(truncated, "é…"))
+}
@@ -11,3 +11,24 @@ type Dispatcher interface {
 	// Send sends a notification to the configured destination
 	Send(ctx context.Context, notification types.Notification) error
 }
+
+// Deliverer is implemented by dispatchers that report the status code of a delivery
+type Deliverer interface {
+	// Deliver sends a notification and returns the detailed result
+	Deliver(ctx context.Context, notification types.Notification) TestResult
+}
+
+// Tester is implemented by dispatchers that can send a test notification
+type Tester interface {
+	// SendTest sends a test notification and returns the detailed result
+	SendTest(ctx context.Context, notification types.Notification) TestResult
+}
+
+// Truncate cuts s to at most n runes, ending with an ellipsis when it was cut
+func Truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,339 @@
+package dispatcher
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/amir20/dozzle/types"
+	"github.com/rs/zerolog/log"
+)
+
+// TypeEmail is the dispatcher type of EmailDispatcher.
+const TypeEmail = "email"
+
+// SMTP connection security of an email dispatcher
+const (
+	SecurityStartTLS = "starttls" // plain connection upgraded with STARTTLS, usually port 587
+	SecurityTLS      = "tls"      // implicit TLS, usually port 465
+	SecurityNone     = "none"     // no encryption, usually port 25
+)
+
+var defaultSMTPPorts = map[string]int{SecurityStartTLS: 587, SecurityTLS: 465, SecurityNone: 25}
+
+// smtpTimeout bounds a whole SMTP session.
+const smtpTimeout = 30 * time.Second
+
+const defaultEmailSubject = `[Dozzle] {{ if eq .Status "resolved" }}Resolved: {{ end }}{{ with .Subscription.Name }}{{ . }}: {{ end }}{{ .Container.Name }} on {{ .Container.HostName }}`
+
+const defaultEmailBody = `<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f4f4f5;font-family:-apple-system,'Segoe UI',Roboto,Helvetica,Arial,sans-serif;color:#18181b">
+<table role="presentation" width="100%" style="max-width:640px;margin:0 auto;background:#ffffff;border-radius:8px">
+<tr><td style="padding:24px">
+<h1 style="margin:0 0 16px;font-size:20px">{{ .Title }}</h1>
+<table role="presentation" style="border-collapse:collapse;font-size:14px">
+{{- range .Fields }}
+<tr><td style="padding:4px 16px 4px 0;color:#71717a">{{ .Name }}</td><td style="padding:4px 0">{{ .Value }}</td></tr>
+{{- end }}
+</table>
+{{- with .Excerpt }}
+<pre style="margin:16px 0 0;padding:12px;background:#18181b;color:#f4f4f5;border-radius:6px;font-size:13px;white-space:pre-wrap;word-break:break-word">{{ . }}</pre>
+{{- end }}
+{{- with .Link }}
+<p style="margin:24px 0 0"><a href="{{ . }}" style="display:inline-block;padding:8px 16px;background:#2563eb;color:#ffffff;border-radius:6px;text-decoration:none">Open in Dozzle</a></p>
+{{- end }}
+</td></tr>
+</table>
+</body>
+</html>
+`
+
+// EmailSettings configures an email dispatcher
+type EmailSettings struct {
+	Host      string
+	Port      int    // defaults to the usual port of Security
+	Security  string // starttls (default), tls or none
+	Username  string // no authentication when empty
+	Password  string
+	From      string
+	To        []string
+	Subject   string // text/template rendered from the notification
+	Body      string // html/template rendered from the notification
+	DozzleURL string // external URL of Dozzle, used to link back to the container
+}
+
+// EmailDispatcher sends notifications as HTML emails over SMTP
+type EmailDispatcher struct {
+	Name     string
+	Settings EmailSettings
+	from     *mail.Address
+	to       []*mail.Address
+	subject  *template.Template
+	body     *htmltemplate.Template
+	dial     func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// emailData is what subject and body templates are rendered from: the
+// notification plus the summary the default body shows.
+type emailData struct {
+	types.Notification
+	Title   string
+	Fields  []field
+	Excerpt string
+	Link    string
+}
+
+// NewEmailDispatcher creates an email dispatcher and validates its settings.
+// An empty subject or body uses the default template.
+func NewEmailDispatcher(name string, settings EmailSettings) (*EmailDispatcher, error) {
+	if settings.Host == "" {
+		return nil, errors.New("email requires an SMTP host")
+	}
+	if settings.Security == "" {
+		settings.Security = SecurityStartTLS
+	}
+	if _, ok := defaultSMTPPorts[settings.Security]; !ok {
+		return nil, fmt.Errorf("invalid SMTP security %q: must be starttls, tls or none", settings.Security)
+	}
+	if settings.Port < 0 || settings.Port > 65535 {
+		return nil, errors.New("invalid SMTP port")
+	}
+	if settings.DozzleURL != "" {
+		if err := validateHTTPURL(settings.DozzleURL); err != nil {
+			return nil, fmt.Errorf("invalid Dozzle URL: %w", err)
+		}
+	}
+
+	from, err := mail.ParseAddress(settings.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	if len(settings.To) == 0 {
+		return nil, errors.New("email requires at least one recipient")
+	}
+	to := make([]*mail.Address, 0, len(settings.To))
+	for _, recipient := range settings.To {
+		addr, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", recipient, err)
+		}
+		to = append(to, addr)
+	}
+
+	subject, err := template.New("subject").Parse(cmp.Or(settings.Subject, defaultEmailSubject))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse subject template: %w", err)
+	}
+	body, err := htmltemplate.New("body").Parse(cmp.Or(settings.Body, defaultEmailBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse body template: %w", err)
+	}
+
+	return &EmailDispatcher{
+		Name:     name,
+		Settings: settings,
+		from:     from,
+		to:       to,
+		subject:  subject,
+		body:     body,
+		dial:     safeDialContext,
+	}, nil
+}
+
+// Send sends a notification by email
+func (d *EmailDispatcher) Send(ctx context.Context, notification types.Notification) error {
+	result := d.Deliver(ctx, notification)
+	if !result.Success {
+		return fmt.Errorf("email notification failed: %s", result.Error)
+	}
+	return nil
+}
+
+// SendTest sends a test notification and returns the detailed result
+func (d *EmailDispatcher) SendTest(ctx context.Context, notification types.Notification) TestResult {
+	return d.Deliver(ctx, notification)
+}
+
+// Deliver sends a notification and returns the detailed result. StatusCode is
+// the SMTP reply code.
+func (d *EmailDispatcher) Deliver(ctx context.Context, notification types.Notification) TestResult {
+	message, err := d.buildMessage(notification)
+	if err != nil {
+		return TestResult{Success: false, Error: err.Error()}
+	}
+
+	if err := d.deliver(ctx, message); err != nil {
+		if errors.Is(err, errBlockedAddress) {
+			return TestResult{Success: false, Error: errBlockedAddress.Error()}
+		}
+		// Like webhook response bodies, server replies are only logged for the operator.
+		var protoErr *textproto.Error
+		if errors.As(err, &protoErr) {
+			log.Debug().
+				Str("dispatcher", d.Name).
+				Int("status_code", protoErr.Code).
+				Str("reply", protoErr.Msg).
+				Msg("smtp server rejected email")
+			return TestResult{
+				Success:    false,
+				StatusCode: protoErr.Code,
+				Error:      fmt.Sprintf("smtp server returned status code %d", protoErr.Code),
+			}
+		}
+		return TestResult{Success: false, Error: fmt.Sprintf("failed to send email: %v", err)}
+	}
+
+	return TestResult{Success: true, StatusCode: 250}
+}
+
+// buildMessage renders the notification as a multipart email with a plain
+// text and an HTML part.
+func (d *EmailDispatcher) buildMessage(n types.Notification) ([]byte, error) {
+	summary := newMessage(n, d.Settings.DozzleURL)
+	data := emailData{
+		Notification: n,
+		Title:        summary.Title,
+		Fields:       summary.Fields,
+		Excerpt:      summary.Excerpt,
+		Link:         summary.Link,
+	}
+
+	var subject bytes.Buffer
+	if err := d.subject.Execute(&subject, data); err != nil {
+		return nil, fmt.Errorf("failed to execute subject template: %w", err)
+	}
+	var html bytes.Buffer
+	if err := d.body.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("failed to execute body template: %w", err)
+	}
+	text := summary.Title + "\n\n" + summary.text()
+	if summary.Link != "" {
+		text += "\n\n" + summary.Link
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", html.String()},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	recipients := make([]string, len(d.to))
+	for i, addr := range d.to {
+		recipients[i] = addr.String()
+	}
+	// A rendered subject must not be able to add headers.
+	subjectLine := strings.Join(strings.Fields(subject.String()), " ")
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", d.from.String())
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subjectLine))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "Message-ID: <%s@dozzle>\r\n", rand.Text())
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
+
+// deliver sends message to all recipients in one SMTP session.
+func (d *EmailDispatcher) deliver(ctx context.Context, message []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	s := d.Settings
+	port := s.Port
+	if port == 0 {
+		port = defaultSMTPPorts[s.Security]
+	}
+	conn, err := d.dial(ctx, "tcp", net.JoinHostPort(s.Host, strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	tlsConfig := &tls.Config{ServerName: s.Host, MinVersion: tls.VersionTLS12}
+	if s.Security == SecurityTLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if s.Security == SecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(d.from.Address); err != nil {
+		return err
+	}
+	for _, addr := range d.to {
+		if err := client.Rcpt(addr.Address); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
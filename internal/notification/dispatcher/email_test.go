@@ -0,0 +1,229 @@
+package dispatcher
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpSession is what the SMTP stand-in received in one session.
+type smtpSession struct {
+	Auth string
+	From string
+	To   []string
+	Data string
+}
+
+// newSMTPServer is a minimal local SMTP stand-in. rcptReply is the reply to
+// RCPT TO, so tests can simulate a rejected recipient.
+func newSMTPServer(t *testing.T, rcptReply string) (string, <-chan smtpSession) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		session := smtpSession{}
+		reply := func(line string) { _ = text.PrintfLine("%s", line) }
+		reply("220 localhost ESMTP stand-in")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch command {
+			case "EHLO":
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case "AUTH":
+				session.Auth = strings.TrimPrefix(line, "AUTH PLAIN ")
+				reply("235 2.7.0 Authentication successful")
+			case "MAIL":
+				session.From = line
+				reply("250 OK")
+			case "RCPT":
+				session.To = append(session.To, line)
+				reply(rcptReply)
+			case "DATA":
+				reply("354 Go ahead")
+				data, err := text.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				session.Data = string(data)
+				reply("250 OK")
+			case "QUIT":
+				reply("221 Bye")
+				sessions <- session
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().String(), sessions
+}
+
+// newLocalEmailDispatcher allows the dispatcher to reach the local stand-in.
+func newLocalEmailDispatcher(t *testing.T, addr string, settings EmailSettings) *EmailDispatcher {
+	t.Helper()
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	settings.Host = host
+	settings.Port, _ = net.LookupPort("tcp", port)
+	settings.Security = SecurityNone
+	d, err := NewEmailDispatcher("test", settings)
+	require.NoError(t, err)
+	d.dial = (&net.Dialer{}).DialContext
+	return d
+}
+
+// htmlPart returns the decoded HTML part of a multipart email.
+func htmlPart(t *testing.T, data string) (*mail.Message, string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	require.NoError(t, err)
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		require.NoError(t, err)
+		if strings.HasPrefix(part.Header.Get("Content-Type"), "text/html") {
+			body, err := io.ReadAll(part)
+			require.NoError(t, err)
+			return msg, string(body)
+		}
+	}
+}
+
+func TestNewEmailDispatcher_Validation(t *testing.T) {
+	valid := EmailSettings{Host: "smtp.example.com", From: "dozzle@example.com", To: []string{"oncall@example.com"}}
+
+	tests := []struct {
+		name    string
+		modify  func(*EmailSettings)
+		wantErr string
+	}{
+		{"missing host", func(s *EmailSettings) { s.Host = "" }, "requires an SMTP host"},
+		{"bad security", func(s *EmailSettings) { s.Security = "ssl" }, "invalid SMTP security"},
+		{"bad port", func(s *EmailSettings) { s.Port = 70000 }, "invalid SMTP port"},
+		{"bad from", func(s *EmailSettings) { s.From = "dozzle" }, "invalid from address"},
+		{"no recipients", func(s *EmailSettings) { s.To = nil }, "at least one recipient"},
+		{"bad recipient", func(s *EmailSettings) { s.To = []string{"oncall"} }, "invalid recipient"},
+		{"bad subject", func(s *EmailSettings) { s.Subject = "{{ .Container.Name" }, "failed to parse subject template"},
+		{"bad body", func(s *EmailSettings) { s.Body = "{{ if }}" }, "failed to parse body template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := valid
+			tt.modify(&settings)
+			_, err := NewEmailDispatcher("test", settings)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	d, err := NewEmailDispatcher("test", valid)
+	require.NoError(t, err)
+	assert.Equal(t, SecurityStartTLS, d.Settings.Security)
+}
+
+func TestEmailDispatcher_SendsDefaultTemplate(t *testing.T) {
+	addr, sessions := newSMTPServer(t, "250 OK")
+	d := newLocalEmailDispatcher(t, addr, EmailSettings{
+		Username:  "dozzle",
+		Password:  "secret",
+		From:      "Dozzle <dozzle@example.com>",
+		To:        []string{"oncall@example.com", "Ops <ops@example.com>"},
+		DozzleURL: "https://dozzle.example.com",
+	})
+
+	n := newNativeTestNotification()
+	n.Detail = `panic: <nil> map access`
+	result := d.SendTest(context.Background(), n)
+	require.True(t, result.Success, result.Error)
+	assert.Equal(t, 250, result.StatusCode)
+
+	session := <-sessions
+	credentials, err := base64.StdEncoding.DecodeString(session.Auth)
+	require.NoError(t, err)
+	assert.Equal(t, "\x00dozzle\x00secret", string(credentials))
+	assert.Equal(t, "MAIL FROM:<dozzle@example.com>", session.From)
+	assert.Equal(t, []string{"RCPT TO:<oncall@example.com>", "RCPT TO:<ops@example.com>"}, session.To)
+
+	msg, html := htmlPart(t, session.Data)
+	assert.Equal(t, "[Dozzle] Crash alert: my-container on docker-host", msg.Header.Get("Subject"))
+	assert.Equal(t, `"Ops" <ops@example.com>`, strings.Split(msg.Header.Get("To"), ", ")[1])
+	assert.Contains(t, html, "panic: &lt;nil&gt; map access")
+	assert.Contains(t, html, "docker-host")
+	assert.Contains(t, html, `href="https://dozzle.example.com/container/abc123/time/2026-05-01T12:00:00Z?logId=42"`)
+}
+
+func TestEmailDispatcher_CustomTemplates(t *testing.T) {
+	addr, sessions := newSMTPServer(t, "250 OK")
+	d := newLocalEmailDispatcher(t, addr, EmailSettings{
+		From:    "dozzle@example.com",
+		To:      []string{"oncall@example.com"},
+		Subject: "{{ .Log.Level }} in {{ .Container.Name }}\r\nBcc: attacker@example.com",
+		Body:    "<p>{{ .Detail }}</p>",
+	})
+
+	n := newNativeTestNotification()
+	n.Detail = "<b>boom</b>"
+	result := d.SendTest(context.Background(), n)
+	require.True(t, result.Success, result.Error)
+
+	msg, html := htmlPart(t, (<-sessions).Data)
+	assert.Equal(t, "error in my-container Bcc: attacker@example.com", msg.Header.Get("Subject"))
+	assert.Empty(t, msg.Header.Get("Bcc"))
+	assert.Equal(t, "<p>&lt;b&gt;boom&lt;/b&gt;</p>", html)
+}
+
+func TestEmailDispatcher_RejectedRecipient(t *testing.T) {
+	addr, _ := newSMTPServer(t, "550 5.1.1 No such user")
+	d := newLocalEmailDispatcher(t, addr, EmailSettings{From: "dozzle@example.com", To: []string{"nobody@example.com"}})
+
+	result := d.SendTest(context.Background(), newNativeTestNotification())
+	assert.False(t, result.Success)
+	assert.Equal(t, 550, result.StatusCode)
+	assert.Equal(t, "smtp server returned status code 550", result.Error)
+}
+
+func TestEmailDispatcher_BlocksLoopback(t *testing.T) {
+	addr, _ := newSMTPServer(t, "250 OK")
+	host, port, _ := net.SplitHostPort(addr)
+	portNumber, _ := net.LookupPort("tcp", port)
+	d, err := NewEmailDispatcher("test", EmailSettings{
+		Host:     host,
+		Port:     portNumber,
+		Security: SecurityNone,
+		From:     "dozzle@example.com",
+		To:       []string{"oncall@example.com"},
+	})
+	require.NoError(t, err)
+
+	result := d.SendTest(context.Background(), newNativeTestNotification())
+	assert.False(t, result.Success)
+	assert.Equal(t, errBlockedAddress.Error(), result.Error)
+}
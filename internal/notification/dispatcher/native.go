@@ -0,0 +1,558 @@
+package dispatcher
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amir20/dozzle/types"
+	"github.com/rs/zerolog/log"
+)
+
+// Native dispatcher types. Each one formats notifications for its service, so
+// no template is needed.
+const (
+	TypeSlack    = "slack"
+	TypeDiscord  = "discord"
+	TypeTeams    = "teams"
+	TypeTelegram = "telegram"
+	TypeNtfy     = "ntfy"
+	TypeGotify   = "gotify"
+	TypePushover = "pushover"
+)
+
+// NativeTypes lists the native dispatcher types.
+var NativeTypes = []string{TypeSlack, TypeDiscord, TypeTeams, TypeTelegram, TypeNtfy, TypeGotify, TypePushover}
+
+// IsNativeType reports whether kind is a native dispatcher type.
+func IsNativeType(kind string) bool {
+	return slices.Contains(NativeTypes, kind)
+}
+
+const (
+	defaultTelegramURL = "https://api.telegram.org"
+	defaultNtfyURL     = "https://ntfy.sh"
+	defaultPushoverURL = "https://api.pushover.net"
+
+	// maxExcerpt is how much of the log line or detail a message carries.
+	maxExcerpt = 1000
+	// maxPushoverMessage is the longest message Pushover accepts.
+	maxPushoverMessage = 1024
+)
+
+var ntfyPriorities = map[string]int{"min": 1, "low": 2, "default": 3, "high": 4, "max": 5, "urgent": 5}
+
+// NativeSettings configures a native dispatcher. Which settings are used
+// depends on the type.
+type NativeSettings struct {
+	URL       string // webhook URL (Slack, Discord, Teams), server (ntfy, Gotify) or API base (Telegram, Pushover)
+	Channel   string // Slack channel override or Telegram chat ID
+	Topic     string // ntfy topic
+	Priority  string // ntfy (1-5 or min to urgent), Gotify (0-10) or Pushover (-2 to 2)
+	Token     string // Telegram bot token, Gotify app token, Pushover app token or ntfy access token
+	User      string // Pushover user or group key
+	DozzleURL string // external URL of Dozzle, used to link back to the container
+}
+
+// NativeDispatcher sends rich messages to a chat or push service
+type NativeDispatcher struct {
+	Name     string
+	Type     string
+	Settings NativeSettings
+	client   *http.Client
+}
+
+// NewNativeDispatcher creates a dispatcher of one of the NativeTypes and
+// validates the settings that type needs.
+func NewNativeDispatcher(name, kind string, settings NativeSettings) (*NativeDispatcher, error) {
+	if err := validateNativeSettings(kind, settings); err != nil {
+		return nil, err
+	}
+	return &NativeDispatcher{
+		Name:     name,
+		Type:     kind,
+		Settings: settings,
+		client:   newSafeClient(),
+	}, nil
+}
+
+func validateNativeSettings(kind string, s NativeSettings) error {
+	if !IsNativeType(kind) {
+		return fmt.Errorf("unknown dispatcher type: %s", kind)
+	}
+	if s.URL != "" {
+		if err := validateHTTPURL(s.URL); err != nil {
+			return fmt.Errorf("invalid %s URL: %w", kind, err)
+		}
+	}
+	if s.DozzleURL != "" {
+		if err := validateHTTPURL(s.DozzleURL); err != nil {
+			return fmt.Errorf("invalid Dozzle URL: %w", err)
+		}
+	}
+
+	switch kind {
+	case TypeSlack, TypeDiscord, TypeTeams:
+		if s.URL == "" {
+			return fmt.Errorf("%s requires a webhook URL", kind)
+		}
+	case TypeTelegram:
+		if s.Token == "" || s.Channel == "" {
+			return errors.New("telegram requires a bot token and a chat ID as channel")
+		}
+	case TypeNtfy:
+		if s.Topic == "" {
+			return errors.New("ntfy requires a topic")
+		}
+		if _, err := ntfyPriority(s.Priority); err != nil {
+			return err
+		}
+	case TypeGotify:
+		if s.URL == "" || s.Token == "" {
+			return errors.New("gotify requires a server URL and an application token")
+		}
+		if _, err := rangedPriority(s.Priority, 0, 10); err != nil {
+			return err
+		}
+	case TypePushover:
+		if s.Token == "" || s.User == "" {
+			return errors.New("pushover requires an application token and a user key")
+		}
+		if _, err := rangedPriority(s.Priority, -2, 2); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateHTTPURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if scheme := strings.ToLower(parsed.Scheme); scheme != "http" && scheme != "https" {
+		return fmt.Errorf("scheme %q is not allowed, only http and https are", parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return errors.New("missing host")
+	}
+	return nil
+}
+
+// ntfyPriority parses a ntfy priority, which is 1-5 or its name. Empty is
+// the server default.
+func ntfyPriority(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if p, ok := ntfyPriorities[strings.ToLower(value)]; ok {
+		return p, nil
+	}
+	return rangedPriority(value, 1, 5)
+}
+
+// rangedPriority parses an integer priority between low and high. Empty is
+// the server default and parses as 0.
+func rangedPriority(value string, low, high int) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	p, err := strconv.Atoi(value)
+	if err != nil || p < low || p > high {
+		return 0, fmt.Errorf("priority must be between %d and %d", low, high)
+	}
+	return p, nil
+}
+
+// Send sends a notification to the service
+func (d *NativeDispatcher) Send(ctx context.Context, notification types.Notification) error {
+	result := d.Deliver(ctx, notification)
+	if !result.Success {
+		return fmt.Errorf("%s notification failed: %s", d.Type, result.Error)
+	}
+	return nil
+}
+
+// SendTest sends a test notification and returns the detailed result
+func (d *NativeDispatcher) SendTest(ctx context.Context, notification types.Notification) TestResult {
+	return d.Deliver(ctx, notification)
+}
+
+// Deliver sends a notification and returns the detailed result
+func (d *NativeDispatcher) Deliver(ctx context.Context, notification types.Notification) TestResult {
+	req, err := d.newRequest(ctx, newMessage(notification, d.Settings.DozzleURL))
+	if err != nil {
+		return TestResult{Success: false, Error: fmt.Sprintf("failed to create request: %v", err)}
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		if errors.Is(err, errBlockedAddress) {
+			return TestResult{Success: false, Error: errBlockedAddress.Error()}
+		}
+		// The URL can carry a token (Telegram), so only the cause is reported.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return TestResult{Success: false, Error: fmt.Sprintf("failed to send %s notification: %v", d.Type, err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Only logged for the operator, never returned through the API.
+		responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+		log.Debug().
+			Str("dispatcher", d.Name).
+			Str("type", d.Type).
+			Int("status_code", resp.StatusCode).
+			Str("response_body", string(responseBody)).
+			Msg("dispatcher returned non-success status code")
+		return TestResult{
+			Success:    false,
+			StatusCode: resp.StatusCode,
+			Error:      fmt.Sprintf("%s returned status code %d", d.Type, resp.StatusCode),
+		}
+	}
+
+	return TestResult{Success: true, StatusCode: resp.StatusCode}
+}
+
+// newRequest builds the request that delivers msg to the service.
+func (d *NativeDispatcher) newRequest(ctx context.Context, msg message) (*http.Request, error) {
+	s := d.Settings
+	endpoint := s.URL
+	headers := map[string]string{}
+	var payload map[string]any
+
+	switch d.Type {
+	case TypeSlack:
+		payload = slackPayload(msg, s.Channel)
+	case TypeDiscord:
+		payload = discordPayload(msg)
+	case TypeTeams:
+		payload = teamsPayload(msg)
+	case TypeTelegram:
+		endpoint = strings.TrimSuffix(cmp.Or(s.URL, defaultTelegramURL), "/") + "/bot" + s.Token + "/sendMessage"
+		payload = telegramPayload(msg, s.Channel)
+	case TypeNtfy:
+		endpoint = cmp.Or(s.URL, defaultNtfyURL)
+		if s.Token != "" {
+			headers["Authorization"] = "Bearer " + s.Token
+		}
+		priority, _ := ntfyPriority(s.Priority)
+		payload = ntfyPayload(msg, s.Topic, priority)
+	case TypeGotify:
+		endpoint = strings.TrimSuffix(s.URL, "/") + "/message"
+		headers["X-Gotify-Key"] = s.Token
+		payload = gotifyPayload(msg, s.Priority)
+	case TypePushover:
+		endpoint = strings.TrimSuffix(cmp.Or(s.URL, defaultPushoverURL), "/") + "/1/messages.json"
+		payload = pushoverPayload(msg, s.Token, s.User, s.Priority)
+	default:
+		return nil, fmt.Errorf("unknown dispatcher type: %s", d.Type)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		// The error quotes the URL, which can carry a token.
+		return nil, errors.New("invalid endpoint")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
+
+// severity of a message, used for colors and tags
+type severity int
+
+const (
+	severityInfo severity = iota
+	severityWarning
+	severityError
+	severityResolved
+)
+
+type field struct {
+	Name  string
+	Value string
+}
+
+// message is the service-independent content every native format is
+// rendered from.
+type message struct {
+	Title     string
+	Fields    []field
+	Excerpt   string
+	Link      string
+	Severity  severity
+	Timestamp time.Time
+}
+
+// newMessage collects what a notification is about: the container, host,
+// level or metric values, an excerpt and a link to the container in Dozzle
+// when its URL is known.
+func newMessage(n types.Notification, dozzleURL string) message {
+	msg := message{
+		Title:     cmp.Or(n.Subscription.Name, "Dozzle alert"),
+		Excerpt:   Truncate(n.Detail, maxExcerpt),
+		Timestamp: n.Timestamp,
+		Fields: []field{
+			{"Container", n.Container.Name},
+			{"Host", cmp.Or(n.Container.HostName, n.Container.HostID)},
+		},
+	}
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+	if n.Container.Image != "" {
+		msg.Fields = append(msg.Fields, field{"Image", n.Container.Image})
+	}
+
+	switch {
+	case n.Log != nil:
+		if n.Digest != nil {
+			msg.Fields = append(msg.Fields, field{"Matches", strconv.Itoa(n.Digest.Count)})
+		}
+		if n.Log.Level != "" {
+			msg.Fields = append(msg.Fields, field{"Level", n.Log.Level})
+		}
+		if n.Log.Stream != "" {
+			msg.Fields = append(msg.Fields, field{"Stream", n.Log.Stream})
+		}
+		switch strings.ToLower(n.Log.Level) {
+		case "fatal", "panic", "crit", "critical", "alert", "emerg", "error", "err":
+			msg.Severity = severityError
+		case "warn", "warning":
+			msg.Severity = severityWarning
+		}
+	case n.Stat != nil:
+		msg.Fields = append(msg.Fields,
+			field{"CPU", fmt.Sprintf("%.1f%%", n.Stat.CPUPercent)},
+			field{"Memory", fmt.Sprintf("%.1f%%", n.Stat.MemoryPercent)},
+		)
+		msg.Severity = severityWarning
+	case n.Event != nil:
+		msg.Fields = append(msg.Fields, field{"Event", n.Event.Name})
+		msg.Severity = severityWarning
+	}
+
+	if n.Status == types.StatusResolved {
+		msg.Title = "Resolved: " + msg.Title
+		msg.Severity = severityResolved
+	}
+
+	if dozzleURL != "" && n.Container.ID != "" {
+		link := strings.TrimSuffix(dozzleURL, "/") + "/container/" + url.PathEscape(n.Container.ID)
+		if n.Log != nil && n.Log.Timestamp > 0 {
+			link += "/time/" + time.UnixMilli(n.Log.Timestamp).UTC().Format(time.RFC3339) + "?logId=" + strconv.FormatUint(uint64(n.Log.ID), 10)
+		}
+		msg.Link = link
+	}
+	return msg
+}
+
+// text renders the fields and excerpt as plain text.
+func (m message) text() string {
+	var sb strings.Builder
+	for _, f := range m.Fields {
+		fmt.Fprintf(&sb, "%s: %s\n", f.Name, f.Value)
+	}
+	if m.Excerpt != "" {
+		sb.WriteString("\n")
+		sb.WriteString(m.Excerpt)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func slackPayload(m message, channel string) map[string]any {
+	fields := make([]map[string]any, 0, len(m.Fields))
+	for _, f := range m.Fields {
+		fields = append(fields, map[string]any{"type": "mrkdwn", "text": "*" + f.Name + "*\n" + slackEscaper.Replace(f.Value)})
+	}
+	blocks := []map[string]any{
+		{"type": "header", "text": map[string]any{"type": "plain_text", "text": Truncate(m.Title, 150)}},
+		{"type": "section", "fields": fields[:min(len(fields), 10)]},
+	}
+	if m.Excerpt != "" {
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": "```" + slackEscaper.Replace(m.Excerpt) + "```"},
+		})
+	}
+	if m.Link != "" {
+		blocks = append(blocks, map[string]any{
+			"type": "actions",
+			"elements": []map[string]any{{
+				"type": "button",
+				"text": map[string]any{"type": "plain_text", "text": "Open in Dozzle"},
+				"url":  m.Link,
+			}},
+		})
+	}
+
+	payload := map[string]any{
+		"text":   m.Title + ": " + m.Fields[0].Value,
+		"blocks": blocks,
+	}
+	if channel != "" {
+		payload["channel"] = channel
+	}
+	return payload
+}
+
+func discordPayload(m message) map[string]any {
+	colors := map[severity]int{severityInfo: 0x3b82f6, severityWarning: 0xf59e0b, severityError: 0xef4444, severityResolved: 0x22c55e}
+	fields := make([]map[string]any, 0, len(m.Fields))
+	for _, f := range m.Fields {
+		fields = append(fields, map[string]any{"name": f.Name, "value": cmp.Or(f.Value, "-"), "inline": true})
+	}
+	embed := map[string]any{
+		"title":     Truncate(m.Title, 256),
+		"color":     colors[m.Severity],
+		"fields":    fields,
+		"timestamp": m.Timestamp.UTC().Format(time.RFC3339),
+	}
+	if m.Excerpt != "" {
+		embed["description"] = "```\n" + m.Excerpt + "\n```"
+	}
+	if m.Link != "" {
+		embed["url"] = m.Link
+	}
+	return map[string]any{"embeds": []map[string]any{embed}}
+}
+
+func teamsPayload(m message) map[string]any {
+	colors := map[severity]string{severityInfo: "Default", severityWarning: "Warning", severityError: "Attention", severityResolved: "Good"}
+	facts := make([]map[string]any, 0, len(m.Fields))
+	for _, f := range m.Fields {
+		facts = append(facts, map[string]any{"title": f.Name, "value": f.Value})
+	}
+	body := []map[string]any{
+		{"type": "TextBlock", "text": m.Title, "weight": "Bolder", "size": "Medium", "color": colors[m.Severity], "wrap": true},
+		{"type": "FactSet", "facts": facts},
+	}
+	if m.Excerpt != "" {
+		body = append(body, map[string]any{"type": "TextBlock", "text": m.Excerpt, "fontType": "Monospace", "wrap": true})
+	}
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if m.Link != "" {
+		card["actions"] = []map[string]any{{"type": "Action.OpenUrl", "title": "Open in Dozzle", "url": m.Link}}
+	}
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	}
+}
+
+func telegramPayload(m message, chatID string) map[string]any {
+	var sb strings.Builder
+	sb.WriteString("<b>" + html.EscapeString(m.Title) + "</b>\n")
+	for _, f := range m.Fields {
+		sb.WriteString("<b>" + html.EscapeString(f.Name) + ":</b> " + html.EscapeString(f.Value) + "\n")
+	}
+	if m.Excerpt != "" {
+		sb.WriteString("<pre>" + html.EscapeString(m.Excerpt) + "</pre>\n")
+	}
+	if m.Link != "" {
+		sb.WriteString(`<a href="` + html.EscapeString(m.Link) + `">Open in Dozzle</a>`)
+	}
+	return map[string]any{
+		"chat_id":              chatID,
+		"text":                 strings.TrimRight(sb.String(), "\n"),
+		"parse_mode":           "HTML",
+		"link_preview_options": map[string]any{"is_disabled": true},
+	}
+}
+
+func ntfyPayload(m message, topic string, priority int) map[string]any {
+	payload := map[string]any{
+		"topic":   topic,
+		"title":   m.Title,
+		"message": m.text(),
+	}
+	if priority > 0 {
+		payload["priority"] = priority
+	}
+	switch m.Severity {
+	case severityError:
+		payload["tags"] = []string{"rotating_light"}
+	case severityWarning:
+		payload["tags"] = []string{"warning"}
+	case severityResolved:
+		payload["tags"] = []string{"white_check_mark"}
+	}
+	if m.Link != "" {
+		payload["click"] = m.Link
+	}
+	return payload
+}
+
+func gotifyPayload(m message, priority string) map[string]any {
+	payload := map[string]any{
+		"title":   m.Title,
+		"message": m.text(),
+	}
+	if priority != "" {
+		p, _ := rangedPriority(priority, 0, 10)
+		payload["priority"] = p
+	}
+	if m.Link != "" {
+		payload["extras"] = map[string]any{
+			"client::notification": map[string]any{"click": map[string]any{"url": m.Link}},
+		}
+	}
+	return payload
+}
+
+func pushoverPayload(m message, token, user, priority string) map[string]any {
+	payload := map[string]any{
+		"token":     token,
+		"user":      user,
+		"title":     Truncate(m.Title, 250),
+		"message":   Truncate(m.text(), maxPushoverMessage),
+		"timestamp": m.Timestamp.Unix(),
+	}
+	if priority != "" {
+		p, _ := rangedPriority(priority, -2, 2)
+		payload["priority"] = p
+		// Emergency priority repeats until acknowledged, which needs both.
+		if p == 2 {
+			payload["retry"] = 60
+			payload["expire"] = 3600
+		}
+	}
+	if m.Link != "" {
+		payload["url"] = m.Link
+		payload["url_title"] = "Open in Dozzle"
+	}
+	return payload
+}
@@ -0,0 +1,253 @@
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type capturedRequest struct {
+	Path    string
+	Headers http.Header
+	Body    map[string]any
+}
+
+// newCaptureServer is a local stand-in for a chat or push service.
+func newCaptureServer(t *testing.T, status int) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	requests := make(chan capturedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		select {
+		case requests <- capturedRequest{Path: r.URL.Path, Headers: r.Header.Clone(), Body: body}:
+		default:
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+// newLocalNativeDispatcher allows the dispatcher to reach the local stand-in.
+func newLocalNativeDispatcher(t *testing.T, kind string, settings NativeSettings) *NativeDispatcher {
+	t.Helper()
+	d, err := NewNativeDispatcher("test", kind, settings)
+	require.NoError(t, err)
+	d.client = &http.Client{Timeout: 5 * time.Second}
+	return d
+}
+
+func newNativeTestNotification() types.Notification {
+	n := newTestNotification(`panic: runtime error: index out of range`)
+	n.Subscription = types.SubscriptionConfig{ID: 1, Name: "Crash alert"}
+	n.Log.ID = 42
+	n.Log.Level = "error"
+	n.Log.Timestamp = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC).UnixMilli()
+	return n
+}
+
+func TestNewNativeDispatcher_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		settings NativeSettings
+		wantErr  string
+	}{
+		{"slack without url", TypeSlack, NativeSettings{}, "slack requires a webhook URL"},
+		{"discord with bad scheme", TypeDiscord, NativeSettings{URL: "ftp://example.com"}, "invalid discord URL"},
+		{"telegram without chat", TypeTelegram, NativeSettings{Token: "t"}, "telegram requires"},
+		{"ntfy without topic", TypeNtfy, NativeSettings{}, "ntfy requires a topic"},
+		{"ntfy bad priority", TypeNtfy, NativeSettings{Topic: "alerts", Priority: "9"}, "priority must be between 1 and 5"},
+		{"gotify without token", TypeGotify, NativeSettings{URL: "https://gotify.example.com"}, "gotify requires"},
+		{"gotify bad priority", TypeGotify, NativeSettings{URL: "https://gotify.example.com", Token: "t", Priority: "11"}, "priority must be between 0 and 10"},
+		{"pushover without user", TypePushover, NativeSettings{Token: "t"}, "pushover requires"},
+		{"pushover bad priority", TypePushover, NativeSettings{Token: "t", User: "u", Priority: "3"}, "priority must be between -2 and 2"},
+		{"bad dozzle url", TypeSlack, NativeSettings{URL: "https://hooks.slack.com/x", DozzleURL: "javascript:alert(1)"}, "invalid Dozzle URL"},
+		{"unknown type", "irc", NativeSettings{}, "unknown dispatcher type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewNativeDispatcher("test", tt.kind, tt.settings)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	_, err := NewNativeDispatcher("test", TypeNtfy, NativeSettings{Topic: "alerts", Priority: "high"})
+	assert.NoError(t, err)
+}
+
+func TestNativeDispatcher_Slack(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusOK)
+	d := newLocalNativeDispatcher(t, TypeSlack, NativeSettings{URL: server.URL, Channel: "#alerts", DozzleURL: "https://dozzle.example.com/"})
+
+	result := d.SendTest(context.Background(), newNativeTestNotification())
+	require.True(t, result.Success, result.Error)
+
+	req := <-requests
+	assert.Equal(t, "#alerts", req.Body["channel"])
+	assert.Equal(t, "Crash alert: my-container", req.Body["text"])
+	blocks := req.Body["blocks"].([]any)
+	require.Len(t, blocks, 4)
+	payload, _ := json.Marshal(blocks)
+	assert.Contains(t, string(payload), "*Host*\\ndocker-host")
+	assert.Contains(t, string(payload), "*Level*\\nerror")
+	assert.Contains(t, string(payload), "index out of range")
+	assert.Contains(t, string(payload), "https://dozzle.example.com/container/abc123/time/2026-05-01T12:00:00Z?logId=42")
+}
+
+func TestNativeDispatcher_Discord(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusNoContent)
+	d := newLocalNativeDispatcher(t, TypeDiscord, NativeSettings{URL: server.URL})
+
+	result := d.SendTest(context.Background(), newNativeTestNotification())
+	require.True(t, result.Success, result.Error)
+
+	req := <-requests
+	embed := req.Body["embeds"].([]any)[0].(map[string]any)
+	assert.Equal(t, "Crash alert", embed["title"])
+	assert.Equal(t, float64(0xef4444), embed["color"])
+	assert.Contains(t, embed["description"], "index out of range")
+	assert.NotContains(t, embed, "url")
+	assert.Len(t, embed["fields"], 5)
+}
+
+func TestNativeDispatcher_TeamsMetric(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusAccepted)
+	d := newLocalNativeDispatcher(t, TypeTeams, NativeSettings{URL: server.URL, DozzleURL: "https://dozzle.example.com"})
+
+	n := newNativeTestNotification()
+	n.Type = types.MetricNotification
+	n.Log = nil
+	n.Stat = &types.NotificationStat{CPUPercent: 91.25, MemoryPercent: 40}
+	n.Detail = "CPU: 91.2%, Memory: 40.0%"
+
+	result := d.SendTest(context.Background(), n)
+	require.True(t, result.Success, result.Error)
+
+	req := <-requests
+	assert.Equal(t, "message", req.Body["type"])
+	payload, _ := json.Marshal(req.Body)
+	assert.Contains(t, string(payload), `"title":"CPU","value":"91.2%"`)
+	assert.Contains(t, string(payload), `"title":"Memory","value":"40.0%"`)
+	assert.Contains(t, string(payload), `"url":"https://dozzle.example.com/container/abc123"`)
+}
+
+func TestNativeDispatcher_Telegram(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusOK)
+	d := newLocalNativeDispatcher(t, TypeTelegram, NativeSettings{URL: server.URL, Token: "123:secret", Channel: "-100200"})
+
+	n := newNativeTestNotification()
+	n.Detail = "<script>"
+	result := d.SendTest(context.Background(), n)
+	require.True(t, result.Success, result.Error)
+
+	req := <-requests
+	assert.Equal(t, "/bot123:secret/sendMessage", req.Path)
+	assert.Equal(t, "-100200", req.Body["chat_id"])
+	assert.Equal(t, "HTML", req.Body["parse_mode"])
+	assert.Contains(t, req.Body["text"], "<pre>&lt;script&gt;</pre>")
+}
+
+func TestNativeDispatcher_Ntfy(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusOK)
+	d := newLocalNativeDispatcher(t, TypeNtfy, NativeSettings{URL: server.URL, Topic: "dozzle", Priority: "urgent", Token: "tk", DozzleURL: "https://dozzle.example.com"})
+
+	result := d.SendTest(context.Background(), newNativeTestNotification())
+	require.True(t, result.Success, result.Error)
+
+	req := <-requests
+	assert.Equal(t, "Bearer tk", req.Headers.Get("Authorization"))
+	assert.Equal(t, "dozzle", req.Body["topic"])
+	assert.Equal(t, float64(5), req.Body["priority"])
+	assert.Equal(t, []any{"rotating_light"}, req.Body["tags"])
+	assert.Contains(t, req.Body["message"], "Container: my-container\nHost: docker-host")
+	assert.Contains(t, req.Body["click"], "https://dozzle.example.com/container/abc123")
+}
+
+func TestNativeDispatcher_Gotify(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusOK)
+	d := newLocalNativeDispatcher(t, TypeGotify, NativeSettings{URL: server.URL + "/", Token: "app-token", Priority: "8"})
+
+	result := d.SendTest(context.Background(), newNativeTestNotification())
+	require.True(t, result.Success, result.Error)
+
+	req := <-requests
+	assert.Equal(t, "/message", req.Path)
+	assert.Equal(t, "app-token", req.Headers.Get("X-Gotify-Key"))
+	assert.Equal(t, float64(8), req.Body["priority"])
+	assert.Equal(t, "Crash alert", req.Body["title"])
+}
+
+func TestNativeDispatcher_Pushover(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusOK)
+	d := newLocalNativeDispatcher(t, TypePushover, NativeSettings{URL: server.URL, Token: "app", User: "user", Priority: "2"})
+
+	result := d.SendTest(context.Background(), newNativeTestNotification())
+	require.True(t, result.Success, result.Error)
+
+	req := <-requests
+	assert.Equal(t, "/1/messages.json", req.Path)
+	assert.Equal(t, "app", req.Body["token"])
+	assert.Equal(t, "user", req.Body["user"])
+	assert.Equal(t, float64(2), req.Body["priority"])
+	assert.Equal(t, float64(60), req.Body["retry"])
+	assert.Equal(t, float64(3600), req.Body["expire"])
+}
+
+func TestNativeDispatcher_NonSuccessStatus(t *testing.T) {
+	server, _ := newCaptureServer(t, http.StatusForbidden)
+	d := newLocalNativeDispatcher(t, TypeSlack, NativeSettings{URL: server.URL})
+
+	result := d.SendTest(context.Background(), newNativeTestNotification())
+	assert.False(t, result.Success)
+	assert.Equal(t, http.StatusForbidden, result.StatusCode)
+	assert.Equal(t, "slack returned status code 403", result.Error)
+	assert.Error(t, d.Send(context.Background(), newNativeTestNotification()))
+}
+
+func TestNativeDispatcher_ErrorHidesToken(t *testing.T) {
+	server, _ := newCaptureServer(t, http.StatusOK)
+	server.Close()
+	d := newLocalNativeDispatcher(t, TypeTelegram, NativeSettings{URL: server.URL, Token: "123:secret", Channel: "1"})
+
+	result := d.SendTest(context.Background(), newNativeTestNotification())
+	assert.False(t, result.Success)
+	assert.NotContains(t, result.Error, "secret")
+}
+
+func TestNativeDispatcher_BlocksLoopback(t *testing.T) {
+	server, _ := newCaptureServer(t, http.StatusOK)
+	d, err := NewNativeDispatcher("test", TypeDiscord, NativeSettings{URL: server.URL})
+	require.NoError(t, err)
+
+	result := d.SendTest(context.Background(), newNativeTestNotification())
+	assert.False(t, result.Success)
+	assert.Equal(t, errBlockedAddress.Error(), result.Error)
+}
+
+func TestNativeDispatcher_Resolved(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusNoContent)
+	d := newLocalNativeDispatcher(t, TypeDiscord, NativeSettings{URL: server.URL})
+
+	n := newNativeTestNotification()
+	n.Type = types.EventNotification
+	n.Status = types.StatusResolved
+	n.Log = nil
+	n.Event = &types.NotificationEvent{Name: "health_status"}
+
+	result := d.SendTest(context.Background(), n)
+	require.True(t, result.Success, result.Error)
+
+	embed := (<-requests).Body["embeds"].([]any)[0].(map[string]any)
+	assert.Equal(t, "Resolved: Crash alert", embed["title"])
+	assert.Equal(t, float64(0x22c55e), embed["color"])
+}
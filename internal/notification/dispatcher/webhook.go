@@ -147,6 +147,19 @@ func safeDialContext(ctx context.Context, network, addr string) (net.Conn, error
 // UserAgent is set by the application at startup
 var UserAgent = "Dozzle/head"
 
+// newSafeClient returns an HTTP client that refuses to dial blocked addresses.
+func newSafeClient() *http.Client {
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext:           safeDialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+	}
+}
+
 // WebhookDispatcher sends notifications to a webhook URL
 type WebhookDispatcher struct {
 	Name         string
@@ -173,15 +186,7 @@ func NewWebhookDispatcher(name, rawURL, templateStr string, headers map[string]s
 		URL:          rawURL,
 		TemplateText: templateStr,
 		Headers:      headers,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-			Transport: &http.Transport{
-				DialContext:           safeDialContext,
-				TLSHandshakeTimeout:   10 * time.Second,
-				ResponseHeaderTimeout: 10 * time.Second,
-				ExpectContinueTimeout: 1 * time.Second,
-			},
-		},
+		client:       newSafeClient(),
 	}
 
 	if templateStr != "" {
@@ -204,15 +209,20 @@ type TestResult struct {
 
 // Send sends a notification to the webhook URL
 func (w *WebhookDispatcher) Send(ctx context.Context, notification types.Notification) error {
-	result := w.SendTest(ctx, notification)
+	result := w.Deliver(ctx, notification)
 	if !result.Success {
 		return fmt.Errorf("webhook notification failed: %s", result.Error)
 	}
 	return nil
 }
 
-// SendTest sends a notification and returns detailed result for testing
+// SendTest sends a test notification and returns the detailed result
 func (w *WebhookDispatcher) SendTest(ctx context.Context, notification types.Notification) TestResult {
+	return w.Deliver(ctx, notification)
+}
+
+// Deliver sends a notification and returns the detailed result
+func (w *WebhookDispatcher) Deliver(ctx context.Context, notification types.Notification) TestResult {
 	var payload []byte
 	var err error
 
@@ -33,6 +33,7 @@ type ContainerLogListener struct {
 	logChannel       chan *container.LogEvent
 	ctx              context.Context
 	cache            *TTLCache[string, containerInfo]
+	unlimited        bool
 }
 
 // NewContainerLogListener creates a new listener for multiple clients
@@ -47,6 +48,12 @@ func NewContainerLogListener(ctx context.Context, clients []container_support.Cl
 	}
 }
 
+// DisableRateLimit forwards every line of noisy containers, for consumers that
+// must not miss any like the log archive. It must be called before Start.
+func (l *ContainerLogListener) DisableRateLimit() {
+	l.unlimited = true
+}
+
 // Start begins listening for container events and processes log streams
 func (l *ContainerLogListener) Start(matcher ContainerMatcher) error {
 	l.matcher = matcher
@@ -131,15 +138,53 @@ func (l *ContainerLogListener) startListening(c container.Container, client cont
 	})
 	l.containerClients.Store(c.ID, client)
 
+	events := l.logChannel
+	var limited chan *container.LogEvent
+	if limiter := container.NewLogRateLimiter(c); limiter != nil && !l.unlimited {
+		limited = make(chan *container.LogEvent)
+		events = limited
+		go l.forwardLimited(streamCtx, c.ID, limiter, limited)
+	}
+
 	go func() {
 		defer l.cleanupStream(c.ID, streamCtx)
+		if limited != nil {
+			defer close(limited)
+		}
 		log.Debug().Str("containerID", c.ID).Str("name", c.Name).Msg("Started listening to container")
-		if err := client.StreamLogs(streamCtx, c, since, container.STDALL, l.logChannel); err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, context.Canceled) && streamCtx.Err() == nil {
+		if err := client.StreamLogs(streamCtx, c, since, container.STDALL, events); err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, context.Canceled) && streamCtx.Err() == nil {
 			log.Error().Err(err).Str("containerID", c.ID).Msg("Error streaming logs")
 		}
 	}()
 }
 
+// forwardLimited passes the events of a rate limited container to the log
+// channel until in is closed. Error lines always pass; the counts of dropped
+// lines are logged once a minute.
+func (l *ContainerLogListener) forwardLimited(ctx context.Context, containerID string, limiter *container.LogRateLimiter, in <-chan *container.LogEvent) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case event, ok := <-in:
+			if !ok {
+				return
+			}
+			if !limiter.Allow(event, time.Now()) {
+				continue
+			}
+			select {
+			case l.logChannel <- event:
+			case <-ctx.Done():
+			}
+		case <-ticker.C:
+			if dropped := limiter.TakeDropped(); dropped != nil {
+				log.Warn().Str("containerID", containerID).Interface("dropped", dropped).Msg("Rate limited logs of container, skipped lines are not checked for alerts")
+			}
+		}
+	}
+}
+
 // cleanupStream removes the activeStreams entry only if it still belongs to this stream
 func (l *ContainerLogListener) cleanupStream(containerID string, streamCtx context.Context) {
 	l.activeStreams.Compute(containerID, func(entry *streamEntry, loaded bool) (*streamEntry, xsync.ComputeOp) {
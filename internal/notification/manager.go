@@ -30,6 +30,7 @@ type Manager struct {
 	ctx                 context.Context
 	cancel              context.CancelFunc
 	sendSem             *semaphore.Weighted
+	deliveryLog         atomic.Pointer[DeliveryLog]
 }
 
 // NewManager creates a new notification manager
@@ -88,6 +89,9 @@ func (m *Manager) AddSubscription(sub *Subscription) error {
 	sub.MetricCooldowns = xsync.NewMap[string, time.Time]()
 	sub.MetricSampleBuffers = xsync.NewMap[string, *utils.RingBuffer[bool]]()
 	sub.EventCooldowns = xsync.NewMap[string, time.Time]()
+	sub.LogCooldowns = xsync.NewMap[string, time.Time]()
+	sub.LogDigests = xsync.NewMap[string, *LogDigest]()
+	sub.AlertStates = xsync.NewMap[string, *AlertState]()
 
 	if err := sub.CompileExpressions(); err != nil {
 		return err
@@ -115,14 +119,20 @@ func (m *Manager) ReplaceSubscription(sub *Subscription) error {
 	sub.MetricCooldowns = xsync.NewMap[string, time.Time]()
 	sub.MetricSampleBuffers = xsync.NewMap[string, *utils.RingBuffer[bool]]()
 	sub.EventCooldowns = xsync.NewMap[string, time.Time]()
+	sub.LogCooldowns = xsync.NewMap[string, time.Time]()
+	sub.LogDigests = xsync.NewMap[string, *LogDigest]()
+	sub.AlertStates = xsync.NewMap[string, *AlertState]()
 
 	if err := sub.CompileExpressions(); err != nil {
 		return err
 	}
 
-	// Preserve enabled state from existing subscription if it exists
+	// Preserve enabled state and firing alerts from existing subscription if it exists
 	if existing, ok := m.subscriptions.Load(sub.ID); ok {
 		sub.Enabled = existing.Enabled
+		if existing.AlertStates != nil {
+			sub.AlertStates = existing.AlertStates
+		}
 	} else {
 		sub.Enabled = true
 	}
@@ -163,6 +173,11 @@ func (m *Manager) UpdateSubscription(id int, updates map[string]any) error {
 			SampleWindow:        sub.SampleWindow,
 			MetricCooldowns:     sub.MetricCooldowns,
 			MetricSampleBuffers: sub.MetricSampleBuffers,
+			GroupBy:             sub.GroupBy,
+			DigestWindow:        sub.DigestWindow,
+			LogCooldowns:        sub.LogCooldowns,
+			LogDigests:          sub.LogDigests,
+			AlertStates:         sub.AlertStates,
 			TriggeredContainerIDs: sub.TriggeredContainerIDs,
 		}
 
@@ -180,6 +195,10 @@ func (m *Manager) UpdateSubscription(id int, updates map[string]any) error {
 			case "enabled":
 				if enabled, ok := value.(bool); ok {
 					updated.Enabled = enabled
+					// Paused alerts aren't evaluated, so they can't stay firing
+					if !enabled {
+						updated.AlertStates = xsync.NewMap[string, *AlertState]()
+					}
 				}
 			case "dispatcherId":
 				if dispatcherID, ok := value.(int); ok {
@@ -244,6 +263,19 @@ func (m *Manager) UpdateSubscription(id int, updates map[string]any) error {
 				if cd, ok := value.(int); ok {
 					updated.Cooldown = cd
 				}
+			case "groupBy":
+				if groupBy, ok := value.(string); ok {
+					if err := ValidateGroupBy(groupBy); err != nil {
+						updateErr = err
+						return nil, xsync.CancelOp
+					}
+					updated.GroupBy = groupBy
+					updated.LogCooldowns = xsync.NewMap[string, time.Time]()
+				}
+			case "digestWindow":
+				if dw, ok := value.(int); ok {
+					updated.DigestWindow = dw
+				}
 			case "sampleWindow":
 				if sw, ok := value.(int); ok {
 					updated.SampleWindow = sw
@@ -393,6 +425,7 @@ func (m *Manager) GetNotificationStats() []types.SubscriptionStats {
 			TriggerCount:          sub.TriggerCount.Load(),
 			LastTriggeredAt:       lastTriggered,
 			TriggeredContainerIDs: containerIDs,
+			FiringAlerts:          sub.FiringAlerts(),
 		})
 		return true
 	})
@@ -427,6 +460,35 @@ func (m *Manager) Dispatchers() []DispatcherConfig {
 				Template: v.TemplateText,
 				Headers:  v.Headers,
 			})
+		case *dispatcher.NativeDispatcher:
+			result = append(result, DispatcherConfig{
+				ID:        id,
+				Name:      v.Name,
+				Type:      v.Type,
+				URL:       v.Settings.URL,
+				Channel:   v.Settings.Channel,
+				Topic:     v.Settings.Topic,
+				Priority:  v.Settings.Priority,
+				Token:     v.Settings.Token,
+				User:      v.Settings.User,
+				DozzleURL: v.Settings.DozzleURL,
+			})
+		case *dispatcher.EmailDispatcher:
+			result = append(result, DispatcherConfig{
+				ID:           id,
+				Name:         v.Name,
+				Type:         dispatcher.TypeEmail,
+				Template:     v.Settings.Body,
+				DozzleURL:    v.Settings.DozzleURL,
+				SMTPHost:     v.Settings.Host,
+				SMTPPort:     v.Settings.Port,
+				SMTPSecurity: v.Settings.Security,
+				SMTPUsername: v.Settings.Username,
+				SMTPPassword: v.Settings.Password,
+				From:         v.Settings.From,
+				To:           v.Settings.To,
+				Subject:      v.Settings.Subject,
+			})
 		}
 		return true
 	})
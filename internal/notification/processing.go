@@ -3,6 +3,7 @@ package notification
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -46,6 +47,10 @@ func (m *Manager) processLogEvent(logEvent *container.LogEvent) {
 
 	notificationContainer := FromContainerModel(c, host)
 	notificationLog := FromLogEvent(*logEvent)
+	detail := logEvent.Summary
+	if detail == "" {
+		detail = formatLogMessage(notificationLog.Message)
+	}
 
 	m.subscriptions.Range(func(_ int, sub *Subscription) bool {
 		// Skip disabled or non-log subscriptions
@@ -63,36 +68,9 @@ func (m *Manager) processLogEvent(logEvent *container.LogEvent) {
 			return true
 		}
 
-		// Update stats
-		sub.AddTriggeredContainer(notificationContainer.ID)
-		sub.TriggerCount.Add(1)
-		now := time.Now()
-		sub.LastTriggeredAt.Store(&now)
-
 		log.Debug().Str("containerID", notificationContainer.ID).Interface("log", notificationLog.Message).Msg("Matched subscription")
 
-		// Create notification
-		notification := types.Notification{
-			ID:        fmt.Sprintf("%s-%d", c.ID, time.Now().UnixNano()),
-			Type:      types.LogNotification,
-			Detail:    formatLogMessage(notificationLog.Message),
-			Container: notificationContainer,
-			Log:       &notificationLog,
-			Subscription: types.SubscriptionConfig{
-				ID:                  sub.ID,
-				Name:                sub.Name,
-				Enabled:             sub.Enabled,
-				DispatcherID:        sub.DispatcherID,
-				LogExpression:       sub.LogExpression,
-				ContainerExpression: sub.ContainerExpression,
-			},
-			Timestamp: time.Now(),
-		}
-
-		// Send to the subscription's dispatcher
-		if d, ok := m.getDispatcher(sub.DispatcherID); ok {
-			go m.sendNotification(d, notification, sub.DispatcherID)
-		}
+		m.handleLogMatch(sub, sub.LogGroupKey(logEvent), notificationContainer, notificationLog, detail)
 		return true
 	})
 }
@@ -147,6 +125,15 @@ func (m *Manager) processStatEvent(event *ContainerStatEvent) {
 		// Evaluate metric expression and record in sample window
 		matched := sub.MatchesMetric(notificationStat)
 		if !sub.RecordMetricSample(event.Stat.ID, matched) {
+			if state, firing := sub.AlertStates.Load(event.Stat.ID); firing && state.Type == types.MetricNotification && sub.IsMetricResolved(event.Stat.ID, matched) {
+				m.sendResolved(sub, event.Stat.ID, types.Notification{
+					Type:         types.MetricNotification,
+					Detail:       metricDetail(notificationStat),
+					Container:    notificationContainer,
+					Stat:         &notificationStat,
+					Subscription: metricSubscriptionConfig(sub),
+				})
+			}
 			return true
 		}
 
@@ -169,23 +156,23 @@ func (m *Manager) processStatEvent(event *ContainerStatEvent) {
 			Str("subscription", sub.Name).
 			Msg("Metric alert triggered")
 
-		notification := types.Notification{
-			ID:        fmt.Sprintf("%s-metric-%d", event.Stat.ID, time.Now().UnixNano()),
+		detail := metricDetail(notificationStat)
+		sub.setFiring(event.Stat.ID, &AlertState{
 			Type:      types.MetricNotification,
-			Detail:    fmt.Sprintf("CPU: %.1f%%, Memory: %.1f%%", notificationStat.CPUPercent, notificationStat.MemoryPercent),
 			Container: notificationContainer,
-			Stat:      &notificationStat,
-			Subscription: types.SubscriptionConfig{
-				ID:                  sub.ID,
-				Name:                sub.Name,
-				Enabled:             sub.Enabled,
-				DispatcherID:        sub.DispatcherID,
-				MetricExpression:    sub.MetricExpression,
-				ContainerExpression: sub.ContainerExpression,
-				Cooldown:            sub.Cooldown,
-				SampleWindow:        sub.SampleWindow,
-			},
-			Timestamp: time.Now(),
+			Detail:    detail,
+			Since:     now,
+		})
+
+		notification := types.Notification{
+			ID:           fmt.Sprintf("%s-metric-%d", event.Stat.ID, time.Now().UnixNano()),
+			Type:         types.MetricNotification,
+			Status:       types.StatusFiring,
+			Detail:       detail,
+			Container:    notificationContainer,
+			Stat:         &notificationStat,
+			Subscription: metricSubscriptionConfig(sub),
+			Timestamp:    time.Now(),
 		}
 
 		if d, ok := m.getDispatcher(sub.DispatcherID); ok {
@@ -225,6 +212,18 @@ func (m *Manager) processDockerEvent(event *ContainerEventEntry) {
 			return true
 		}
 
+		// A firing alert already matched its container, which may look different by now
+		if state, firing := sub.AlertStates.Load(event.Event.ActorID); firing && state.Type == types.EventNotification && state.Event != nil && isRecovery(*state.Event, notificationEvent) {
+			m.sendResolved(sub, event.Event.ActorID, types.Notification{
+				Type:         types.EventNotification,
+				Detail:       eventDetail(notificationEvent),
+				Container:    notificationContainer,
+				Event:        &notificationEvent,
+				Subscription: eventSubscriptionConfig(sub),
+			})
+			return true
+		}
+
 		if !sub.MatchesContainer(notificationContainer) {
 			return true
 		}
@@ -252,27 +251,28 @@ func (m *Manager) processDockerEvent(event *ContainerEventEntry) {
 			Str("subscription", sub.Name).
 			Msg("Event alert triggered")
 
-		detail := fmt.Sprintf("Container event: %s", event.Event.Name)
-		if exitCode, ok := event.Event.ActorAttributes["exitCode"]; ok && event.Event.Name == "die" {
-			detail = fmt.Sprintf("Container event: %s (exit code %s)", event.Event.Name, exitCode)
+		detail := eventDetail(notificationEvent)
+		status := types.NotificationStatus("")
+		if canRecover(notificationEvent) {
+			status = types.StatusFiring
+			sub.setFiring(event.Event.ActorID, &AlertState{
+				Type:      types.EventNotification,
+				Container: notificationContainer,
+				Detail:    detail,
+				Since:     now,
+				Event:     &notificationEvent,
+			})
 		}
 
 		notification := types.Notification{
-			ID:        fmt.Sprintf("%s-event-%d", event.Event.ActorID, time.Now().UnixNano()),
-			Type:      types.EventNotification,
-			Detail:    detail,
-			Container: notificationContainer,
-			Event:     &notificationEvent,
-			Subscription: types.SubscriptionConfig{
-				ID:                  sub.ID,
-				Name:                sub.Name,
-				Enabled:             sub.Enabled,
-				DispatcherID:        sub.DispatcherID,
-				EventExpression:     sub.EventExpression,
-				ContainerExpression: sub.ContainerExpression,
-				Cooldown:            sub.Cooldown,
-			},
-			Timestamp: time.Now(),
+			ID:           fmt.Sprintf("%s-event-%d", event.Event.ActorID, time.Now().UnixNano()),
+			Type:         types.EventNotification,
+			Status:       status,
+			Detail:       detail,
+			Container:    notificationContainer,
+			Event:        &notificationEvent,
+			Subscription: eventSubscriptionConfig(sub),
+			Timestamp:    time.Now(),
 		}
 
 		if d, ok := m.getDispatcher(sub.DispatcherID); ok {
@@ -282,6 +282,45 @@ func (m *Manager) processDockerEvent(event *ContainerEventEntry) {
 	})
 }
 
+func eventDetail(event types.NotificationEvent) string {
+	switch {
+	case event.Name == "die" && event.Attributes["exitCode"] != "":
+		return fmt.Sprintf("Container event: %s (exit code %s)", event.Name, event.Attributes["exitCode"])
+	case event.Name == "health_status" && event.Attributes["healthStatus"] != "":
+		return fmt.Sprintf("Container event: %s (%s)", event.Name, event.Attributes["healthStatus"])
+	}
+	return fmt.Sprintf("Container event: %s", event.Name)
+}
+
+func metricDetail(stat types.NotificationStat) string {
+	return fmt.Sprintf("CPU: %.1f%%, Memory: %.1f%%", stat.CPUPercent, stat.MemoryPercent)
+}
+
+func metricSubscriptionConfig(sub *Subscription) types.SubscriptionConfig {
+	return types.SubscriptionConfig{
+		ID:                  sub.ID,
+		Name:                sub.Name,
+		Enabled:             sub.Enabled,
+		DispatcherID:        sub.DispatcherID,
+		MetricExpression:    sub.MetricExpression,
+		ContainerExpression: sub.ContainerExpression,
+		Cooldown:            sub.Cooldown,
+		SampleWindow:        sub.SampleWindow,
+	}
+}
+
+func eventSubscriptionConfig(sub *Subscription) types.SubscriptionConfig {
+	return types.SubscriptionConfig{
+		ID:                  sub.ID,
+		Name:                sub.Name,
+		Enabled:             sub.Enabled,
+		DispatcherID:        sub.DispatcherID,
+		EventExpression:     sub.EventExpression,
+		ContainerExpression: sub.ContainerExpression,
+		Cooldown:            sub.Cooldown,
+	}
+}
+
 func formatLogMessage(message any) string {
 	switch v := message.(type) {
 	case string:
@@ -295,12 +334,25 @@ func formatLogMessage(message any) string {
 	}
 }
 
-// sendNotification sends a notification using the dispatcher
+// SendTest sends a test notification through tester, sharing the limit of
+// concurrent sends with alerts.
+func (m *Manager) SendTest(ctx context.Context, tester dispatcher.Tester, notification types.Notification) dispatcher.TestResult {
+	if err := m.sendSem.Acquire(ctx, 1); err != nil {
+		return dispatcher.TestResult{Error: "too many pending notifications"}
+	}
+	defer m.sendSem.Release(1)
+	return tester.SendTest(ctx, notification)
+}
+
+// sendNotification sends a notification using the dispatcher and records the delivery
 func (m *Manager) sendNotification(d dispatcher.Dispatcher, notification types.Notification, id int) {
 	acquireCtx, acquireCancel := context.WithTimeout(m.ctx, time.Minute)
 	defer acquireCancel()
 	if err := m.sendSem.Acquire(acquireCtx, 1); err != nil {
 		log.Warn().Err(err).Int("subscription", id).Msg("Notification dropped: too many pending")
+		if m.ctx.Err() == nil {
+			m.recordDelivery(d, notification, id, 0, 0, errors.New("dropped: too many pending notifications"))
+		}
 		return
 	}
 	defer m.sendSem.Release(1)
@@ -308,7 +360,23 @@ func (m *Manager) sendNotification(d dispatcher.Dispatcher, notification types.N
 	ctx, cancel := context.WithTimeout(m.ctx, 30*time.Second)
 	defer cancel()
 
-	if err := d.Send(ctx, notification); err != nil {
+	// Most dispatchers report the HTTP or SMTP status code
+	var err error
+	var statusCode int
+	start := time.Now()
+	if deliverer, ok := d.(dispatcher.Deliverer); ok {
+		result := deliverer.Deliver(ctx, notification)
+		statusCode = result.StatusCode
+		if !result.Success {
+			err = errors.New(result.Error)
+		}
+	} else {
+		err = d.Send(ctx, notification)
+	}
+	latency := time.Since(start)
+
+	if err != nil {
 		log.Error().Err(err).Int("subscription", id).Msg("Failed to send notification")
 	}
+	m.recordDelivery(d, notification, id, latency, statusCode, err)
 }
@@ -0,0 +1,36 @@
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/notification/dispatcher"
+	"github.com/amir20/dozzle/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type testerFunc func(context.Context, types.Notification) dispatcher.TestResult
+
+func (f testerFunc) SendTest(ctx context.Context, n types.Notification) dispatcher.TestResult {
+	return f(ctx, n)
+}
+
+func TestSendTest_WaitsForSendSlot(t *testing.T) {
+	m, _ := newTestManager(t)
+	sent := 0
+	tester := testerFunc(func(context.Context, types.Notification) dispatcher.TestResult {
+		sent++
+		return dispatcher.TestResult{Success: true, StatusCode: 200}
+	})
+
+	assert.True(t, m.SendTest(context.Background(), tester, types.Notification{}).Success)
+
+	m.sendSem.Acquire(context.Background(), 5)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	result := m.SendTest(ctx, tester, types.Notification{})
+	assert.False(t, result.Success)
+	assert.Equal(t, "too many pending notifications", result.Error)
+	assert.Equal(t, 1, sent)
+}
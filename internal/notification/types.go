@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/patterns"
 	"github.com/amir20/dozzle/internal/utils"
 	"github.com/amir20/dozzle/types"
 	"github.com/expr-lang/expr"
@@ -128,6 +129,8 @@ type Subscription struct {
 	EventExpression     string `json:"eventExpression,omitempty" yaml:"eventExpression,omitempty"`
 	Cooldown            int    `json:"cooldown,omitempty" yaml:"cooldown,omitempty"`         // seconds between metric notifications, default 300
 	SampleWindow        int    `json:"sampleWindow,omitempty" yaml:"sampleWindow,omitempty"` // seconds of samples to evaluate, default 15
+	GroupBy             string `json:"groupBy,omitempty" yaml:"groupBy,omitempty"`           // log alerts: container (default), template or field:<path>
+	DigestWindow        int    `json:"digestWindow,omitempty" yaml:"digestWindow,omitempty"` // log alerts: seconds to batch matches into one notification, 0 disables
 
 	// Compiled filter expressions
 	LogProgram       *vm.Program `json:"-" yaml:"-"` // Compiled log filter expression
@@ -148,6 +151,15 @@ type Subscription struct {
 
 	// Per-container sample buffers for windowed metric evaluation (containerID -> ring buffer of match results)
 	MetricSampleBuffers *xsync.Map[string, *utils.RingBuffer[bool]] `json:"-" yaml:"-"`
+
+	// Per-group cooldown tracking for log alerts (group key -> last triggered time)
+	LogCooldowns *xsync.Map[string, time.Time] `json:"-" yaml:"-"`
+
+	// Open digests of log alerts (group key -> matches batched so far)
+	LogDigests *xsync.Map[string, *LogDigest] `json:"-" yaml:"-"`
+
+	// Firing metric and event alerts (containerID -> alert state)
+	AlertStates *xsync.Map[string, *AlertState] `json:"-" yaml:"-"`
 }
 
 // TriggeredContainersCount returns the number of unique containers that triggered this subscription
@@ -167,7 +179,8 @@ func (s *Subscription) AddTriggeredContainer(id string) {
 }
 
 // CompileExpressions compiles all expression strings into executable programs.
-// Returns an error describing which expression failed to compile.
+// Returns an error describing which expression failed to compile, or if more
+// than one of the log, metric and event expressions is set.
 func (s *Subscription) CompileExpressions() error {
 	if s.ContainerExpression != "" {
 		program, err := expr.Compile(s.ContainerExpression, expr.Env(types.NotificationContainer{}))
@@ -201,6 +214,10 @@ func (s *Subscription) CompileExpressions() error {
 		s.EventProgram = program
 	}
 
+	if err := ValidateGroupBy(s.GroupBy); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -208,11 +225,29 @@ func (s *Subscription) CompileExpressions() error {
 type DispatcherConfig struct {
 	ID       int               `json:"id" yaml:"id"`
 	Name     string            `json:"name" yaml:"name"`
-	Type     string            `json:"type" yaml:"type"` // "webhook" or "cloud"
+	Type     string            `json:"type" yaml:"type"` // "webhook", "cloud" or a native type such as "slack"
 	URL      string            `json:"url,omitempty" yaml:"url,omitempty"`
 	Template string            `json:"template,omitempty" yaml:"template,omitempty"` // Go template for custom payload format
 	Headers  map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`   // Custom HTTP headers
 	Prefix   string            `json:"prefix,omitempty" yaml:"-"`                    // Cloud dispatcher API key prefix (not persisted)
+
+	// Settings of native dispatchers (slack, discord, teams, telegram, ntfy, gotify, pushover)
+	Channel   string `json:"channel,omitempty" yaml:"channel,omitempty"`
+	Topic     string `json:"topic,omitempty" yaml:"topic,omitempty"`
+	Priority  string `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Token     string `json:"token,omitempty" yaml:"token,omitempty"`
+	User      string `json:"user,omitempty" yaml:"user,omitempty"`
+	DozzleURL string `json:"dozzleUrl,omitempty" yaml:"dozzleUrl,omitempty"`
+
+	// Settings of email dispatchers; Template is the HTML body template
+	SMTPHost     string   `json:"smtpHost,omitempty" yaml:"smtpHost,omitempty"`
+	SMTPPort     int      `json:"smtpPort,omitempty" yaml:"smtpPort,omitempty"`
+	SMTPSecurity string   `json:"smtpSecurity,omitempty" yaml:"smtpSecurity,omitempty"` // starttls, tls or none
+	SMTPUsername string   `json:"smtpUsername,omitempty" yaml:"smtpUsername,omitempty"`
+	SMTPPassword string   `json:"smtpPassword,omitempty" yaml:"smtpPassword,omitempty"`
+	From         string   `json:"from,omitempty" yaml:"from,omitempty"`
+	To           []string `json:"to,omitempty" yaml:"to,omitempty"`
+	Subject      string   `json:"subject,omitempty" yaml:"subject,omitempty"`
 }
 
 // Config represents the persisted notification configuration
@@ -255,6 +290,22 @@ func (s *Subscription) MatchesLog(l types.NotificationLog) bool {
 	return ok && match
 }
 
+// ValidateAlertKind returns an error if more than one of the log, metric and
+// event expressions is set, since a rule alerts on only one kind of input.
+// Rules saved before this was enforced are split when they are loaded.
+func (s *Subscription) ValidateAlertKind() error {
+	kinds := 0
+	for _, expression := range []string{s.LogExpression, s.MetricExpression, s.EventExpression} {
+		if expression != "" {
+			kinds++
+		}
+	}
+	if kinds > 1 {
+		return fmt.Errorf("only one of log, metric and event expressions can be set")
+	}
+	return nil
+}
+
 // IsLogAlert returns true if this subscription is a log-based alert
 func (s *Subscription) IsLogAlert() bool {
 	return s.LogExpression != "" && s.LogProgram != nil
@@ -390,3 +441,82 @@ func (s *Subscription) RecordMetricSample(containerID string, matched bool) bool
 
 	return float64(trueCount)/float64(buf.Len()) >= 0.8
 }
+
+// maxLogCooldownKeys is the number of log cooldown keys above which expired ones are pruned
+const maxLogCooldownKeys = 1000
+
+// Group-by keys of log alerts. Any other key must be GroupByFieldPrefix
+// followed by the name or dotted path of a field in structured logs.
+const (
+	GroupByContainer   = "container"
+	GroupByTemplate    = "template"
+	GroupByFieldPrefix = "field:"
+)
+
+// ValidateGroupBy returns an error if groupBy is not a known group-by key
+func ValidateGroupBy(groupBy string) error {
+	switch {
+	case groupBy == "", groupBy == GroupByContainer, groupBy == GroupByTemplate:
+		return nil
+	case strings.HasPrefix(groupBy, GroupByFieldPrefix) && strings.TrimPrefix(groupBy, GroupByFieldPrefix) != "":
+		return nil
+	}
+	return fmt.Errorf("invalid groupBy %q: must be container, template or field:<name>", groupBy)
+}
+
+// LogGroupKey returns the key a matching log event is deduplicated and batched by.
+// Keys are always scoped to the container, so one noisy replica doesn't silence the others.
+func (s *Subscription) LogGroupKey(event *container.LogEvent) string {
+	switch {
+	case s.GroupBy == GroupByTemplate:
+		return event.ContainerID + "|" + strings.Join(patterns.Tokenize(patterns.MessageText(event)), " ")
+	case strings.HasPrefix(s.GroupBy, GroupByFieldPrefix):
+		value, ok := container.FieldValue(event.Message, strings.TrimPrefix(s.GroupBy, GroupByFieldPrefix))
+		if !ok {
+			return event.ContainerID + "|"
+		}
+		return event.ContainerID + "|" + fmt.Sprint(value)
+	default:
+		return event.ContainerID
+	}
+}
+
+// IsLogCooldownActive checks if the cooldown is still active for a given group key
+func (s *Subscription) IsLogCooldownActive(key string) bool {
+	if s.GetCooldownSeconds() == 0 {
+		return false
+	}
+	lastTriggered, ok := s.LogCooldowns.Load(key)
+	if !ok {
+		return false
+	}
+	cooldown := time.Duration(s.GetCooldownSeconds()) * time.Second
+	return time.Now().Before(lastTriggered.Add(cooldown))
+}
+
+// SetLogCooldown records the current time as the last triggered time for a group key.
+// Expired keys are pruned once there are many, since template and field keys are unbounded.
+func (s *Subscription) SetLogCooldown(key string) {
+	now := time.Now()
+	if s.LogCooldowns.Size() >= maxLogCooldownKeys {
+		cooldown := time.Duration(s.GetCooldownSeconds()) * time.Second
+		s.LogCooldowns.Range(func(k string, lastTriggered time.Time) bool {
+			if !now.Before(lastTriggered.Add(cooldown)) {
+				s.LogCooldowns.Delete(k)
+			}
+			return true
+		})
+	}
+	s.LogCooldowns.Store(key, now)
+}
+
+// GetDigestWindowSeconds returns the digest window in seconds, clamped to [0, 3600]. 0 disables digests.
+func (s *Subscription) GetDigestWindowSeconds() int {
+	if s.DigestWindow <= 0 {
+		return 0
+	}
+	if s.DigestWindow > 3600 {
+		return 3600
+	}
+	return s.DigestWindow
+}
@@ -1,6 +1,7 @@
 package notification
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -566,3 +567,59 @@ func TestFromContainerMounts(t *testing.T) {
 		assert.Nil(t, FromContainerMounts(container.Container{}))
 	})
 }
+
+func TestValidateGroupBy(t *testing.T) {
+	for _, groupBy := range []string{"", "container", "template", "field:request.path"} {
+		assert.NoError(t, ValidateGroupBy(groupBy), groupBy)
+	}
+	for _, groupBy := range []string{"field:", "message", "Template"} {
+		assert.Error(t, ValidateGroupBy(groupBy), groupBy)
+	}
+}
+
+func TestSubscription_LogGroupKey(t *testing.T) {
+	structured := orderedmap.New[string, any]()
+	structured.Set("msg", "request failed")
+	structured.Set("request", map[string]any{"path": "/api/users"})
+
+	t.Run("defaults to container", func(t *testing.T) {
+		sub := &Subscription{}
+		assert.Equal(t, "abc", sub.LogGroupKey(&container.LogEvent{ContainerID: "abc", Message: "error 1"}))
+	})
+	t.Run("template ignores variable tokens", func(t *testing.T) {
+		sub := &Subscription{GroupBy: GroupByTemplate}
+		a := sub.LogGroupKey(&container.LogEvent{ContainerID: "abc", Message: "timeout after 30s on 10.0.0.1"})
+		b := sub.LogGroupKey(&container.LogEvent{ContainerID: "abc", Message: "timeout after 45s on 10.0.0.2"})
+		c := sub.LogGroupKey(&container.LogEvent{ContainerID: "abc", Message: "connection refused"})
+		assert.Equal(t, a, b)
+		assert.NotEqual(t, a, c)
+		assert.NotEqual(t, a, sub.LogGroupKey(&container.LogEvent{ContainerID: "def", Message: "timeout after 30s on 10.0.0.1"}))
+	})
+	t.Run("field looks up dotted paths", func(t *testing.T) {
+		sub := &Subscription{GroupBy: "field:request.path"}
+		assert.Equal(t, "abc|/api/users", sub.LogGroupKey(&container.LogEvent{ContainerID: "abc", Message: structured}))
+		assert.Equal(t, "abc|", sub.LogGroupKey(&container.LogEvent{ContainerID: "abc", Message: "plain text"}))
+	})
+}
+
+func TestSubscription_LogCooldown(t *testing.T) {
+	t.Run("cooldown 0 always returns false", func(t *testing.T) {
+		sub := &Subscription{LogCooldowns: xsync.NewMap[string, time.Time]()}
+		sub.SetLogCooldown("abc")
+		assert.False(t, sub.IsLogCooldownActive("abc"))
+	})
+	t.Run("cooldown active per key", func(t *testing.T) {
+		sub := &Subscription{Cooldown: 60, LogCooldowns: xsync.NewMap[string, time.Time]()}
+		sub.SetLogCooldown("abc")
+		assert.True(t, sub.IsLogCooldownActive("abc"))
+		assert.False(t, sub.IsLogCooldownActive("def"))
+	})
+	t.Run("prunes expired keys", func(t *testing.T) {
+		sub := &Subscription{Cooldown: 60, LogCooldowns: xsync.NewMap[string, time.Time]()}
+		for i := range maxLogCooldownKeys {
+			sub.LogCooldowns.Store(fmt.Sprint(i), time.Now().Add(-time.Hour))
+		}
+		sub.SetLogCooldown("abc")
+		assert.Equal(t, 1, sub.LogCooldowns.Size())
+	})
+}
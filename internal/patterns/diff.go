@@ -0,0 +1,127 @@
+package patterns
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+)
+
+// changeFactor is how much the share of lines of a template must grow or
+// shrink between two ranges to count as changed.
+const changeFactor = 2.0
+
+// Change is a template found in both ranges of a diff, or in only one.
+type Change struct {
+	Template string  `json:"template"`
+	CountA   int     `json:"countA"`
+	CountB   int     `json:"countB"`
+	PercentA float64 `json:"percentA"`
+	PercentB float64 `json:"percentB"`
+	Example  string  `json:"example"`
+}
+
+// Diff compares the templates of two log ranges A and B. Counts are compared
+// by their share of each range, so ranges of different volume can be
+// compared. DroppedA and DroppedB count the lines of each range that found no
+// template because the miner was full.
+type Diff struct {
+	TotalA    int      `json:"totalA"`
+	TotalB    int      `json:"totalB"`
+	DroppedA  int      `json:"droppedA"`
+	DroppedB  int      `json:"droppedB"`
+	OnlyInA   []Change `json:"onlyInA"`
+	OnlyInB   []Change `json:"onlyInB"`
+	Changed   []Change `json:"changed"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// Compare clusters the events of a and b with one miner, so the same template
+// is recognized in both, and returns up to limit templates per list, biggest
+// difference first. The example of a template is its first line. Events are
+// taken from a and b in turn, so a range with many templates cannot fill the
+// miner before the other range is read.
+func Compare(a, b <-chan *container.LogEvent, limit int) Diff {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	miner := NewMiner()
+	counts := make(map[*Cluster]*[2]int)
+	totals := [2]int{}
+	dropped := [2]int{}
+	sides := [2]<-chan *container.LogEvent{a, b}
+	for sides[0] != nil || sides[1] != nil {
+		for side, events := range sides {
+			if events == nil {
+				continue
+			}
+			event, ok := <-events
+			if !ok {
+				sides[side] = nil
+				continue
+			}
+			text := MessageText(event)
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			totals[side]++
+			cluster := miner.Add(text, event.Level, time.UnixMilli(event.Timestamp))
+			if cluster == nil {
+				dropped[side]++
+				continue
+			}
+			if counts[cluster] == nil {
+				counts[cluster] = &[2]int{}
+			}
+			counts[cluster][side]++
+		}
+	}
+
+	diff := Diff{TotalA: totals[0], TotalB: totals[1], DroppedA: dropped[0], DroppedB: dropped[1], OnlyInA: []Change{}, OnlyInB: []Change{}, Changed: []Change{}}
+	for _, cluster := range miner.Clusters() {
+		count := counts[cluster]
+		if count == nil {
+			continue
+		}
+		change := Change{
+			Template: cluster.String(),
+			CountA:   count[0],
+			CountB:   count[1],
+			PercentA: percent(count[0], totals[0]),
+			PercentB: percent(count[1], totals[1]),
+			Example:  cluster.Example,
+		}
+		switch {
+		case change.CountB == 0:
+			diff.OnlyInA = append(diff.OnlyInA, change)
+		case change.CountA == 0:
+			diff.OnlyInB = append(diff.OnlyInB, change)
+		case math.Max(change.PercentA, change.PercentB) >= changeFactor*math.Min(change.PercentA, change.PercentB):
+			diff.Changed = append(diff.Changed, change)
+		default:
+			diff.Unchanged++
+		}
+	}
+
+	byCount := func(x, y Change) int { return (y.CountA + y.CountB) - (x.CountA + x.CountB) }
+	slices.SortStableFunc(diff.OnlyInA, byCount)
+	slices.SortStableFunc(diff.OnlyInB, byCount)
+	slices.SortStableFunc(diff.Changed, func(x, y Change) int {
+		return cmp.Compare(math.Abs(y.PercentB-y.PercentA), math.Abs(x.PercentB-x.PercentA))
+	})
+	diff.OnlyInA = diff.OnlyInA[:min(limit, len(diff.OnlyInA))]
+	diff.OnlyInB = diff.OnlyInB[:min(limit, len(diff.OnlyInB))]
+	diff.Changed = diff.Changed[:min(limit, len(diff.Changed))]
+	return diff
+}
+
+func percent(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) * 100 / float64(total)
+}
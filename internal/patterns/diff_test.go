@@ -0,0 +1,89 @@
+package patterns
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func events(messages ...string) <-chan *container.LogEvent {
+	ch := make(chan *container.LogEvent, len(messages))
+	for _, message := range messages {
+		ch <- &container.LogEvent{Type: container.LogTypeSingle, Message: message, Level: "info"}
+	}
+	close(ch)
+	return ch
+}
+
+func TestCompare(t *testing.T) {
+	a := events(
+		"listening on port 8080",
+		"user alice logged in",
+		"user bob logged in",
+		"cache warmed in 120ms",
+		"GET /health 200",
+	)
+	b := events(
+		"listening on port 8081",
+		"user carol logged in",
+		"connection refused by db-1",
+		"connection refused by db-2",
+		"GET /health 500",
+		"GET /health 500",
+		"GET /health 500",
+		"GET /health 500",
+	)
+
+	diff := Compare(a, b, 10)
+	assert.Equal(t, 5, diff.TotalA)
+	assert.Equal(t, 8, diff.TotalB)
+
+	require.Len(t, diff.OnlyInA, 1)
+	assert.Equal(t, "cache warmed in <*>", diff.OnlyInA[0].Template)
+
+	require.Len(t, diff.OnlyInB, 1)
+	assert.Equal(t, "connection refused by <*>", diff.OnlyInB[0].Template)
+	assert.Equal(t, 2, diff.OnlyInB[0].CountB)
+	assert.Equal(t, "connection refused by db-1", diff.OnlyInB[0].Example)
+
+	require.Len(t, diff.Changed, 2)
+	assert.Equal(t, "GET /health <*>", diff.Changed[0].Template)
+	assert.InDelta(t, 20.0, diff.Changed[0].PercentA, 0.001)
+	assert.InDelta(t, 50.0, diff.Changed[0].PercentB, 0.001)
+	assert.Equal(t, "user <*> logged in", diff.Changed[1].Template)
+	assert.Equal(t, 1, diff.Unchanged)
+}
+
+func TestCompare_Empty(t *testing.T) {
+	diff := Compare(events(), events("disk full"), 0)
+	assert.Empty(t, diff.OnlyInA)
+	require.Len(t, diff.OnlyInB, 1)
+	assert.Empty(t, diff.Changed)
+	assert.Zero(t, diff.OnlyInB[0].PercentA)
+}
+
+func TestCompare_ManyTemplatesInA(t *testing.T) {
+	var messages []string
+	for i := range 2 * defaultMaxClusters {
+		messages = append(messages, fmt.Sprintf("job%c%c", 'a'+i%26, 'a'+i/26))
+	}
+	b := events(
+		"GET /health 200",
+		"GET /health 200",
+		"connection refused by db-1",
+		"connection refused by db-2",
+	)
+
+	diff := Compare(events(messages...), b, 10)
+	assert.Equal(t, 2*defaultMaxClusters, diff.TotalA)
+	assert.Equal(t, 4, diff.TotalB)
+	assert.Positive(t, diff.DroppedA)
+	assert.Zero(t, diff.DroppedB)
+
+	require.Len(t, diff.OnlyInB, 2)
+	assert.Equal(t, "GET /health <*>", diff.OnlyInB[0].Template)
+	assert.Equal(t, "connection refused by <*>", diff.OnlyInB[1].Template)
+}
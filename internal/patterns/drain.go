@@ -0,0 +1,200 @@
+// Package patterns groups log lines into recurring message templates using a
+// variant of the Drain algorithm (He et al., "Drain: An Online Log Parsing
+// Approach with Fixed Depth Tree", ICWS 2017).
+package patterns
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Wildcard replaces the variable tokens of a template.
+const Wildcard = "<*>"
+
+const (
+	// defaultDepth is the number of leading tokens used to route a line to its
+	// candidate clusters, after the token count. Routing on more tokens is
+	// faster but splits templates whose second word varies, like user names.
+	defaultDepth = 1
+	// defaultSimilarity is the share of equal tokens a line needs to join a
+	// cluster.
+	defaultSimilarity = 0.5
+	// defaultMaxChildren bounds the fan-out of a tree node. Further tokens are
+	// routed through the wildcard child.
+	defaultMaxChildren = 100
+	// defaultMaxClusters bounds the memory of a single miner.
+	defaultMaxClusters = 1000
+)
+
+// variableToken matches tokens that are almost certainly parameters: numbers,
+// timestamps, hex ids, UUIDs, IPs, durations and sizes.
+var variableToken = regexp.MustCompile(`^(?:[-+]?\d[\d.,:_/-]*[a-zA-Zµ%]{0,3}|\d{4}-\d{2}-\d{2}T[\d:.,]+(?:Z|[+-]\d{2}:?\d{2})?|0x[0-9a-fA-F]+|[0-9a-fA-F]{12,}|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`)
+
+// Cluster is a message template and the lines that matched it.
+type Cluster struct {
+	Template  []string
+	Count     int
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Levels    map[string]int
+	Example   string
+}
+
+// String returns the template as a single line.
+func (c *Cluster) String() string {
+	return strings.Join(c.Template, " ")
+}
+
+type node struct {
+	children map[string]*node
+	clusters []*Cluster
+}
+
+func newNode() *node {
+	return &node{children: make(map[string]*node)}
+}
+
+// Miner incrementally clusters log lines. It is not safe for concurrent use.
+type Miner struct {
+	root     *node
+	clusters []*Cluster
+	total    int
+}
+
+// NewMiner returns an empty miner.
+func NewMiner() *Miner {
+	return &Miner{root: newNode()}
+}
+
+// Tokenize splits message into whitespace separated tokens and replaces the
+// obvious parameters with Wildcard. The key of a key=value token is kept.
+func Tokenize(message string) []string {
+	tokens := strings.Fields(message)
+	for i, token := range tokens {
+		if variableToken.MatchString(strings.Trim(token, `,;:()[]{}"'`)) {
+			tokens[i] = Wildcard
+		} else if key, value, ok := strings.Cut(token, "="); ok && key != "" && variableToken.MatchString(strings.Trim(value, `,;:()[]{}"'`)) {
+			tokens[i] = key + "=" + Wildcard
+		}
+	}
+	return tokens
+}
+
+// Add clusters a line and returns its cluster, or nil when message is empty or
+// the miner is full and no existing cluster matches.
+func (m *Miner) Add(message, level string, timestamp time.Time) *Cluster {
+	tokens := Tokenize(message)
+	if len(tokens) == 0 {
+		return nil
+	}
+	m.total++
+
+	leaf := m.route(tokens)
+	cluster := bestMatch(leaf.clusters, tokens)
+	if cluster == nil {
+		if len(m.clusters) >= defaultMaxClusters {
+			return nil
+		}
+		cluster = &Cluster{
+			Template:  tokens,
+			FirstSeen: timestamp,
+			LastSeen:  timestamp,
+			Levels:    make(map[string]int),
+			Example:   message,
+		}
+		leaf.clusters = append(leaf.clusters, cluster)
+		m.clusters = append(m.clusters, cluster)
+	} else {
+		for i, token := range tokens {
+			if cluster.Template[i] != token {
+				cluster.Template[i] = Wildcard
+			}
+		}
+	}
+
+	cluster.Count++
+	if level != "" {
+		cluster.Levels[level]++
+	}
+	if timestamp.Before(cluster.FirstSeen) {
+		cluster.FirstSeen = timestamp
+	}
+	if timestamp.After(cluster.LastSeen) {
+		cluster.LastSeen = timestamp
+	}
+	return cluster
+}
+
+// route walks the fixed depth tree by token count and leading tokens, creating
+// nodes as needed, and returns the leaf holding the candidate clusters.
+func (m *Miner) route(tokens []string) *node {
+	current := m.child(m.root, lengthKey(len(tokens)))
+	for i := 0; i < defaultDepth && i < len(tokens); i++ {
+		token := tokens[i]
+		if _, ok := current.children[token]; !ok && len(current.children) >= defaultMaxChildren {
+			token = Wildcard
+		}
+		current = m.child(current, token)
+	}
+	return current
+}
+
+func (m *Miner) child(parent *node, key string) *node {
+	child, ok := parent.children[key]
+	if !ok {
+		child = newNode()
+		parent.children[key] = child
+	}
+	return child
+}
+
+func lengthKey(n int) string {
+	return "#" + strconv.Itoa(n)
+}
+
+// bestMatch returns the most similar cluster at or above defaultSimilarity.
+// A template wildcard counts as equal only to a parameter of the line; on
+// ties the more general template wins, as in the reference implementation.
+func bestMatch(clusters []*Cluster, tokens []string) *Cluster {
+	var best *Cluster
+	bestSimilarity, bestWildcards := -1.0, 0
+	for _, cluster := range clusters {
+		if len(cluster.Template) != len(tokens) {
+			continue
+		}
+		equal, wildcards := 0, 0
+		for i, token := range cluster.Template {
+			switch token {
+			case tokens[i]:
+				equal++
+			case Wildcard:
+				wildcards++
+			}
+		}
+		similarity := float64(equal) / float64(len(tokens))
+		if similarity < defaultSimilarity {
+			continue
+		}
+		if similarity > bestSimilarity || (similarity == bestSimilarity && wildcards > bestWildcards) {
+			best, bestSimilarity, bestWildcards = cluster, similarity, wildcards
+		}
+	}
+	return best
+}
+
+// Total returns the number of lines added.
+func (m *Miner) Total() int {
+	return m.total
+}
+
+// Clusters returns all clusters, most frequent first.
+func (m *Miner) Clusters() []*Cluster {
+	clusters := slices.Clone(m.clusters)
+	slices.SortStableFunc(clusters, func(a, b *Cluster) int {
+		return b.Count - a.Count
+	})
+	return clusters
+}
@@ -0,0 +1,72 @@
+package patterns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t,
+		[]string{"user", "<*>", "logged", "in", "from", "<*>", "after", "<*>"},
+		Tokenize("user 42 logged in from 10.0.0.1 after 1.5s"),
+	)
+	assert.Equal(t, []string{"request", "<*>", "done"}, Tokenize("request 3f2a9c1e-8d4b-4e2a-9f1c-0a1b2c3d4e5f done"))
+	assert.Equal(t,
+		[]string{"<*>", "served", "request_id=<*>", "in", "duration=<*>", "user=bob"},
+		Tokenize("2024-03-01T03:12:45.123+01:00 served request_id=8f3a2b1c9d4e5f60 in duration=12ms user=bob"),
+	)
+	assert.Empty(t, Tokenize("   "))
+}
+
+func TestMiner(t *testing.T) {
+	miner := NewMiner()
+	now := time.Now()
+
+	miner.Add("user alice logged in", "info", now)
+	miner.Add("user bob logged in", "info", now.Add(time.Second))
+	miner.Add("user carol logged in", "warn", now.Add(-time.Second))
+	miner.Add("cache miss for key 12", "debug", now)
+	miner.Add("disk full", "error", now)
+	assert.Nil(t, miner.Add("", "info", now))
+
+	clusters := miner.Clusters()
+	require.Len(t, clusters, 3)
+	assert.Equal(t, "user <*> logged in", clusters[0].String())
+	assert.Equal(t, 3, clusters[0].Count)
+	assert.Equal(t, map[string]int{"info": 2, "warn": 1}, clusters[0].Levels)
+	assert.Equal(t, now.Add(-time.Second), clusters[0].FirstSeen)
+	assert.Equal(t, now.Add(time.Second), clusters[0].LastSeen)
+	assert.Equal(t, "user alice logged in", clusters[0].Example)
+	assert.Equal(t, 5, miner.Total())
+}
+
+func TestMiner_DifferentLengthsDoNotMerge(t *testing.T) {
+	miner := NewMiner()
+	miner.Add("connection closed", "info", time.Now())
+	miner.Add("connection closed by peer", "info", time.Now())
+	assert.Len(t, miner.Clusters(), 2)
+}
+
+func TestSummarize(t *testing.T) {
+	events := make(chan *container.LogEvent, 4)
+	fields := orderedmap.New[string, any]()
+	fields.Set("msg", "order 1 shipped")
+	events <- &container.LogEvent{Type: container.LogTypeComplex, Message: fields, Level: "info"}
+	events <- &container.LogEvent{Type: container.LogTypeSingle, Message: "order 2 shipped", Level: "info"}
+	events <- &container.LogEvent{Type: container.LogTypeGroup, Message: []container.LogFragment{{Message: "panic: boom"}, {Message: "  at main.go:12"}}, Level: "error"}
+	events <- &container.LogEvent{Type: container.LogTypeComplex, Summary: "order 3 shipped", Message: orderedmap.New[string, any](), Level: "warn"}
+	close(events)
+
+	summary := Summarize(events, 1)
+	assert.Equal(t, 4, summary.Total)
+	assert.Equal(t, 2, summary.Clusters)
+	require.Len(t, summary.Patterns, 1)
+	assert.Equal(t, "order <*> shipped", summary.Patterns[0].Template)
+	assert.Equal(t, 3, summary.Patterns[0].Count)
+	assert.InDelta(t, 75.0, summary.Patterns[0].Percent, 0.001)
+}
@@ -0,0 +1,107 @@
+package patterns
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// DefaultLimit is the number of patterns returned when no limit is given.
+const DefaultLimit = 50
+
+// Pattern is a recurring message template in a summary.
+type Pattern struct {
+	Template  string         `json:"template"`
+	Count     int            `json:"count"`
+	Percent   float64        `json:"percent"`
+	FirstSeen time.Time      `json:"firstSeen"`
+	LastSeen  time.Time      `json:"lastSeen"`
+	Levels    map[string]int `json:"levels"`
+	Example   string         `json:"example"`
+}
+
+// Summary lists the most frequent patterns among Total lines.
+type Summary struct {
+	Total    int       `json:"total"`
+	Clusters int       `json:"clusters"`
+	Patterns []Pattern `json:"patterns"`
+}
+
+// Summarize clusters every event of events and returns the limit most frequent
+// patterns.
+func Summarize(events <-chan *container.LogEvent, limit int) Summary {
+	miner := NewMiner()
+	for event := range events {
+		miner.Add(MessageText(event), event.Level, time.UnixMilli(event.Timestamp))
+	}
+	return miner.Summary(limit)
+}
+
+// Summary returns the limit most frequent patterns mined so far.
+func (m *Miner) Summary(limit int) Summary {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	clusters := m.Clusters()
+	summary := Summary{
+		Total:    m.total,
+		Clusters: len(clusters),
+		Patterns: make([]Pattern, 0, min(limit, len(clusters))),
+	}
+	for _, cluster := range clusters[:min(limit, len(clusters))] {
+		summary.Patterns = append(summary.Patterns, Pattern{
+			Template:  cluster.String(),
+			Count:     cluster.Count,
+			Percent:   float64(cluster.Count) * 100 / float64(m.total),
+			FirstSeen: cluster.FirstSeen.UTC(),
+			LastSeen:  cluster.LastSeen.UTC(),
+			Levels:    cluster.Levels,
+			Example:   cluster.Example,
+		})
+	}
+	return summary
+}
+
+// messageKeys are the structured keys holding the text of a JSON or logfmt line.
+var messageKeys = []string{"message", "msg", "@m"}
+
+// MessageText returns the text of event to mine. Grouped events use their
+// first line, since the rest is usually a stack trace; structured events use
+// their summary or message field, falling back to the raw line.
+func MessageText(event *container.LogEvent) string {
+	if event.Summary != "" {
+		return event.Summary
+	}
+	switch message := event.Message.(type) {
+	case string:
+		return container.StripANSI(message)
+	case []container.LogFragment:
+		if len(message) > 0 {
+			return container.StripANSI(message[0].Message)
+		}
+	case *orderedmap.OrderedMap[string, any]:
+		for _, key := range messageKeys {
+			if value, ok := message.Get(key); ok {
+				if text, ok := value.(string); ok {
+					return text
+				}
+			}
+		}
+		if event.RawMessage != "" {
+			return event.RawMessage
+		}
+		if data, err := json.Marshal(message); err == nil {
+			return string(data)
+		}
+	case *orderedmap.OrderedMap[string, string]:
+		for _, key := range messageKeys {
+			if value, ok := message.Get(key); ok {
+				return value
+			}
+		}
+		return event.RawMessage
+	}
+	return ""
+}
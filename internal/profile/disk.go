@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/amir20/dozzle/internal/views"
 	"github.com/rs/zerolog/log"
 )
 
@@ -44,6 +45,8 @@ type Profile struct {
 	VisibleKeys     []any     `json:"visibleKeys,omitempty"`
 	ReleaseSeen     string    `json:"releaseSeen,omitempty"`
 	CollapsedGroups []string  `json:"collapsedGroups"`
+	// Views are the private saved views of the user.
+	Views []views.View `json:"views,omitempty"`
 }
 
 var dataPath string
@@ -88,6 +91,23 @@ func UpdateFromReader(username string, reader io.Reader) error {
 	return save(username, existingProfile)
 }
 
+// Update loads the profile of username, applies change and saves it. The
+// profile is not saved when change fails.
+func Update(username string, change func(*Profile) error) error {
+	mux.Lock()
+	defer mux.Unlock()
+	existingProfile, err := Load(username)
+	if err != nil && err != errMissingProfileErr {
+		return err
+	}
+
+	if err := change(&existingProfile); err != nil {
+		return err
+	}
+
+	return save(username, existingProfile)
+}
+
 func save(username string, profile Profile) error {
 	path, err := safePath(username)
 	if err != nil {
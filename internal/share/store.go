@@ -0,0 +1,185 @@
+package share
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amir20/dozzle/internal/auth"
+	"github.com/rs/zerolog/log"
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultPath is where share links are stored.
+const DefaultPath = "./data/shares.yml"
+
+var ErrNotFound = errors.New("share link not found")
+
+// Link is a share link to a range of logs of one container. A signed token
+// only grants access while its link is in the store, so deleting a link
+// revokes it.
+type Link struct {
+	ID            string    `json:"id" yaml:"id"`
+	Host          string    `json:"host" yaml:"host"`
+	ContainerID   string    `json:"containerId" yaml:"containerId"`
+	ContainerName string    `json:"containerName" yaml:"containerName"`
+	From          time.Time `json:"from" yaml:"from"`
+	To            time.Time `json:"to" yaml:"to"`
+	Filter        string    `json:"filter,omitempty" yaml:"filter,omitempty"`
+	CreatedBy     string    `json:"createdBy" yaml:"createdBy"`
+	CreatedAt     time.Time `json:"createdAt" yaml:"createdAt"`
+	ExpiresAt     time.Time `json:"expiresAt" yaml:"expiresAt"`
+}
+
+// NewID returns a random ID for a link.
+func NewID() string {
+	return strings.ToLower(rand.Text()[:16])
+}
+
+// Expired reports whether the link has expired at now.
+func (l Link) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// Claims returns what the token of the link grants access to.
+func (l Link) Claims() auth.Share {
+	return auth.Share{
+		ID:          l.ID,
+		Host:        l.Host,
+		ContainerID: l.ContainerID,
+		From:        l.From,
+		To:          l.To,
+		Filter:      l.Filter,
+		ExpiresAt:   l.ExpiresAt,
+	}
+}
+
+// Matches reports whether share was signed for this link.
+func (l Link) Matches(share auth.Share) bool {
+	return share.ID == l.ID &&
+		share.Host == l.Host &&
+		share.ContainerID == l.ContainerID &&
+		share.From.Equal(l.From) &&
+		share.To.Equal(l.To) &&
+		share.Filter == l.Filter
+}
+
+// storeFile is the on-disk format of shares.yml.
+type storeFile struct {
+	Links []Link `yaml:"links"`
+}
+
+// Store holds the share links and writes them to a YAML file on every
+// change. Expired links are dropped on the next change. Safe for concurrent
+// use.
+type Store struct {
+	path  string
+	mu    sync.RWMutex
+	links []Link
+}
+
+// NewStore loads the links at path. A missing file has no links, and an
+// invalid one is logged and replaced on the next change.
+func NewStore(path string) *Store {
+	s := &Store{path: path}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return s
+	}
+	defer file.Close()
+
+	var config storeFile
+	if err := yaml.NewDecoder(file).Decode(&config); err != nil && err != io.EOF {
+		log.Warn().Err(fmt.Errorf("failed to decode share links: %w", err)).Msg("Could not load share links")
+		return s
+	}
+	s.links = config.Links
+	log.Debug().Str("path", path).Int("links", len(s.links)).Msg("Loaded share links")
+	return s
+}
+
+// List returns the links created by user that have not expired.
+func (s *Store) List(user string) []Link {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	links := make([]Link, 0)
+	for _, link := range s.links {
+		if link.CreatedBy == user && !link.Expired(now) {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// Get returns the link with id unless it has expired.
+func (s *Store) Get(id string) (Link, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, link := range s.links {
+		if link.ID == id && !link.Expired(time.Now()) {
+			return link, nil
+		}
+	}
+	return Link{}, ErrNotFound
+}
+
+// Add stores link and saves the file.
+func (s *Store) Add(link Link) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	links := slices.DeleteFunc(slices.Clone(s.links), func(l Link) bool { return l.Expired(now) })
+	links = append(links, link)
+
+	if err := s.save(links); err != nil {
+		return err
+	}
+	s.links = links
+	return nil
+}
+
+// Delete removes the link with id created by user and saves the file.
+func (s *Store) Delete(id string, user string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.links, func(l Link) bool { return l.ID == id && l.CreatedBy == user })
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	links := slices.Delete(slices.Clone(s.links), i, i+1)
+	if err := s.save(links); err != nil {
+		return err
+	}
+	s.links = links
+	return nil
+}
+
+func (s *Store) save(links []Link) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(s.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := yaml.NewEncoder(file)
+	if err := encoder.Encode(storeFile{Links: links}); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,47 @@
+package share
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorePersistsLinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "shares.yml")
+	store := NewStore(path)
+	assert.Empty(t, store.List("alice"))
+
+	now := time.Now().UTC()
+	link := Link{ID: "a", Host: "localhost", ContainerID: "123", From: now.Add(-time.Hour), To: now, CreatedBy: "alice", ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, store.Add(link))
+	require.NoError(t, store.Add(Link{ID: "b", Host: "localhost", ContainerID: "123", CreatedBy: "bob", ExpiresAt: now.Add(time.Hour)}))
+
+	reloaded := NewStore(path)
+	links := reloaded.List("alice")
+	require.Len(t, links, 1)
+	assert.True(t, links[0].Matches(link.Claims()))
+
+	assert.ErrorIs(t, reloaded.Delete("a", "bob"), ErrNotFound, "only the creator can revoke a link")
+	require.NoError(t, reloaded.Delete("a", "alice"))
+	_, err := reloaded.Get("a")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = NewStore(path).Get("b")
+	assert.NoError(t, err)
+}
+
+func TestStoreDropsExpiredLinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shares.yml")
+	store := NewStore(path)
+
+	now := time.Now()
+	require.NoError(t, store.Add(Link{ID: "old", CreatedBy: "alice", ExpiresAt: now.Add(-time.Minute)}))
+	_, err := store.Get("old")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Empty(t, store.List("alice"))
+
+	require.NoError(t, store.Add(Link{ID: "new", CreatedBy: "alice", ExpiresAt: now.Add(time.Hour)}))
+	assert.Len(t, NewStore(path).links, 1)
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/amir20/dozzle/internal/agent"
 	"github.com/amir20/dozzle/internal/cloud"
+	"github.com/amir20/dozzle/internal/container"
 	"github.com/amir20/dozzle/internal/docker"
 	"github.com/amir20/dozzle/internal/notification"
 	"github.com/amir20/dozzle/internal/notification/dispatcher"
@@ -47,6 +48,10 @@ func (h *persistingNotificationHandler) GetNotificationStats() []types.Subscript
 	return h.manager.GetNotificationStats()
 }
 
+func (h *persistingNotificationHandler) Deliveries() []types.NotificationDelivery {
+	return h.manager.Deliveries()
+}
+
 func (h *persistingNotificationHandler) HandleNotificationConfig(subscriptions []types.SubscriptionConfig, dispatchers []types.DispatcherConfig) error {
 	// Update the manager
 	if err := h.manager.HandleNotificationConfig(subscriptions, dispatchers); err != nil {
@@ -141,6 +146,15 @@ func (a *AgentCmd) Run(args Args, embeddedCerts embed.FS) error {
 	}
 	go StartEvent(args, "", client, "agent")
 
+	// Logs are parsed and redacted on the agent, so secrets never leave the host
+	container.LoadParserConfigFile(container.DefaultParserConfigPath)
+	container.LoadRedactionConfigFile(container.DefaultRedactionConfigPath)
+	container.SetDefaultLogRateLimit(container.LogRateLimit{
+		Rate:   args.StreamRateLimit,
+		Burst:  args.StreamRateBurst,
+		Sample: args.StreamSampleRate,
+	})
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
@@ -155,6 +169,7 @@ func (a *AgentCmd) Run(args Args, embeddedCerts embed.FS) error {
 		notification.NewContainerStatsListener(ctx, clients),
 		notification.NewContainerEventListener(ctx, clients),
 	)
+	notificationManager.SetDeliveryLog(notification.NewDeliveryLog(notification.DefaultDeliveryLogPath))
 
 	// Start first so matcher is available for LoadConfig
 	if err := notificationManager.Start(); err != nil {
@@ -33,6 +33,9 @@ type Args struct {
 	RemoteHost       []string            `arg:"env:DOZZLE_REMOTE_HOST,--remote-host,separate" help:"list of hosts to connect remotely"`
 	RemoteAgent      []string            `arg:"env:DOZZLE_REMOTE_AGENT,--remote-agent,separate" help:"list of agents to connect remotely"`
 	NoAnalytics      bool                `arg:"--no-analytics,env:DOZZLE_NO_ANALYTICS" help:"disables anonymous analytics"`
+	StreamRateLimit  float64             `arg:"--stream-rate-limit,env:DOZZLE_STREAM_RATE_LIMIT" default:"0" help:"limits live log lines per second for each container. Errors are never dropped. 0 disables the limit."`
+	StreamRateBurst  int                 `arg:"--stream-rate-burst,env:DOZZLE_STREAM_RATE_BURST" default:"0" help:"sets how many lines a container can send at once before it is limited. Defaults to the rate limit."`
+	StreamSampleRate int                 `arg:"--stream-sample-rate,env:DOZZLE_STREAM_SAMPLE_RATE" default:"0" help:"keeps one in every N lines over the rate limit instead of dropping all of them."`
 	Mode             string              `arg:"env:DOZZLE_MODE" default:"server" help:"sets the mode to run in (server, swarm)"`
 	TimeoutString    string              `arg:"--timeout,env:DOZZLE_TIMEOUT" default:"10s" help:"sets the timeout for docker client"`
 	Timeout          time.Duration       `arg:"-"`
@@ -99,3 +99,7 @@ func (a *agentService) UpdateCloudConfig(ctx context.Context, cloudConfig *types
 func (a *agentService) GetNotificationStats(ctx context.Context) ([]types.SubscriptionStats, error) {
 	return a.client.GetNotificationStats(ctx)
 }
+
+func (a *agentService) GetNotificationDeliveries(ctx context.Context) ([]types.NotificationDelivery, error) {
+	return a.client.GetNotificationDeliveries(ctx)
+}
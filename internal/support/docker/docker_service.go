@@ -40,8 +40,8 @@ func NewDockerClientService(client DockerUpdateClient, labels container.Containe
 	}
 }
 
-func (d *DockerClientService) RawLogs(ctx context.Context, container container.Container, from time.Time, to time.Time, stdTypes container.StdType) (io.ReadCloser, error) {
-	reader, err := d.client.ContainerLogsBetweenDates(ctx, container.ID, from, to, stdTypes)
+func (d *DockerClientService) RawLogs(ctx context.Context, c container.Container, from time.Time, to time.Time, stdTypes container.StdType) (io.ReadCloser, error) {
+	reader, err := d.client.ContainerLogsBetweenDates(ctx, c.ID, from, to, stdTypes)
 	if err != nil {
 		return nil, err
 	}
@@ -49,21 +49,23 @@ func (d *DockerClientService) RawLogs(ctx context.Context, container container.C
 	in, out := io.Pipe()
 
 	go func() {
-		if container.Tty {
+		if c.Tty {
 			if _, err := io.Copy(out, reader); err != nil {
-				log.Error().Err(err).Msgf("error copying logs for container %s", container.ID)
+				log.Error().Err(err).Msgf("error copying logs for container %s", c.ID)
 			}
 		} else {
 			if _, err := stdcopy.StdCopy(out, out, reader); err != nil {
-				log.Error().Err(err).Msgf("error copying logs for container %s", container.ID)
+				log.Error().Err(err).Msgf("error copying logs for container %s", c.ID)
 			}
 		}
 
 		out.Close()
 	}()
 
+	if redactor := container.RedactorForContainer(c); redactor != nil {
+		return container.NewRedactingReader(in, redactor), nil
+	}
 	return in, nil
-
 }
 
 func (d *DockerClientService) LogsBetweenDates(ctx context.Context, c container.Container, from time.Time, to time.Time, stdTypes container.StdType) (<-chan *container.LogEvent, error) {
@@ -3,15 +3,19 @@ package docker_support
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/amir20/dozzle/internal/archive"
 	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/history"
 	"github.com/amir20/dozzle/internal/migration"
 	"github.com/amir20/dozzle/internal/notification"
 	"github.com/amir20/dozzle/internal/notification/dispatcher"
 	container_support "github.com/amir20/dozzle/internal/support/container"
+	"github.com/amir20/dozzle/internal/timeline"
 	"github.com/amir20/dozzle/types"
 	"github.com/rs/zerolog/log"
 	lop "github.com/samber/lo/parallel"
@@ -42,6 +46,9 @@ type MultiHostService struct {
 	notificationManager *notification.Manager
 	persister           *notification.Persister
 	cloudNotifyFn       atomic.Pointer[func()]
+	archive             *archive.Archive
+	history             *history.History
+	timeline            *timeline.Timeline
 }
 
 func NewMultiHostService(manager ClientManager, timeout time.Duration) *MultiHostService {
@@ -56,18 +63,51 @@ func NewMultiHostService(manager ClientManager, timeout time.Duration) *MultiHos
 func (m *MultiHostService) FindContainer(host string, id string, labels container.ContainerLabels) (*container_support.ContainerService, error) {
 	client, ok := m.manager.Find(host)
 	if !ok {
+		if archived, err := m.findArchived(host, id, labels); err == nil {
+			return archived, nil
+		}
 		return nil, fmt.Errorf("host %s not found", host)
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
 	defer cancel()
 	container, err := client.FindContainer(ctx, id, labels)
 	if err != nil {
+		if archived, archiveErr := m.findArchived(host, id, labels); archiveErr == nil {
+			return archived, nil
+		}
 		return nil, err
 	}
 
 	return container_support.NewContainerService(client, container), nil
 }
 
+// findArchived falls back to the archived logs of a removed container.
+func (m *MultiHostService) findArchived(host string, id string, labels container.ContainerLabels) (*container_support.ContainerService, error) {
+	if m.archive == nil {
+		return nil, container.ErrContainerNotFound
+	}
+	return m.archive.FindContainer(host, id, labels)
+}
+
+// ListArchivedContainers returns the archived containers that have been
+// removed since.
+func (m *MultiHostService) ListArchivedContainers(labels container.ContainerLabels) []container.Container {
+	if m.archive == nil {
+		return []container.Container{}
+	}
+
+	return slices.DeleteFunc(m.archive.Containers(labels), func(c container.Container) bool {
+		client, ok := m.manager.Find(c.Host)
+		if !ok {
+			return false
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+		defer cancel()
+		_, err := client.FindContainer(ctx, c.ID, nil)
+		return err == nil
+	})
+}
+
 func (m *MultiHostService) ListContainersForHost(host string, labels container.ContainerLabels) ([]container.Container, error) {
 	client, ok := m.manager.Find(host)
 	if !ok {
@@ -192,6 +232,96 @@ func (m *MultiHostService) TotalClients() int {
 	return len(m.manager.List())
 }
 
+// SearchArchive finds archived lines with the search index.
+func (m *MultiHostService) SearchArchive(ctx context.Context, query archive.Query) (archive.SearchResult, error) {
+	if m.archive == nil {
+		return archive.SearchResult{}, archive.ErrIndexDisabled
+	}
+	return m.archive.Search(ctx, query)
+}
+
+// StartArchive begins archiving the logs of matching containers when
+// archive.yml exists.
+func (m *MultiHostService) StartArchive(ctx context.Context) error {
+	config := archive.LoadConfigFile(archive.DefaultConfigPath)
+	if config == nil {
+		return nil
+	}
+
+	a, err := archive.Start(ctx, config, archive.DefaultDirectory, archive.DefaultIndexDirectory, m.manager.LocalClientServices())
+	if err != nil {
+		return err
+	}
+	m.archive = a
+	return nil
+}
+
+// StatsHistory returns the recorded stats of a container between from and to.
+func (m *MultiHostService) StatsHistory(host string, id string, from time.Time, to time.Time, step time.Duration) ([]history.Sample, time.Duration, error) {
+	if m.history == nil {
+		return nil, 0, history.ErrDisabled
+	}
+	if !m.recorded(host) {
+		return nil, 0, history.ErrNotRecorded
+	}
+	return m.history.Query(host, id, from, to, step)
+}
+
+// StartStatsHistory begins recording the stats of all containers when
+// stats.yml exists.
+func (m *MultiHostService) StartStatsHistory(ctx context.Context) error {
+	config := history.LoadConfigFile(history.DefaultConfigPath)
+	if config == nil {
+		return nil
+	}
+
+	h, err := history.Start(ctx, config, history.DefaultDirectory, m.manager.LocalClientServices())
+	if err != nil {
+		return err
+	}
+	m.history = h
+	return nil
+}
+
+// ContainerTimeline returns the recorded lifecycle events matching query.
+func (m *MultiHostService) ContainerTimeline(query timeline.Query) (timeline.Result, error) {
+	if m.timeline == nil {
+		return timeline.Result{}, timeline.ErrDisabled
+	}
+	if !m.recorded(query.Host) {
+		return timeline.Result{}, timeline.ErrNotRecorded
+	}
+	return m.timeline.Query(query)
+}
+
+// recorded reports whether the stats history and the timeline record the
+// containers of host. They only follow the local clients, so agent hosts are
+// left out.
+func (m *MultiHostService) recorded(host string) bool {
+	client, ok := m.manager.Find(host)
+	if !ok {
+		return true
+	}
+	_, local := client.(*DockerClientService)
+	return local
+}
+
+// StartTimeline begins recording the lifecycle events of all containers when
+// timeline.yml exists.
+func (m *MultiHostService) StartTimeline(ctx context.Context) error {
+	config := timeline.LoadConfigFile(timeline.DefaultConfigPath)
+	if config == nil {
+		return nil
+	}
+
+	t, err := timeline.Start(ctx, config, timeline.DefaultDirectory, m.manager.LocalClientServices())
+	if err != nil {
+		return err
+	}
+	m.timeline = t
+	return nil
+}
+
 // StartNotificationManager initializes and starts the notification manager
 func (m *MultiHostService) StartNotificationManager(ctx context.Context) error {
 	clients := m.manager.LocalClientServices()
@@ -199,6 +329,7 @@ func (m *MultiHostService) StartNotificationManager(ctx context.Context) error {
 	statsListener := notification.NewContainerStatsListener(ctx, clients)
 	eventListener := notification.NewContainerEventListener(ctx, clients)
 	m.notificationManager = notification.NewManager(listener, statsListener, eventListener)
+	m.notificationManager.SetDeliveryLog(notification.NewDeliveryLog(notification.DefaultDeliveryLogPath))
 	m.persister = &notification.Persister{
 		Manager:          m.notificationManager,
 		NotificationPath: notification.DefaultNotificationConfigPath,
@@ -300,6 +431,8 @@ func (m *MultiHostService) broadcastNotificationConfig() {
 			EventExpression:     sub.EventExpression,
 			Cooldown:            sub.Cooldown,
 			SampleWindow:        sub.SampleWindow,
+			GroupBy:             sub.GroupBy,
+			DigestWindow:        sub.DigestWindow,
 		}
 	}
 
@@ -310,12 +443,26 @@ func (m *MultiHostService) broadcastNotificationConfig() {
 			continue
 		}
 		dispatchers = append(dispatchers, types.DispatcherConfig{
-			ID:       d.ID,
-			Name:     d.Name,
-			Type:     d.Type,
-			URL:      d.URL,
-			Template: d.Template,
-			Headers:  d.Headers,
+			ID:           d.ID,
+			Name:         d.Name,
+			Type:         d.Type,
+			URL:          d.URL,
+			Template:     d.Template,
+			Headers:      d.Headers,
+			Channel:      d.Channel,
+			Topic:        d.Topic,
+			Priority:     d.Priority,
+			Token:        d.Token,
+			User:         d.User,
+			DozzleURL:    d.DozzleURL,
+			SMTPHost:     d.SMTPHost,
+			SMTPPort:     d.SMTPPort,
+			SMTPSecurity: d.SMTPSecurity,
+			SMTPUsername: d.SMTPUsername,
+			SMTPPassword: d.SMTPPassword,
+			From:         d.From,
+			To:           d.To,
+			Subject:      d.Subject,
 		})
 	}
 
@@ -493,6 +640,16 @@ func (m *MultiHostService) Dispatchers() []notification.DispatcherConfig {
 	return m.notificationManager.Dispatchers()
 }
 
+// SendTestNotification sends a test notification through the notification manager
+func (m *MultiHostService) SendTestNotification(ctx context.Context, tester dispatcher.Tester, n types.Notification) dispatcher.TestResult {
+	return m.notificationManager.SendTest(ctx, tester, n)
+}
+
+// NotificationDeliveries returns the deliveries of notifications sent by this host, newest first
+func (m *MultiHostService) NotificationDeliveries() []types.NotificationDelivery {
+	return m.notificationManager.Deliveries()
+}
+
 // NotificationStatsProvider is an interface for clients that can report notification stats
 type NotificationStatsProvider interface {
 	GetNotificationStats(ctx context.Context) ([]types.SubscriptionStats, error)
@@ -545,6 +702,7 @@ func (m *MultiHostService) FetchAgentNotificationStats() map[int]types.Subscript
 			}
 
 			existing.TriggerCount += s.TriggerCount
+			existing.FiringAlerts = append(existing.FiringAlerts, s.FiringAlerts...)
 
 			if s.LastTriggeredAt != nil && (existing.LastTriggeredAt == nil || s.LastTriggeredAt.After(*existing.LastTriggeredAt)) {
 				existing.LastTriggeredAt = s.LastTriggeredAt
@@ -567,3 +725,39 @@ func (m *MultiHostService) FetchAgentNotificationStats() map[int]types.Subscript
 
 	return aggregated
 }
+
+// NotificationDeliveriesProvider is an interface for clients that can report notification deliveries
+type NotificationDeliveriesProvider interface {
+	GetNotificationDeliveries(ctx context.Context) ([]types.NotificationDelivery, error)
+}
+
+// FetchAgentNotificationDeliveries fetches the deliveries of notifications sent by all agent clients
+func (m *MultiHostService) FetchAgentNotificationDeliveries() []types.NotificationDelivery {
+	var providers []NotificationDeliveriesProvider
+	for _, client := range m.manager.List() {
+		if provider, ok := client.(NotificationDeliveriesProvider); ok {
+			providers = append(providers, provider)
+		}
+	}
+
+	if len(providers) == 0 {
+		return nil
+	}
+
+	allDeliveries := lop.Map(providers, func(provider NotificationDeliveriesProvider, _ int) []types.NotificationDelivery {
+		ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+		defer cancel()
+		deliveries, err := provider.GetNotificationDeliveries(ctx)
+		if err != nil {
+			log.Debug().Err(err).Msg("Failed to fetch notification deliveries from agent")
+			return nil
+		}
+		return deliveries
+	})
+
+	var deliveries []types.NotificationDelivery
+	for _, d := range allDeliveries {
+		deliveries = append(deliveries, d...)
+	}
+	return deliveries
+}
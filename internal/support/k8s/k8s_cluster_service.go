@@ -3,14 +3,18 @@ package k8s_support
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/amir20/dozzle/internal/archive"
 	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/history"
 	"github.com/amir20/dozzle/internal/k8s"
 	"github.com/amir20/dozzle/internal/migration"
 	"github.com/amir20/dozzle/internal/notification"
 	"github.com/amir20/dozzle/internal/notification/dispatcher"
 	container_support "github.com/amir20/dozzle/internal/support/container"
+	"github.com/amir20/dozzle/internal/timeline"
 	"github.com/amir20/dozzle/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -21,6 +25,9 @@ type K8sClusterService struct {
 	hosts               []container.Host
 	notificationManager *notification.Manager
 	persister           *notification.Persister
+	archive             *archive.Archive
+	history             *history.History
+	timeline            *timeline.Timeline
 }
 
 func NewK8sClusterService(client *k8s.K8sClient, timeout time.Duration) (*K8sClusterService, error) {
@@ -56,12 +63,102 @@ func NewK8sClusterService(client *k8s.K8sClient, timeout time.Duration) (*K8sClu
 func (m *K8sClusterService) FindContainer(host string, id string, labels container.ContainerLabels) (*container_support.ContainerService, error) {
 	container, err := m.client.FindContainer(context.Background(), id, labels)
 	if err != nil {
+		if m.archive != nil {
+			if archived, archiveErr := m.archive.FindContainer(host, id, labels); archiveErr == nil {
+				return archived, nil
+			}
+		}
 		return nil, err
 	}
 
 	return container_support.NewContainerService(m.client, container), nil
 }
 
+// ListArchivedContainers returns the archived containers that have been
+// removed since.
+func (m *K8sClusterService) ListArchivedContainers(labels container.ContainerLabels) []container.Container {
+	if m.archive == nil {
+		return []container.Container{}
+	}
+
+	return slices.DeleteFunc(m.archive.Containers(labels), func(c container.Container) bool {
+		_, err := m.client.FindContainer(context.Background(), c.ID, nil)
+		return err == nil
+	})
+}
+
+// SearchArchive finds archived lines with the search index.
+func (m *K8sClusterService) SearchArchive(ctx context.Context, query archive.Query) (archive.SearchResult, error) {
+	if m.archive == nil {
+		return archive.SearchResult{}, archive.ErrIndexDisabled
+	}
+	return m.archive.Search(ctx, query)
+}
+
+// StartArchive begins archiving the logs of matching containers when
+// archive.yml exists.
+func (m *K8sClusterService) StartArchive(ctx context.Context) error {
+	config := archive.LoadConfigFile(archive.DefaultConfigPath)
+	if config == nil {
+		return nil
+	}
+
+	a, err := archive.Start(ctx, config, archive.DefaultDirectory, archive.DefaultIndexDirectory, m.LocalClientServices())
+	if err != nil {
+		return err
+	}
+	m.archive = a
+	return nil
+}
+
+// StatsHistory returns the recorded stats of a container between from and to.
+func (m *K8sClusterService) StatsHistory(host string, id string, from time.Time, to time.Time, step time.Duration) ([]history.Sample, time.Duration, error) {
+	if m.history == nil {
+		return nil, 0, history.ErrDisabled
+	}
+	return m.history.Query(host, id, from, to, step)
+}
+
+// StartStatsHistory begins recording the stats of all containers when
+// stats.yml exists.
+func (m *K8sClusterService) StartStatsHistory(ctx context.Context) error {
+	config := history.LoadConfigFile(history.DefaultConfigPath)
+	if config == nil {
+		return nil
+	}
+
+	h, err := history.Start(ctx, config, history.DefaultDirectory, m.LocalClientServices())
+	if err != nil {
+		return err
+	}
+	m.history = h
+	return nil
+}
+
+// ContainerTimeline returns the recorded lifecycle events matching query.
+func (m *K8sClusterService) ContainerTimeline(query timeline.Query) (timeline.Result, error) {
+	if m.timeline == nil {
+		return timeline.Result{}, timeline.ErrDisabled
+	}
+	return m.timeline.Query(query)
+}
+
+// StartTimeline begins recording the lifecycle events of all containers when
+// timeline.yml exists.
+func (m *K8sClusterService) StartTimeline(ctx context.Context) error {
+	config := timeline.LoadConfigFile(timeline.DefaultConfigPath)
+	if config == nil {
+		return nil
+	}
+
+	t, err := timeline.Start(ctx, config, timeline.DefaultDirectory, m.LocalClientServices())
+	if err != nil {
+		return err
+	}
+	m.timeline = t
+	return nil
+}
+
 func (m *K8sClusterService) ListContainersForHost(host string, labels container.ContainerLabels) ([]container.Container, error) {
 	containers, err := m.client.ListContainers(context.Background(), labels)
 	if err != nil {
@@ -149,6 +246,7 @@ func (m *K8sClusterService) StartNotificationManager(ctx context.Context) error
 	statsListener := notification.NewContainerStatsListener(ctx, clients)
 	eventListener := notification.NewContainerEventListener(ctx, clients)
 	m.notificationManager = notification.NewManager(listener, statsListener, eventListener)
+	m.notificationManager.SetDeliveryLog(notification.NewDeliveryLog(notification.DefaultDeliveryLogPath))
 	m.persister = &notification.Persister{
 		Manager:          m.notificationManager,
 		NotificationPath: notification.DefaultNotificationConfigPath,
@@ -223,6 +321,18 @@ func (m *K8sClusterService) FetchAgentNotificationStats() map[int]types.Subscrip
 	return nil
 }
 
+func (m *K8sClusterService) SendTestNotification(ctx context.Context, tester dispatcher.Tester, n types.Notification) dispatcher.TestResult {
+	return m.notificationManager.SendTest(ctx, tester, n)
+}
+
+func (m *K8sClusterService) NotificationDeliveries() []types.NotificationDelivery {
+	return m.notificationManager.Deliveries()
+}
+
+func (m *K8sClusterService) FetchAgentNotificationDeliveries() []types.NotificationDelivery {
+	return nil
+}
+
 func (m *K8sClusterService) CloudConfig() *notification.CloudConfig {
 	return m.persister.CloudConfig()
 }
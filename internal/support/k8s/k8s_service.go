@@ -57,8 +57,16 @@ func (k *K8sClientService) LogsBetweenDates(ctx context.Context, c container.Con
 	return g.Events, nil
 }
 
-func (k *K8sClientService) RawLogs(ctx context.Context, container container.Container, from time.Time, to time.Time, stdTypes container.StdType) (io.ReadCloser, error) {
-	return k.client.ContainerLogsBetweenDates(ctx, container.ID, from, to, stdTypes)
+func (k *K8sClientService) RawLogs(ctx context.Context, c container.Container, from time.Time, to time.Time, stdTypes container.StdType) (io.ReadCloser, error) {
+	reader, err := k.client.ContainerLogsBetweenDates(ctx, c.ID, from, to, stdTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	if redactor := container.RedactorForContainer(c); redactor != nil {
+		return container.NewRedactingReader(reader, redactor), nil
+	}
+	return reader, nil
 }
 
 func (k *K8sClientService) StreamLogs(ctx context.Context, c container.Container, from time.Time, stdTypes container.StdType, events chan<- *container.LogEvent) error {
@@ -0,0 +1,88 @@
+// Package store_support loads the config of the optional stores Dozzle keeps
+// under ./data and reads their JSON lines files. A store is enabled by the
+// presence of its <name>.yml, which sets how long the store keeps its data
+// with maxAge next to the fields of the feature.
+package store_support
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.yaml.in/yaml/v3"
+)
+
+// Config holds the fields shared by the store configs. Embed it inline:
+//
+//	store_support.Config `yaml:",inline"`
+type Config struct {
+	MaxAge string `yaml:"maxAge,omitempty"`
+
+	maxAge time.Duration
+}
+
+// Retention is how long the store keeps its data.
+func (c *Config) Retention() time.Duration {
+	return c.maxAge
+}
+
+func (c *Config) storeConfig() *Config {
+	return c
+}
+
+// StoreConfig is implemented by the configs that embed Config.
+type StoreConfig interface {
+	storeConfig() *Config
+}
+
+// Spec describes the config file of a store.
+type Spec struct {
+	// Name is the name of the store in errors and logs, e.g. stats for stats.yml.
+	Name          string
+	DefaultMaxAge time.Duration
+	// MinMaxAge rejects a shorter maxAge when set. maxAge has to be positive
+	// either way.
+	MinMaxAge time.Duration
+}
+
+// Decode parses the config in r into config and validates its maxAge. An
+// empty file keeps the defaults.
+func (s Spec) Decode(r io.Reader, config StoreConfig) error {
+	if err := yaml.NewDecoder(r).Decode(config); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to decode %s config: %w", s.Name, err)
+	}
+
+	c := config.storeConfig()
+	c.maxAge = s.DefaultMaxAge
+	if c.MaxAge != "" {
+		maxAge, err := time.ParseDuration(c.MaxAge)
+		switch {
+		case err != nil || maxAge <= 0:
+			return fmt.Errorf("invalid maxAge %q", c.MaxAge)
+		case maxAge < s.MinMaxAge:
+			return fmt.Errorf("invalid maxAge %q: must be at least %s", c.MaxAge, s.MinMaxAge)
+		}
+		c.maxAge = maxAge
+	}
+	return nil
+}
+
+// LoadFile reads the config at path with load. It returns nil when the file
+// does not exist or is invalid, which leaves the store disabled.
+func LoadFile[T any](path string, name string, load func(io.Reader) (*T, error)) *T {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	config, err := load(file)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not load %s config", name)
+		return nil
+	}
+	log.Debug().Str("path", path).Msgf("Loaded %s config", name)
+	return config
+}
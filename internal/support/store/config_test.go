@@ -0,0 +1,59 @@
+package store_support
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Config `yaml:",inline"`
+	Extra  string `yaml:"extra,omitempty"`
+}
+
+var testSpec = Spec{Name: "test", DefaultMaxAge: 24 * time.Hour, MinMaxAge: time.Hour}
+
+func loadTestConfig(r io.Reader) (*testConfig, error) {
+	var config testConfig
+	if err := testSpec.Decode(r, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func TestSpec_Decode(t *testing.T) {
+	config, err := loadTestConfig(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, config.Retention())
+
+	config, err = loadTestConfig(strings.NewReader("maxAge: 48h\nextra: value\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 48*time.Hour, config.Retention())
+	assert.Equal(t, "value", config.Extra)
+
+	for _, value := range []string{"maxAge: soon\n", "maxAge: -1h\n", "maxAge: 10m\n", "maxAge: [1h]\n"} {
+		_, err := loadTestConfig(strings.NewReader(value))
+		assert.Error(t, err, value)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, LoadFile(filepath.Join(dir, "test.yml"), "test", loadTestConfig), "missing file")
+
+	invalid := filepath.Join(dir, "invalid.yml")
+	require.NoError(t, os.WriteFile(invalid, []byte("maxAge: soon\n"), 0644))
+	assert.Nil(t, LoadFile(invalid, "test", loadTestConfig), "invalid file")
+
+	valid := filepath.Join(dir, "valid.yml")
+	require.NoError(t, os.WriteFile(valid, []byte("maxAge: 2h\n"), 0644))
+	config := LoadFile(valid, "test", loadTestConfig)
+	require.NotNil(t, config)
+	assert.Equal(t, 2*time.Hour, config.Retention())
+}
@@ -0,0 +1,25 @@
+package store_support
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// ReadLines decodes every line of the JSON lines in r as a T and passes it to
+// fn. A line that doesn't decode is skipped, since a partial line is left
+// behind when Dozzle is killed mid-write. It returns the number of lines read,
+// skipped ones included.
+func ReadLines[T any](r io.Reader, fn func(T)) (int, error) {
+	lines := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines++
+		var value T
+		if err := json.Unmarshal(scanner.Bytes(), &value); err != nil {
+			continue
+		}
+		fn(value)
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,23 @@
+package store_support
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLines(t *testing.T) {
+	type entry struct {
+		Name string `json:"name"`
+	}
+
+	var names []string
+	lines, err := ReadLines(strings.NewReader("{\"name\":\"start\"}\n{\"name\":\"stop\"}\n{\"na"), func(e entry) {
+		names = append(names, e.Name)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, lines)
+	assert.Equal(t, []string{"start", "stop"}, names, "the partial last line is skipped")
+}
@@ -0,0 +1,127 @@
+package support_web
+
+import (
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/utils"
+)
+
+// MaxContextLines bounds the before and after params of the log endpoints.
+const MaxContextLines = 100
+
+// ContextLines adds the lines around matching lines, like grep -B and -A.
+// Lines are tracked per container so interleaved streams keep their own
+// context.
+type ContextLines struct {
+	before  int
+	after   int
+	windows map[string]*contextWindow
+}
+
+type contextWindow struct {
+	previous *utils.RingBuffer[*container.LogEvent]
+	pending  int
+}
+
+// NewContextLines keeps before lines ahead of each match and after lines
+// following it.
+func NewContextLines(before int, after int) *ContextLines {
+	return &ContextLines{before: before, after: after, windows: make(map[string]*contextWindow)}
+}
+
+// Next returns the lines to send for event in order. A match is preceded by
+// the unsent lines before it, and a line that did not match is sent while
+// lines after a match are due. Lines sent as context have Context set.
+func (c *ContextLines) Next(event *container.LogEvent, matched bool) []*container.LogEvent {
+	if c.before == 0 && c.after == 0 {
+		if matched {
+			return []*container.LogEvent{event}
+		}
+		return nil
+	}
+
+	window, ok := c.windows[event.ContainerID]
+	if !ok {
+		window = &contextWindow{previous: utils.NewRingBuffer[*container.LogEvent](c.before)}
+		c.windows[event.ContainerID] = window
+	}
+
+	if matched {
+		lines := make([]*container.LogEvent, 0, window.previous.Len()+1)
+		for _, previous := range window.previous.Data() {
+			previous.Context = true
+			lines = append(lines, previous)
+		}
+		window.previous.Clear()
+		window.pending = c.after
+		return append(lines, event)
+	}
+
+	if window.pending > 0 {
+		window.pending--
+		event.Context = true
+		return []*container.LogEvent{event}
+	}
+
+	window.previous.Push(event)
+	return nil
+}
+
+// ContextBuffer keeps the last lines holding at most Size matches. Context
+// lines do not count toward Size, so asking for context never returns fewer
+// matches; they are dropped along with their match.
+type ContextBuffer struct {
+	Size   int
+	lines  []*container.LogEvent
+	starts []int // index of the first line of every match and its context
+
+	truncated bool
+}
+
+// NewContextBuffer keeps up to size matches and their context lines.
+func NewContextBuffer(size int) *ContextBuffer {
+	return &ContextBuffer{Size: size}
+}
+
+// Push adds the lines returned by ContextLines.Next for one event. Lines
+// ending with a match start a new match, others are the context after the
+// last one. The oldest match is dropped once more than Size are kept.
+func (b *ContextBuffer) Push(lines ...*container.LogEvent) {
+	if b.Size <= 0 || len(lines) == 0 {
+		return
+	}
+	if !lines[len(lines)-1].Context {
+		b.starts = append(b.starts, len(b.lines))
+		if len(b.starts) > b.Size {
+			drop := b.starts[1]
+			b.lines = b.lines[drop:]
+			b.starts = b.starts[1:]
+			for n := range b.starts {
+				b.starts[n] -= drop
+			}
+			b.truncated = true
+		}
+	}
+	b.lines = append(b.lines, lines...)
+}
+
+// Matches returns the number of matches kept.
+func (b *ContextBuffer) Matches() int {
+	return len(b.starts)
+}
+
+// Truncated reports whether older matches were dropped.
+func (b *ContextBuffer) Truncated() bool {
+	return b.truncated
+}
+
+// Clear drops every line.
+func (b *ContextBuffer) Clear() {
+	b.lines = nil
+	b.starts = nil
+	b.truncated = false
+}
+
+// Data returns the kept lines in order.
+func (b *ContextBuffer) Data() []*container.LogEvent {
+	return b.lines
+}
@@ -0,0 +1,86 @@
+package support_web
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/amir20/dozzle/internal/container"
+)
+
+func TestContextLines(t *testing.T) {
+	lines := NewContextLines(2, 1)
+
+	var got []string
+	for i, message := range []string{"a", "b", "c", "ERR1", "d", "e", "ERR2", "ERR3", "f", "g"} {
+		event := &container.LogEvent{Message: message, Id: uint32(i), ContainerID: "one"}
+		for _, line := range lines.Next(event, len(message) > 1) {
+			if line.Context {
+				got = append(got, "-"+line.Message.(string))
+			} else {
+				got = append(got, line.Message.(string))
+			}
+		}
+	}
+
+	want := []string{"-b", "-c", "ERR1", "-d", "-e", "ERR2", "ERR3", "-f"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestContextLinesPerContainer(t *testing.T) {
+	lines := NewContextLines(1, 0)
+
+	lines.Next(&container.LogEvent{Message: "one before", ContainerID: "one"}, false)
+	lines.Next(&container.LogEvent{Message: "two before", ContainerID: "two"}, false)
+	got := lines.Next(&container.LogEvent{Message: "one match", ContainerID: "one"}, true)
+
+	if len(got) != 2 || got[0].Message != "one before" || !got[0].Context || got[1].Context {
+		t.Errorf("unexpected lines %+v", got)
+	}
+}
+
+func TestContextLinesDisabled(t *testing.T) {
+	lines := NewContextLines(0, 0)
+	event := &container.LogEvent{Message: "line"}
+
+	if got := lines.Next(event, false); len(got) != 0 {
+		t.Errorf("expected no lines, got %+v", got)
+	}
+	if got := lines.Next(event, true); len(got) != 1 || got[0].Context {
+		t.Errorf("expected only the match, got %+v", got)
+	}
+}
+
+func TestContextBuffer(t *testing.T) {
+	buffer := NewContextBuffer(2)
+	lines := NewContextLines(1, 1)
+	for _, message := range []string{"a", "ERR1", "b", "c", "ERR2", "d", "ERR3", "e"} {
+		event := &container.LogEvent{Message: message, ContainerID: "one"}
+		buffer.Push(lines.Next(event, len(message) > 1)...)
+	}
+
+	var got []string
+	for _, line := range buffer.Data() {
+		if line.Context {
+			got = append(got, "-"+line.Message.(string))
+		} else {
+			got = append(got, line.Message.(string))
+		}
+	}
+	want := []string{"-c", "ERR2", "-d", "ERR3", "-e"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if buffer.Matches() != 2 {
+		t.Errorf("Matches() = %d, want 2", buffer.Matches())
+	}
+	if !buffer.Truncated() {
+		t.Error("Truncated() = false after dropping ERR1")
+	}
+
+	buffer.Clear()
+	if len(buffer.Data()) != 0 || buffer.Matches() != 0 || buffer.Truncated() {
+		t.Errorf("Clear() kept %v", buffer.Data())
+	}
+}
@@ -0,0 +1,45 @@
+package support_web
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/amir20/dozzle/internal/container"
+)
+
+// QueryPrefix marks a filter written in the query syntax. A leading ? is not
+// a valid regular expression, so no regex filter changes meaning.
+const QueryPrefix = "?"
+
+// Filter matches log events against the filter param of the log endpoints.
+// Filters starting with QueryPrefix are compiled to a Query, anything else is
+// a regular expression.
+type Filter struct {
+	regex *regexp.Regexp
+	query *Query
+}
+
+// ParseFilter compiles filter once for all events.
+func ParseFilter(filter string) (*Filter, error) {
+	if query, ok := strings.CutPrefix(filter, QueryPrefix); ok {
+		compiled, err := ParseQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		return &Filter{query: compiled}, nil
+	}
+
+	regex, err := ParseRegex(filter)
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{regex: regex}, nil
+}
+
+// Match reports whether event matches and highlights the matched text.
+func (f *Filter) Match(event *container.LogEvent) bool {
+	if f.query != nil {
+		return f.query.Match(event)
+	}
+	return Search(f.regex, event)
+}
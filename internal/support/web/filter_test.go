@@ -0,0 +1,51 @@
+package support_web
+
+import (
+	"testing"
+
+	"github.com/amir20/dozzle/internal/container"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		message string
+		level   string
+		want    bool
+	}{
+		// without the prefix a filter stays a regular expression whatever it looks like
+		{"uppercase words", "404 NOT FOUND", "GET /x 404 NOT FOUND", "info", true},
+		{"uppercase words do not negate", "404 NOT FOUND", "GET /x 404", "info", false},
+		{"colon", "Exception:.*", "Exception: boom", "error", true},
+		{"field-like", "level:error", "level:error in message", "info", true},
+		{"field-like does not read the level", "level:error", "boom", "error", false},
+		{"query", "?level:error", "boom", "error", true},
+		{"query not matching", "?level:error", "boom", "info", false},
+		{"query with text", "?boom AND level>=warn", "boom", "error", true},
+		{"query with text not matching", "?crash AND level>=warn", "boom", "error", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("ParseFilter(%q) error = %v", tt.filter, err)
+			}
+			event := &container.LogEvent{Type: container.LogTypeSingle, Message: tt.message, Level: tt.level}
+			if got := filter.Match(event); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.message, got, tt.want)
+			}
+		})
+	}
+
+	for _, filter := range []string{"(boom", "?status>=many", "?"} {
+		if _, err := ParseFilter(filter); err == nil {
+			t.Errorf("ParseFilter(%q) should fail", filter)
+		}
+	}
+	// the prefix can never start a regex filter
+	if _, err := ParseRegex("?level:error"); err == nil {
+		t.Error("ParseRegex(?level:error) should fail")
+	}
+}
@@ -0,0 +1,496 @@
+package support_web
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/amir20/dozzle/internal/container"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// Query is a compiled structured filter such as
+//
+//	status>=500 AND path:"/api/*" OR NOT level>=warn
+//
+// Terms are words or regular expressions, quoted phrases, field:value with *
+// wildcards and field>N, field>=N, field<N or field<=N on the fields of JSON
+// and logfmt logs. level and stream are fields of every log, and levels
+// compare by severity. Terms next to each other are ANDed, AND binds tighter
+// than OR, and parentheses group.
+type Query struct {
+	root node
+	// text holds the regexes of the text terms outside of NOT, which are
+	// highlighted once a log matches.
+	text []*regexp.Regexp
+}
+
+// levelRanks orders the canonical levels by severity.
+var levelRanks = map[string]int{
+	"trace": 0,
+	"debug": 1,
+	"info":  2,
+	"warn":  3,
+	"error": 4,
+	"fatal": 5,
+}
+
+// fieldTerm splits field:value and field>=N terms. Values starting with / are
+// left to text search so URLs such as http://host are not fields.
+var fieldTerm = regexp.MustCompile(`^([A-Za-z_@][\w.@-]*)(:|>=|<=|>|<)([^/].*)$`)
+
+// ParseQuery compiles a query.
+func ParseQuery(query string) (*Query, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("empty query")
+	}
+
+	p := &parser{tokens: tokens, query: &Query{}}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	p.query.root = root
+	return p.query, nil
+}
+
+// Matches reports whether event matches the query without changing it.
+func (q *Query) Matches(event *container.LogEvent) bool {
+	return q.root.match(&matchContext{event: event})
+}
+
+// Match reports whether event matches the query and highlights the matched
+// text terms.
+func (q *Query) Match(event *container.LogEvent) bool {
+	if !q.Matches(event) {
+		return false
+	}
+	for _, re := range q.text {
+		Search(re, event)
+	}
+	return true
+}
+
+type token struct {
+	text   string
+	phrase bool
+	paren  bool
+}
+
+// tokenize splits a query on whitespace and parentheses. Quotes group a phrase
+// or the value of a field term, with \" escaping a quote.
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case r == ' ' || r == '\t' || r == '\n':
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, token{text: string(r), paren: true})
+			i++
+		default:
+			var word strings.Builder
+			phrase := r == '"'
+			for i < len(runes) && !strings.ContainsRune(" \t\n()", runes[i]) {
+				if runes[i] != '"' {
+					word.WriteRune(runes[i])
+					i++
+					continue
+				}
+				end, value, err := readQuoted(runes, i)
+				if err != nil {
+					return nil, err
+				}
+				if phrase {
+					word.WriteString(value)
+				} else {
+					word.WriteString(strconv.Quote(value))
+				}
+				i = end
+			}
+			tokens = append(tokens, token{text: word.String(), phrase: phrase})
+		}
+	}
+	return tokens, nil
+}
+
+func readQuoted(runes []rune, start int) (int, string, error) {
+	var value strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				value.WriteRune(runes[i])
+			}
+		case '"':
+			return i + 1, value.String(), nil
+		default:
+			value.WriteRune(runes[i])
+		}
+	}
+	return 0, "", errors.New("unterminated quote")
+}
+
+type parser struct {
+	tokens  []token
+	pos     int
+	negated int
+	query   *Query
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) keyword(word string) bool {
+	if t, ok := p.peek(); ok && !t.phrase && !t.paren && t.text == word {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []node{left}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, right)
+	}
+	if len(nodes) == 1 {
+		return left, nil
+	}
+	return orNode(nodes), nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	var nodes []node
+	for {
+		t, ok := p.peek()
+		if !ok || (t.paren && t.text == ")") || (!t.phrase && !t.paren && t.text == "OR") {
+			break
+		}
+		p.keyword("AND")
+		n, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	switch len(nodes) {
+	case 0:
+		return nil, errors.New("expected a term")
+	case 1:
+		return nodes[0], nil
+	}
+	return andNode(nodes), nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.keyword("NOT") {
+		p.negated++
+		n, err := p.parseNot()
+		p.negated--
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	}
+
+	t, ok := p.peek()
+	if !ok {
+		return nil, errors.New("expected a term")
+	}
+	p.pos++
+
+	if t.paren {
+		if t.text == ")" {
+			return nil, errors.New("unexpected )")
+		}
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing, ok := p.peek(); !ok || !closing.paren || closing.text != ")" {
+			return nil, errors.New("missing )")
+		}
+		p.pos++
+		return n, nil
+	}
+
+	return p.parseTerm(t)
+}
+
+func (p *parser) parseTerm(t token) (node, error) {
+	if t.phrase {
+		return p.text(regexp.QuoteMeta(t.text))
+	}
+
+	parts := fieldTerm.FindStringSubmatch(t.text)
+	if parts == nil {
+		return p.text(t.text)
+	}
+
+	key, op, value := parts[1], parts[2], parts[3]
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	if op == ":" {
+		pattern, err := regexp.Compile("(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(value), `\*`, ".*") + "$")
+		if err != nil {
+			return nil, err
+		}
+		literal, err := regexp.Compile("(?i)" + regexp.QuoteMeta(key+":"+value))
+		if err != nil {
+			return nil, err
+		}
+		return fieldNode{key: key, pattern: pattern, literal: literal}, nil
+	}
+
+	switch key {
+	case "level":
+		rank, ok := levelRanks[strings.ToLower(value)]
+		if !ok {
+			return nil, fmt.Errorf("unknown level %q", value)
+		}
+		return compareNode{key: key, op: op, value: float64(rank)}, nil
+	case "stream":
+		return nil, fmt.Errorf("stream cannot be compared with %s", op)
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s%s%s: %q is not a number", key, op, value, value)
+	}
+	return compareNode{key: key, op: op, value: number}, nil
+}
+
+// text compiles a text term the way a plain filter is compiled.
+func (p *parser) text(pattern string) (node, error) {
+	re, err := ParseRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if p.negated%2 == 0 {
+		p.query.text = append(p.query.text, re)
+	}
+	return textNode{re}, nil
+}
+
+// matchContext renders the text of the event once for all text terms.
+type matchContext struct {
+	event    *container.LogEvent
+	text     string
+	rendered bool
+}
+
+func (c *matchContext) plainText() string {
+	if !c.rendered {
+		c.text = c.event.PlainText()
+		if c.text == "" {
+			if message, ok := c.event.Message.(string); ok {
+				c.text = container.StripHighlightMarkers(message)
+			}
+		}
+		c.rendered = true
+	}
+	return c.text
+}
+
+type node interface {
+	match(c *matchContext) bool
+}
+
+type andNode []node
+
+func (n andNode) match(c *matchContext) bool {
+	for _, child := range n {
+		if !child.match(c) {
+			return false
+		}
+	}
+	return true
+}
+
+type orNode []node
+
+func (n orNode) match(c *matchContext) bool {
+	for _, child := range n {
+		if child.match(c) {
+			return true
+		}
+	}
+	return false
+}
+
+type notNode struct {
+	node node
+}
+
+func (n notNode) match(c *matchContext) bool {
+	return !n.node.match(c)
+}
+
+type textNode struct {
+	re *regexp.Regexp
+}
+
+func (n textNode) match(c *matchContext) bool {
+	return n.re.MatchString(c.plainText())
+}
+
+// fieldNode matches field:value. On plain text logs, which have no fields, it
+// matches the term as written instead.
+type fieldNode struct {
+	key     string
+	pattern *regexp.Regexp
+	literal *regexp.Regexp
+}
+
+func (n fieldNode) match(c *matchContext) bool {
+	switch n.key {
+	case "level":
+		return n.pattern.MatchString(c.event.Level)
+	case "stream":
+		return n.pattern.MatchString(c.event.Stream)
+	}
+
+	values, structured := fieldValues(c.event.Message, n.key)
+	if !structured {
+		return n.literal.MatchString(c.plainText())
+	}
+	for _, value := range values {
+		if s, ok := formatValue(value); ok && n.pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+type compareNode struct {
+	key   string
+	op    string
+	value float64
+}
+
+func (n compareNode) match(c *matchContext) bool {
+	if n.key == "level" {
+		rank, ok := levelRanks[c.event.Level]
+		return ok && compare(float64(rank), n.op, n.value)
+	}
+
+	values, _ := fieldValues(c.event.Message, n.key)
+	for _, value := range values {
+		if number, ok := toNumber(value); ok && compare(number, n.op, n.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func compare(a float64, op string, b float64) bool {
+	switch op {
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	}
+	return false
+}
+
+// fieldValues looks up a dotted key in a structured message. Keys that
+// contain dots themselves are found too. Arrays yield each of their
+// elements. structured is false for plain text messages.
+func fieldValues(message any, key string) (values []any, structured bool) {
+	switch message := message.(type) {
+	case *orderedmap.OrderedMap[string, any]:
+		return lookup(func(k string) (any, bool) { return message.Get(k) }, key), true
+	case *orderedmap.OrderedMap[string, string]:
+		return lookup(func(k string) (any, bool) { return message.Get(k) }, key), true
+	case map[string]any:
+		return lookup(func(k string) (any, bool) { v, ok := message[k]; return v, ok }, key), true
+	case map[string]string:
+		return lookup(func(k string) (any, bool) { v, ok := message[k]; return v, ok }, key), true
+	}
+	return nil, false
+}
+
+func lookup(get func(string) (any, bool), key string) []any {
+	if value, ok := get(key); ok {
+		if items, ok := value.([]any); ok {
+			return items
+		}
+		return []any{value}
+	}
+
+	for i := strings.IndexByte(key, '.'); i > 0; i = nextDot(key, i) {
+		if value, ok := get(key[:i]); ok {
+			if values, structured := fieldValues(value, key[i+1:]); structured && len(values) > 0 {
+				return values
+			}
+		}
+	}
+	return nil
+}
+
+func nextDot(key string, after int) int {
+	if i := strings.IndexByte(key[after+1:], '.'); i >= 0 {
+		return after + 1 + i
+	}
+	return -1
+}
+
+func formatValue(value any) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return container.StripHighlightMarkers(value), true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case int, int64, bool:
+		return fmt.Sprint(value), true
+	case nil:
+		return "null", true
+	}
+	return "", false
+}
+
+func toNumber(value any) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(container.StripHighlightMarkers(value)), 64)
+		return number, err == nil && !math.IsNaN(number)
+	}
+	return 0, false
+}
@@ -0,0 +1,112 @@
+package support_web
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/amir20/dozzle/internal/container"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+func TestQueryMatch(t *testing.T) {
+	newEvent := func() *container.LogEvent {
+		request := orderedmap.New[string, any]()
+		request.Set("path", "/api/orders")
+		request.Set("method", "POST")
+		fields := orderedmap.New[string, any]()
+		fields.Set("msg", "request failed")
+		fields.Set("status", float64(502))
+		fields.Set("duration", "1.5")
+		fields.Set("request", request)
+		fields.Set("tags", []any{"billing", "eu"})
+		return &container.LogEvent{
+			Type:       container.LogTypeComplex,
+			Message:    fields,
+			RawMessage: `{"msg":"request failed","status":502,"duration":"1.5","request":{"path":"/api/orders","method":"POST"},"tags":["billing","eu"]}`,
+			Level:      "error",
+			Stream:     "stderr",
+		}
+	}
+	plain := func() *container.LogEvent {
+		return &container.LogEvent{
+			Type:       container.LogTypeSingle,
+			Message:    "user:alice connection reset by peer",
+			RawMessage: "user:alice connection reset by peer",
+			Level:      "warn",
+			Stream:     "stdout",
+		}
+	}
+
+	tests := []struct {
+		query string
+		event func() *container.LogEvent
+		want  bool
+	}{
+		{`status>=500 AND request.path:"/api/*"`, newEvent, true},
+		{`status>=500 AND request.path:"/admin/*"`, newEvent, false},
+		{"status<500", newEvent, false},
+		{"duration>1", newEvent, true},
+		{"request.method:post", newEvent, true},
+		{"tags:eu", newEvent, true},
+		{"level>=warn", newEvent, true},
+		{"level>=warn", plain, true},
+		{"level>warn", plain, false},
+		{"stream:stderr", newEvent, true},
+		{"stream:stderr", plain, false},
+		{`"connection reset"`, plain, true},
+		{`"reset connection"`, plain, false},
+		{"failed status>=500", newEvent, true},
+		{"NOT level:error", newEvent, false},
+		{"level:info OR level:warn", plain, true},
+		{"(level:info OR level:error) AND stream:stdout", plain, false},
+		{"connection NOT peer", plain, false},
+		{"user:alice", plain, true},
+		{"user:bob", plain, false},
+		{"status>=500", plain, false},
+		{"missing:value", newEvent, false},
+	}
+
+	for _, tt := range tests {
+		query, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q) failed: %v", tt.query, err)
+		}
+		if got := query.Match(tt.event()); got != tt.want {
+			t.Errorf("%q matched %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestQueryHighlightsPositiveTerms(t *testing.T) {
+	query, err := ParseQuery(`"connection reset" NOT timeout level:warn`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &container.LogEvent{Message: "connection reset by peer", RawMessage: "connection reset by peer", Level: "warn"}
+	if !query.Match(event) {
+		t.Fatal("expected query to match")
+	}
+	want := MarkerStart + "connection reset" + MarkerEnd + " by peer"
+	if event.Message != want {
+		t.Errorf("got %q, want %q", event.Message, want)
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		`"unterminated`,
+		"(level:error",
+		"level:error)",
+		"foo AND",
+		"level>=loud",
+		"status>=many",
+		"stream>stdout",
+		"NOT",
+	} {
+		if _, err := ParseQuery(query); err == nil {
+			t.Errorf("ParseQuery(%q) should fail", query)
+		} else if strings.TrimSpace(err.Error()) == "" {
+			t.Errorf("ParseQuery(%q) returned an empty error", query)
+		}
+	}
+}
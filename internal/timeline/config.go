@@ -0,0 +1,40 @@
+// Package timeline records the lifecycle events of containers on disk so
+// restarts, crashes and health changes can be looked up after the fact.
+package timeline
+
+import (
+	"io"
+	"time"
+
+	store_support "github.com/amir20/dozzle/internal/support/store"
+)
+
+const (
+	DefaultConfigPath = "./data/timeline.yml"
+	DefaultDirectory  = "./data/timeline"
+
+	defaultMaxAge = 30 * 24 * time.Hour
+)
+
+var spec = store_support.Spec{Name: "timeline", DefaultMaxAge: defaultMaxAge}
+
+// Config is the on-disk format of timeline.yml. MaxAge is how long events are
+// kept.
+type Config struct {
+	store_support.Config `yaml:",inline"`
+}
+
+// LoadConfig parses and validates timeline.yml.
+func LoadConfig(r io.Reader) (*Config, error) {
+	var config Config
+	if err := spec.Decode(r, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// LoadConfigFile reads the config at path. It returns nil when the file does
+// not exist or is invalid, which leaves the timeline disabled.
+func LoadConfigFile(path string) *Config {
+	return store_support.LoadFile(path, spec.Name, LoadConfig)
+}
@@ -0,0 +1,229 @@
+package timeline
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	store_support "github.com/amir20/dozzle/internal/support/store"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultLimit is the number of entries returned when no limit is given.
+	DefaultLimit = 500
+
+	day = 24 * time.Hour
+)
+
+// ErrDisabled is returned when timeline.yml does not exist.
+var ErrDisabled = errors.New("container timeline is disabled")
+
+// ErrNotRecorded is returned for the containers of agent hosts, whose events
+// are not recorded.
+var ErrNotRecorded = errors.New("events of agent hosts are not recorded")
+
+// Entry is a lifecycle event of a container. PreviousImage is set on start
+// events when the container with the same name last ran another image.
+type Entry struct {
+	Time          time.Time `json:"time"`
+	Name          string    `json:"name"`
+	Host          string    `json:"host"`
+	ContainerID   string    `json:"containerId"`
+	ContainerName string    `json:"containerName"`
+	Image         string    `json:"image,omitempty"`
+	PreviousImage string    `json:"previousImage,omitempty"`
+	ExitCode      *int      `json:"exitCode,omitempty"`
+	HealthStatus  string    `json:"healthStatus,omitempty"`
+	Signal        string    `json:"signal,omitempty"`
+}
+
+// Query selects the entries of the containers named ContainerName on Host,
+// which includes the earlier containers a recreated container replaced.
+// Names limits the event names, and only the last Limit entries are returned.
+type Query struct {
+	Host          string
+	ContainerName string
+	From          time.Time
+	To            time.Time
+	Names         []string
+	Limit         int
+}
+
+// Result holds the matching entries oldest first. Counts are per event name
+// over all matching entries, including the ones cut by the limit.
+type Result struct {
+	Entries   []Entry        `json:"entries"`
+	Counts    map[string]int `json:"counts"`
+	Truncated bool           `json:"truncated"`
+}
+
+type imageKey struct {
+	host string
+	name string
+}
+
+// Store appends entries to one file per day, laid out as <dir>/<day>.jsonl.
+type Store struct {
+	dir    string
+	maxAge time.Duration
+
+	mu     sync.Mutex
+	images map[imageKey]string
+}
+
+// NewStore opens the store in dir and reads the last image of every container
+// from the existing entries.
+func NewStore(dir string, maxAge time.Duration) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		dir:    dir,
+		maxAge: maxAge,
+		images: make(map[imageKey]string),
+	}
+
+	days, err := s.days()
+	if err != nil {
+		return nil, err
+	}
+	for _, start := range days {
+		if err := s.read(start, func(entry Entry) {
+			if entry.Image != "" {
+				s.images[imageKey{entry.Host, entry.ContainerName}] = entry.Image
+			}
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// days returns the start of the days with a file, oldest first.
+func (s *Store) days() ([]time.Time, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]time.Time, 0, len(files))
+	for _, file := range files {
+		start, err := strconv.ParseInt(strings.TrimSuffix(file.Name(), ".jsonl"), 10, 64)
+		if err != nil {
+			continue
+		}
+		days = append(days, time.Unix(start, 0))
+	}
+	slices.SortFunc(days, time.Time.Compare)
+	return days, nil
+}
+
+func (s *Store) path(t time.Time) string {
+	return filepath.Join(s.dir, strconv.FormatInt(t.Truncate(day).Unix(), 10)+".jsonl")
+}
+
+func (s *Store) read(start time.Time, fn func(Entry)) error {
+	file, err := os.Open(s.path(start))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = store_support.ReadLines(file, fn)
+	return err
+}
+
+// Add records entry.
+func (s *Store) Add(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry.Image != "" {
+		k := imageKey{entry.Host, entry.ContainerName}
+		if previous, ok := s.images[k]; ok && previous != entry.Image && entry.Name == "start" {
+			entry.PreviousImage = previous
+		}
+		s.images[k] = entry.Image
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path(entry.Time), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// Query returns the entries matching q.
+func (s *Store) Query(q Query) (Result, error) {
+	result := Result{
+		Entries: make([]Entry, 0),
+		Counts:  make(map[string]int),
+	}
+
+	for start := q.From.Truncate(day); !start.After(q.To); start = start.Add(day) {
+		err := s.read(start, func(entry Entry) {
+			if entry.Host != q.Host || entry.ContainerName != q.ContainerName {
+				return
+			}
+			if entry.Time.Before(q.From) || entry.Time.After(q.To) {
+				return
+			}
+			if len(q.Names) > 0 && !slices.Contains(q.Names, entry.Name) {
+				return
+			}
+			result.Counts[entry.Name]++
+			result.Entries = append(result.Entries, entry)
+		})
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	slices.SortStableFunc(result.Entries, func(a, b Entry) int {
+		return a.Time.Compare(b.Time)
+	})
+	if q.Limit > 0 && len(result.Entries) > q.Limit {
+		result.Entries = result.Entries[len(result.Entries)-q.Limit:]
+		result.Truncated = true
+	}
+	return result, nil
+}
+
+// Prune removes the day files whose entries are all older than maxAge.
+func (s *Store) Prune(now time.Time) {
+	days, err := s.days()
+	if err != nil {
+		log.Error().Err(err).Str("path", s.dir).Msg("Could not read timeline directory")
+		return
+	}
+
+	cutoff := now.Add(-s.maxAge)
+	for _, start := range days {
+		if !start.Add(day).Before(cutoff) {
+			break
+		}
+		if err := os.Remove(s.path(start)); err != nil {
+			log.Error().Err(err).Time("day", start).Msg("Could not remove timeline")
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package timeline
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testStart = time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)
+
+func entry(name string, id string, image string, offset time.Duration) Entry {
+	return Entry{Time: testStart.Add(offset), Name: name, Host: "local", ContainerID: id, ContainerName: "web", Image: image}
+}
+
+func TestStore_Query(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir, 30*24*time.Hour)
+	require.NoError(t, err)
+
+	// a crash loop spanning midnight, then a recreation with a new image
+	require.NoError(t, store.Add(entry("start", "aaa", "web:1", 0)))
+	for i := range 3 {
+		require.NoError(t, store.Add(entry("die", "aaa", "web:1", time.Duration(i+1)*time.Hour)))
+		require.NoError(t, store.Add(entry("restart", "aaa", "web:1", time.Duration(i+1)*time.Hour+time.Second)))
+	}
+	require.NoError(t, store.Add(entry("start", "bbb", "web:2", 4*time.Hour)))
+	require.NoError(t, store.Add(Entry{Time: testStart, Name: "start", Host: "local", ContainerID: "ccc", ContainerName: "db", Image: "postgres"}))
+
+	query := Query{Host: "local", ContainerName: "web", From: testStart, To: testStart.Add(5 * time.Hour)}
+	result, err := store.Query(query)
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 8)
+	assert.Equal(t, map[string]int{"start": 2, "die": 3, "restart": 3}, result.Counts)
+	assert.Equal(t, "bbb", result.Entries[7].ContainerID)
+	assert.Equal(t, "web:1", result.Entries[7].PreviousImage)
+	assert.Empty(t, result.Entries[0].PreviousImage)
+
+	query.Names = []string{"die"}
+	query.Limit = 2
+	result, err = store.Query(query)
+	require.NoError(t, err)
+	assert.Len(t, result.Entries, 2)
+	assert.True(t, result.Truncated)
+	assert.Equal(t, 3, result.Counts["die"])
+	assert.Equal(t, testStart.Add(3*time.Hour), result.Entries[1].Time.UTC())
+
+	// the last image is known again after a restart
+	reopened, err := NewStore(dir, 30*24*time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, reopened.Add(entry("start", "ddd", "web:3", 6*time.Hour)))
+	result, err = reopened.Query(Query{Host: "local", ContainerName: "web", From: testStart.Add(5 * time.Hour), To: testStart.Add(7 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 1)
+	assert.Equal(t, "web:2", result.Entries[0].PreviousImage)
+}
+
+func TestStore_Prune(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir, 24*time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Add(entry("start", "aaa", "web:1", 0)))
+	require.NoError(t, store.Add(entry("die", "aaa", "web:1", 48*time.Hour)))
+
+	store.Prune(testStart.Add(49 * time.Hour))
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func Test_newEntry(t *testing.T) {
+	event := container.ContainerEvent{
+		Name:            "die",
+		ActorID:         "aaa",
+		ActorAttributes: map[string]string{"exitCode": "137", "image": "web:2", "name": "web"},
+		Time:            testStart,
+	}
+	e := newEntry(event, container.Container{ID: "aaa", Name: "old", Image: "web:1"}, container.Host{ID: "local"})
+	require.NotNil(t, e.ExitCode)
+	assert.Equal(t, 137, *e.ExitCode)
+	assert.Equal(t, "web:2", e.Image)
+	assert.Equal(t, "web", e.ContainerName)
+	assert.Equal(t, "local", e.Host)
+
+	e = newEntry(container.ContainerEvent{Name: "health_status", ActorAttributes: map[string]string{"healthStatus": "unhealthy"}}, container.Container{Host: "node-1"}, container.Host{ID: "cluster"})
+	assert.Nil(t, e.ExitCode)
+	assert.Equal(t, "unhealthy", e.HealthStatus)
+	assert.Equal(t, "node-1", e.Host)
+}
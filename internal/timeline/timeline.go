@@ -0,0 +1,104 @@
+package timeline
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/notification"
+	container_support "github.com/amir20/dozzle/internal/support/container"
+	"github.com/rs/zerolog/log"
+)
+
+// pruneInterval is how often old entries are removed.
+const pruneInterval = time.Hour
+
+// Timeline records the lifecycle events of the containers of the clients into
+// a Store.
+type Timeline struct {
+	store    *Store
+	listener *notification.ContainerEventListener
+	ctx      context.Context
+}
+
+// Start opens the store in dir and begins recording the events of the
+// containers of clients.
+func Start(ctx context.Context, config *Config, dir string, clients []container_support.ClientService) (*Timeline, error) {
+	store, err := NewStore(dir, config.Retention())
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Timeline{
+		store:    store,
+		listener: notification.NewContainerEventListener(ctx, clients),
+		ctx:      ctx,
+	}
+
+	store.Prune(time.Now())
+	t.listener.Start()
+	go t.run()
+
+	log.Info().Str("path", dir).Str("maxAge", config.Retention().String()).Msg("Recording container timeline")
+	return t, nil
+}
+
+func (t *Timeline) run() {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case event := <-t.listener.Channel():
+			entry := newEntry(event.Event, event.Container, event.Host)
+			if err := t.store.Add(entry); err != nil {
+				log.Error().Err(err).Str("container", entry.ContainerID).Msg("Could not record container event")
+			}
+		case now := <-ticker.C:
+			t.store.Prune(now)
+		}
+	}
+}
+
+// newEntry keeps the attributes of event that describe the transition. The
+// image and name of the event are preferred as the container may have changed
+// since it was looked up.
+func newEntry(event container.ContainerEvent, c container.Container, host container.Host) Entry {
+	attributes := event.ActorAttributes
+	entry := Entry{
+		Time:          event.Time,
+		Name:          event.Name,
+		Host:          c.Host,
+		ContainerID:   event.ActorID,
+		ContainerName: c.Name,
+		Image:         c.Image,
+		HealthStatus:  attributes["healthStatus"],
+		Signal:        attributes["signal"],
+	}
+
+	// k8s containers carry their node rather than the cluster host
+	if entry.Host == "" {
+		entry.Host = host.ID
+	}
+	if name := attributes["name"]; name != "" {
+		entry.ContainerName = name
+	}
+	if image := attributes["image"]; image != "" {
+		entry.Image = image
+	}
+	if value, ok := attributes["exitCode"]; ok {
+		if code, err := strconv.Atoi(value); err == nil {
+			entry.ExitCode = &code
+		}
+	}
+
+	return entry
+}
+
+// Query returns the entries matching q.
+func (t *Timeline) Query(q Query) (Result, error) {
+	return t.store.Query(q)
+}
@@ -0,0 +1,135 @@
+package views
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultSharedPath is where the views shared by all users are stored.
+const DefaultSharedPath = "./data/views.yml"
+
+// sharedFile is the on-disk format of views.yml.
+type sharedFile struct {
+	Views []View `yaml:"views"`
+}
+
+// Shared holds the views shared by all users and writes them to a YAML file
+// on every change. Safe for concurrent use.
+type Shared struct {
+	path  string
+	mu    sync.RWMutex
+	views []View
+}
+
+// NewShared loads the shared views at path. A missing file has no views, and
+// an invalid one is logged and replaced on the next change.
+func NewShared(path string) *Shared {
+	s := &Shared{path: path}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return s
+	}
+	defer file.Close()
+
+	views, err := load(file)
+	if err != nil {
+		log.Warn().Err(err).Msg("Could not load shared views")
+		return s
+	}
+	s.views = views
+	log.Debug().Str("path", path).Int("views", len(views)).Msg("Loaded shared views")
+	return s
+}
+
+func load(r io.Reader) ([]View, error) {
+	var config sharedFile
+	if err := yaml.NewDecoder(r).Decode(&config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to decode shared views: %w", err)
+	}
+	for i := range config.Views {
+		config.Views[i].Shared = true
+	}
+	return config.Views, nil
+}
+
+// List returns a copy of the shared views.
+func (s *Shared) List() []View {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.views)
+}
+
+// Get returns the shared view with id.
+func (s *Shared) Get(id string) (View, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, view := range s.views {
+		if view.ID == id {
+			return view, nil
+		}
+	}
+	return View{}, ErrNotFound
+}
+
+// Put adds view or replaces the view with the same ID and saves the file.
+func (s *Shared) Put(view View) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	view.Shared = true
+	views := slices.Clone(s.views)
+	if i := slices.IndexFunc(views, func(v View) bool { return v.ID == view.ID }); i >= 0 {
+		views[i] = view
+	} else {
+		views = append(views, view)
+	}
+
+	if err := s.save(views); err != nil {
+		return err
+	}
+	s.views = views
+	return nil
+}
+
+// Delete removes the view with id and saves the file.
+func (s *Shared) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.views, func(v View) bool { return v.ID == id })
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	views := slices.Delete(slices.Clone(s.views), i, i+1)
+	if err := s.save(views); err != nil {
+		return err
+	}
+	s.views = views
+	return nil
+}
+
+func (s *Shared) save(views []View) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(s.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := yaml.NewEncoder(file)
+	if err := encoder.Encode(sharedFile{Views: views}); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,40 @@
+package views
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedPersistsViews(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "views.yml")
+	shared := NewShared(path)
+	assert.Empty(t, shared.List())
+
+	require.NoError(t, shared.Put(View{ID: "a", Name: "errors", Query: "?level:error", Stdout: true}))
+	require.NoError(t, shared.Put(View{ID: "b", Name: "api", Containers: []string{"api"}, Stderr: true}))
+	require.NoError(t, shared.Put(View{ID: "a", Name: "all errors", Query: "?level:error", Stdout: true}))
+
+	reloaded := NewShared(path)
+	views := reloaded.List()
+	require.Len(t, views, 2)
+	assert.Equal(t, "all errors", views[0].Name)
+	assert.True(t, views[0].Shared)
+	assert.Equal(t, []string{"api"}, views[1].Containers)
+
+	require.NoError(t, reloaded.Delete("a"))
+	assert.ErrorIs(t, reloaded.Delete("a"), ErrNotFound)
+	_, err := reloaded.Get("a")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Len(t, NewShared(path).List(), 1)
+}
+
+func TestSharedIgnoresInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "views.yml")
+	require.NoError(t, os.WriteFile(path, []byte("views: [oops"), 0644))
+
+	assert.Empty(t, NewShared(path).List())
+}
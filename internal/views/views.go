@@ -0,0 +1,87 @@
+// Package views defines saved views of the log viewer: the containers, filter,
+// levels, streams and time range of a search under a name. Private views are
+// kept in the profile of their user and shared views in views.yml.
+package views
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	support_web "github.com/amir20/dozzle/internal/support/web"
+)
+
+// maxNameLength bounds the name of a view.
+const maxNameLength = 100
+
+// View is a saved search. Containers are names rather than IDs so a view
+// survives its containers being recreated. The time range is either relative,
+// Since before now, or the fixed range From to To.
+type View struct {
+	ID         string     `json:"id" yaml:"id"`
+	Name       string     `json:"name" yaml:"name"`
+	Host       string     `json:"host,omitempty" yaml:"host,omitempty"`
+	Containers []string   `json:"containers,omitempty" yaml:"containers,omitempty"`
+	Query      string     `json:"query,omitempty" yaml:"query,omitempty"`
+	Levels     []string   `json:"levels,omitempty" yaml:"levels,omitempty"`
+	Stdout     bool       `json:"stdout" yaml:"stdout"`
+	Stderr     bool       `json:"stderr" yaml:"stderr"`
+	Since      string     `json:"since,omitempty" yaml:"since,omitempty"`
+	From       *time.Time `json:"from,omitempty" yaml:"from,omitempty"`
+	To         *time.Time `json:"to,omitempty" yaml:"to,omitempty"`
+	Shared     bool       `json:"shared" yaml:"-"`
+	Owner      string     `json:"owner,omitempty" yaml:"owner,omitempty"`
+	UpdatedAt  time.Time  `json:"updatedAt" yaml:"updatedAt"`
+}
+
+// ErrNotFound is returned when no view has the requested ID.
+var ErrNotFound = errors.New("view not found")
+
+// NewID returns a random ID for a new view.
+func NewID() string {
+	return strings.ToLower(rand.Text()[:16])
+}
+
+// Validate reports the first invalid setting of v.
+func (v *View) Validate() error {
+	v.Name = strings.TrimSpace(v.Name)
+	if v.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(v.Name) > maxNameLength {
+		return fmt.Errorf("name must be at most %d characters", maxNameLength)
+	}
+	if !v.Stdout && !v.Stderr {
+		return errors.New("stdout or stderr is required")
+	}
+	for _, level := range v.Levels {
+		if _, ok := container.SupportedLogLevels[level]; !ok {
+			return fmt.Errorf("invalid level %q", level)
+		}
+	}
+	if v.Query != "" {
+		if _, err := support_web.ParseFilter(v.Query); err != nil {
+			return fmt.Errorf("invalid query: %w", err)
+		}
+	}
+
+	if v.Since != "" {
+		if v.From != nil || v.To != nil {
+			return errors.New("since cannot be combined with from and to")
+		}
+		if since, err := time.ParseDuration(v.Since); err != nil || since <= 0 {
+			return fmt.Errorf("invalid since %q", v.Since)
+		}
+	}
+	if (v.From == nil) != (v.To == nil) {
+		return errors.New("from and to must be set together")
+	}
+	if v.From != nil && !v.From.Before(*v.To) {
+		return errors.New("from must be before to")
+	}
+
+	return nil
+}
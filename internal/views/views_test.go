@@ -0,0 +1,43 @@
+package views
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		view  View
+		valid bool
+	}{
+		{"minimal", View{Name: "all", Stdout: true}, true},
+		{"relative range", View{Name: "recent", Stderr: true, Since: "15m"}, true},
+		{"fixed range", View{Name: "incident", Stdout: true, From: &from, To: &to}, true},
+		{"query and levels", View{Name: "slow", Stdout: true, Query: "?duration>1", Levels: []string{"warn", "error"}}, true},
+		{"blank name", View{Name: "  ", Stdout: true}, false},
+		{"no streams", View{Name: "none"}, false},
+		{"unknown level", View{Name: "loud", Stdout: true, Levels: []string{"loud"}}, false},
+		{"invalid query", View{Name: "broken", Stdout: true, Query: "?status>=many"}, false},
+		{"negative since", View{Name: "past", Stdout: true, Since: "-1h"}, false},
+		{"since and range", View{Name: "both", Stdout: true, Since: "1h", From: &from, To: &to}, false},
+		{"reversed range", View{Name: "reversed", Stdout: true, From: &to, To: &from}, false},
+		{"open range", View{Name: "open", Stdout: true, To: &to}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.view.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
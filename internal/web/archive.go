@@ -0,0 +1,80 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amir20/dozzle/internal/archive"
+	"github.com/rs/zerolog/log"
+)
+
+// maxSearchLimit bounds the hits of a single search.
+const maxSearchLimit = 1000
+
+// archivedContainers lists the removed containers whose logs are still
+// available from the archive.
+func (h *handler) archivedContainers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.hostService.ListArchivedContainers(h.resolveLabels(r)))
+}
+
+// searchArchive finds archived lines across all containers with the search
+// index. It searches the last 7 days unless from and to are given.
+func (h *handler) searchArchive(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseTimeRange(r, 7*24*time.Hour)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := archive.DefaultSearchLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxSearchLimit {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	query := archive.Query{
+		Text:  r.URL.Query().Get("q"),
+		From:  from,
+		To:    to,
+		Limit: limit,
+	}
+
+	if userLabels := h.resolveLabels(r); userLabels.Exists() {
+		allowed := make(map[string]struct{})
+		containers, errs := h.hostService.ListAllContainers(userLabels)
+		if len(errs) > 0 {
+			log.Warn().Err(errs[0]).Msg("error while listing containers")
+		}
+		containers = append(containers, h.hostService.ListArchivedContainers(userLabels)...)
+		for _, c := range containers {
+			allowed[c.ID] = struct{}{}
+		}
+		query.Allow = func(id string) bool {
+			_, ok := allowed[id]
+			return ok
+		}
+	}
+
+	start := time.Now()
+	result, err := h.hostService.SearchArchive(r.Context(), query)
+	switch {
+	case errors.Is(err, archive.ErrIndexDisabled):
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, archive.ErrEmptyQuery):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		log.Error().Err(err).Msg("error searching archive")
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	log.Debug().Str("query", query.Text).Int("hits", len(result.Hits)).Dur("took", time.Since(start)).Msg("searched archive")
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,36 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_handler_archive_disabled(t *testing.T) {
+	handler := createDefaultHandler(nil)
+
+	req, err := http.NewRequest("GET", "/api/archive/containers", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var containers []any
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&containers))
+	assert.Empty(t, containers)
+
+	req, err = http.NewRequest("GET", "/api/archive/search?q=timeout", nil)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	req, err = http.NewRequest("GET", "/api/archive/search?q=timeout&limit=5000", nil)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,96 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amir20/dozzle/internal/correlate"
+	container_support "github.com/amir20/dozzle/internal/support/container"
+	"github.com/rs/zerolog/log"
+)
+
+// correlateLogs streams the lines of all containers containing id as JSON
+// lines, oldest first, the last hour by default. field restricts the match to
+// the values of JSON fields, e.g. field=trace_id,traceId.
+func (h *handler) correlateLogs(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseTimeRange(r, time.Hour)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := correlate.DefaultLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	var fields []string
+	for _, value := range r.URL.Query()["field"] {
+		for field := range strings.SplitSeq(value, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	labels := h.resolveLabels(r)
+	containers, errs := h.hostService.ListAllContainers(labels)
+	if len(errs) > 0 {
+		log.Warn().Err(errs[0]).Msg("error while listing containers")
+	}
+
+	services := make([]*container_support.ContainerService, 0, len(containers))
+	for _, c := range containers {
+		service, err := h.hostService.FindContainer(c.Host, c.ID, labels)
+		if err != nil {
+			log.Debug().Err(err).Str("container", c.ID).Msg("skipping container for correlation")
+			continue
+		}
+		services = append(services, service)
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	hits, err := correlate.Search(ctx, services, correlate.Query{
+		ID:     r.URL.Query().Get("id"),
+		Fields: fields,
+		From:   from,
+		To:     to,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-jsonl; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	flusher, _ := w.(http.Flusher)
+
+	count := 0
+	for hit := range hits {
+		if err := encoder.Encode(hit); err != nil {
+			if !errors.Is(err, context.Canceled) {
+				log.Debug().Err(err).Msg("error writing correlated log")
+			}
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if count++; count >= limit {
+			return
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_handler_correlateLogs(t *testing.T) {
+	created := time.Date(2020, time.May, 13, 18, 0, 0, 0, time.UTC)
+	api := container.Container{ID: "api123", Name: "api", Host: "localhost", Created: created, State: "running"}
+	worker := container.Container{ID: "worker123", Name: "worker", Host: "localhost", Created: created, State: "running"}
+
+	apiLogs := append(
+		makeMessage("2020-05-13T18:55:00.000000000Z {\"msg\":\"request\",\"trace_id\":\"abc-123\"}\n", container.STDOUT),
+		makeMessage("2020-05-13T18:55:30.000000000Z {\"msg\":\"done\",\"trace_id\":\"abc-123\"}\n", container.STDOUT)...,
+	)
+	workerLogs := append(
+		makeMessage("2020-05-13T18:55:10.000000000Z INFO processing abc-123\n", container.STDOUT),
+		makeMessage("2020-05-13T18:55:20.000000000Z INFO processing other\n", container.STDOUT)...,
+	)
+
+	mockedClient := new(MockedClient)
+	mockedClient.On("FindContainer", mock.Anything, api.ID).Return(api, nil)
+	mockedClient.On("FindContainer", mock.Anything, worker.ID).Return(worker, nil)
+	mockedClient.On("ContainerLogsBetweenDates", mock.Anything, api.ID, mock.Anything, mock.Anything, container.STDALL).
+		Return(io.NopCloser(bytes.NewReader(apiLogs)), nil)
+	mockedClient.On("ContainerLogsBetweenDates", mock.Anything, worker.ID, mock.Anything, mock.Anything, container.STDALL).
+		Return(io.NopCloser(bytes.NewReader(workerLogs)), nil)
+	mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+	mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{api, worker}, nil)
+	mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil)
+
+	handler := createDefaultHandler(mockedClient)
+	req, err := http.NewRequest("GET", "/api/correlate?id=abc-123&from=2020-05-13T18:50:00Z&to=2020-05-13T19:00:00Z", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var names []string
+	for line := range strings.SplitSeq(strings.TrimSpace(rr.Body.String()), "\n") {
+		var hit struct {
+			ContainerName string `json:"containerName"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(line), &hit))
+		names = append(names, hit.ContainerName)
+	}
+	assert.Equal(t, []string{"api", "worker", "api"}, names)
+}
+
+func Test_handler_correlateLogs_invalid(t *testing.T) {
+	handler := createDefaultHandler(nil)
+
+	for _, query := range []string{"", "id=%20", "id=abc&limit=0", "id=abc&field=bad%20field"} {
+		req, err := http.NewRequest("GET", "/api/correlate?"+query, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
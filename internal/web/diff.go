@@ -0,0 +1,88 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/patterns"
+	"github.com/rs/zerolog/log"
+)
+
+// diffResponse is a patterns.Diff with the ranges that were compared.
+type diffResponse struct {
+	A diffRange `json:"a"`
+	B diffRange `json:"b"`
+	patterns.Diff
+}
+
+type diffRange struct {
+	Host          string    `json:"host"`
+	ContainerID   string    `json:"containerId"`
+	ContainerName string    `json:"containerName"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+}
+
+// diffLogs compares the message templates of two log ranges. a and b are
+// containers formatted as host~id; b defaults to a so two ranges of the same
+// container can be compared. Each range defaults to the hour before its end.
+func (h *handler) diffLogs(w http.ResponseWriter, r *http.Request) {
+	a := r.URL.Query().Get("a")
+	b := r.URL.Query().Get("b")
+	if b == "" {
+		b = a
+	}
+
+	ranges := [2]diffRange{}
+	for i, side := range []struct{ name, value string }{{"a", a}, {"b", b}} {
+		host, id, ok := strings.Cut(side.value, "~")
+		if !ok || host == "" || id == "" {
+			writeError(w, http.StatusBadRequest, "invalid "+side.name+", expected host~id")
+			return
+		}
+		from, to, err := parseNamedTimeRange(r, side.name+"From", side.name+"To", time.Hour)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ranges[i] = diffRange{Host: host, ContainerID: id, From: from.UTC(), To: to.UTC()}
+	}
+
+	limit := patterns.DefaultLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	stdTypes := parseStdTypes(r)
+	if stdTypes == 0 {
+		stdTypes = container.STDALL
+	}
+
+	labels := h.resolveLabels(r)
+	var events [2]<-chan *container.LogEvent
+	for i := range ranges {
+		containerService, err := h.hostService.FindContainer(ranges[i].Host, ranges[i].ContainerID, labels)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		ranges[i].ContainerName = containerService.Container.Name
+
+		events[i], err = containerService.LogsBetweenDates(r.Context(), ranges[i].From, ranges[i].To, stdTypes)
+		if err != nil {
+			log.Error().Err(err).Msg("error fetching logs for diff")
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, diffResponse{A: ranges[0], B: ranges[1], Diff: patterns.Compare(events[0], events[1], limit)})
+}
@@ -0,0 +1,91 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_handler_diffLogs(t *testing.T) {
+	healthy, broken := "111111", "222222"
+
+	var healthyData, brokenData []byte
+	healthyData = append(healthyData, makeMessage("2020-05-13T18:55:37.772853839Z INFO user 1 logged in\n", container.STDOUT)...)
+	healthyData = append(healthyData, makeMessage("2020-05-13T18:55:38.772853839Z INFO cache warmed in 12ms\n", container.STDOUT)...)
+	brokenData = append(brokenData, makeMessage("2020-05-13T18:55:37.772853839Z INFO user 2 logged in\n", container.STDOUT)...)
+	brokenData = append(brokenData, makeMessage("2020-05-13T18:55:39.772853839Z ERROR connection refused by 10.0.0.1\n", container.STDERR)...)
+
+	from, _ := time.Parse(time.RFC3339, "2020-05-13T18:00:00Z")
+	to, _ := time.Parse(time.RFC3339, "2020-05-13T19:00:00Z")
+
+	mockedClient := new(MockedClient)
+	mockedClient.On("ContainerLogsBetweenDates", mock.Anything, healthy, from, to, container.STDALL).Return(io.NopCloser(bytes.NewReader(healthyData)), nil)
+	mockedClient.On("ContainerLogsBetweenDates", mock.Anything, broken, from, to, container.STDALL).Return(io.NopCloser(bytes.NewReader(brokenData)), nil)
+	mockedClient.On("FindContainer", mock.Anything, healthy).Return(container.Container{ID: healthy, Name: "api-1"}, nil)
+	mockedClient.On("FindContainer", mock.Anything, broken).Return(container.Container{ID: broken, Name: "api-2"}, nil)
+	mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+	mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{
+		{ID: healthy, Name: "api-1", Host: "localhost", State: "running"},
+		{ID: broken, Name: "api-2", Host: "localhost", State: "running"},
+	}, nil)
+	mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil)
+
+	req, err := http.NewRequest("GET", "/api/diff", nil)
+	require.NoError(t, err)
+	q := req.URL.Query()
+	q.Add("a", "localhost~"+healthy)
+	q.Add("b", "localhost~"+broken)
+	for _, key := range []string{"aFrom", "bFrom"} {
+		q.Add(key, from.Format(time.RFC3339))
+	}
+	for _, key := range []string{"aTo", "bTo"} {
+		q.Add(key, to.Format(time.RFC3339))
+	}
+	req.URL.RawQuery = q.Encode()
+
+	handler := createDefaultHandler(mockedClient)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var diff diffResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&diff))
+	assert.Equal(t, "api-1", diff.A.ContainerName)
+	assert.Equal(t, "api-2", diff.B.ContainerName)
+	assert.Equal(t, 2, diff.TotalA)
+	assert.Equal(t, 2, diff.TotalB)
+	require.Len(t, diff.OnlyInA, 1)
+	assert.Equal(t, "INFO cache warmed in <*>", diff.OnlyInA[0].Template)
+	require.Len(t, diff.OnlyInB, 1)
+	assert.Equal(t, "ERROR connection refused by <*>", diff.OnlyInB[0].Template)
+	assert.Empty(t, diff.Changed)
+	assert.Equal(t, 1, diff.Unchanged)
+}
+
+func Test_handler_diffLogs_invalid(t *testing.T) {
+	handler := createDefaultHandler(nil)
+
+	for _, query := range []string{
+		"",
+		"a=localhost",
+		"a=localhost~1&b=~2",
+		"a=localhost~1&aFrom=yesterday",
+		"a=localhost~1&bFrom=2020-05-13T19:00:00Z&bTo=2020-05-13T18:00:00Z",
+		"a=localhost~1&limit=0",
+	} {
+		req, err := http.NewRequest("GET", "/api/diff?"+query, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
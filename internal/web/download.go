@@ -2,10 +2,10 @@ package web
 
 import (
 	"archive/zip"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"regexp"
 	"strings"
 	"time"
 
@@ -57,20 +57,38 @@ func (h *handler) downloadLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse filter regex if provided
-	var regex *regexp.Regexp
+	// Parse filter if provided
+	var filter *support_web.Filter
 	var err error
 	if r.URL.Query().Has("filter") {
-		regex, err = support_web.ParseRegex(r.URL.Query().Get("filter"))
+		filter, err = support_web.ParseFilter(r.URL.Query().Get("filter"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 	}
 
-	// Inverse mode excludes lines matching the regex instead of keeping them.
+	// Inverse mode excludes lines matching the filter instead of keeping them.
 	inverse := r.URL.Query().Get("inverse") == "true"
 
+	// Lines around each match are kept like grep -B and -A.
+	before, after, err := parseContextLines(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// JSON mode writes one parsed event per line so structured fields survive
+	asJSON := false
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "text":
+	case "json":
+		asJSON = true
+	default:
+		http.Error(w, fmt.Sprintf("invalid format: %s", format), http.StatusBadRequest)
+		return
+	}
+
 	// Parse level filters if provided
 	levels := make(map[string]struct{})
 	if r.URL.Query().Has("levels") {
@@ -139,7 +157,11 @@ func (h *handler) downloadLogs(w http.ResponseWriter, r *http.Request) {
 	// Process each container - errors after this point are logged only since response has started
 	for _, c := range containers {
 		// Create new file in zip for this container's logs
-		fileName := fmt.Sprintf("%s-%s.log", c.containerService.Container.Name, nowFmt)
+		extension := "log"
+		if asJSON {
+			extension = "jsonl"
+		}
+		fileName := fmt.Sprintf("%s-%s.%s", c.containerService.Container.Name, nowFmt, extension)
 		f, err := zw.CreateHeader(&zip.FileHeader{
 			Name:     fileName,
 			Modified: now,
@@ -151,7 +173,7 @@ func (h *handler) downloadLogs(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Get container logs - use LogsBetweenDates if filtering is needed, otherwise use RawLogs
-		if regex != nil || len(levels) > 0 {
+		if filter != nil || len(levels) > 0 || asJSON {
 			// Fetch parsed log events for filtering
 			events, err := c.containerService.LogsBetweenDates(r.Context(), time.Time{}, now, stdTypes)
 			if err != nil {
@@ -160,43 +182,52 @@ func (h *handler) downloadLogs(w http.ResponseWriter, r *http.Request) {
 			}
 
 			// Filter and write events
-			for event := range events {
-				// Apply regex filter if provided. In inverse mode a match excludes
+			contextLines := support_web.NewContextLines(before, after)
+			for next := range events {
+				// Apply filter if provided. In inverse mode a match excludes
 				// the line, so skip when the match result equals the inverse flag.
-				if regex != nil && inverse == support_web.Search(regex, event) {
-					continue
-				}
+				matched := filter == nil || inverse != filter.Match(next)
 
 				// Apply level filter if provided
 				if len(levels) > 0 {
-					if _, ok := levels[event.Level]; !ok {
-						continue
+					if _, ok := levels[next.Level]; !ok {
+						matched = false
 					}
 				}
 
-				// Format timestamp in UTC
-				timestamp := time.UnixMilli(event.Timestamp).UTC().Format(time.RFC3339Nano)
-
-				// Handle grouped logs
-				if event.Type == container.LogTypeGroup {
-					if fragments, ok := event.Message.([]container.LogFragment); ok {
-						for _, fragment := range fragments {
-							// Strip the search-highlight markers the regex filter
-							// injected into grouped fragments; otherwise they leak
-							// into the downloaded file as invisible characters.
-							_, err = fmt.Fprintf(f, "%s %s\n", timestamp, container.StripHighlightMarkers(fragment.Message))
-							if err != nil {
-								log.Error().Err(err).Msgf("error writing log for container %s", c.id)
-								return
-							}
+				for _, event := range contextLines.Next(next, matched) {
+					// Format timestamp in UTC
+					timestamp := time.UnixMilli(event.Timestamp).UTC().Format(time.RFC3339Nano)
+
+					if asJSON {
+						if err := writeJSONLogLine(f, timestamp, event); err != nil {
+							log.Error().Err(err).Msgf("error writing log for container %s", c.id)
+							return
 						}
+						continue
 					}
-				} else {
-					// Write timestamp followed by message for single/complex logs
-					_, err = fmt.Fprintf(f, "%s %s\n", timestamp, event.RawMessage)
-					if err != nil {
-						log.Error().Err(err).Msgf("error writing log for container %s", c.id)
-						return
+
+					// Handle grouped logs
+					if event.Type == container.LogTypeGroup {
+						if fragments, ok := event.Message.([]container.LogFragment); ok {
+							for _, fragment := range fragments {
+								// Strip the search-highlight markers the filter
+								// injected into grouped fragments; otherwise they leak
+								// into the downloaded file as invisible characters.
+								_, err = fmt.Fprintf(f, "%s %s\n", timestamp, container.StripHighlightMarkers(fragment.Message))
+								if err != nil {
+									log.Error().Err(err).Msgf("error writing log for container %s", c.id)
+									return
+								}
+							}
+						}
+					} else {
+						// Write timestamp followed by message for single/complex logs
+						_, err = fmt.Fprintf(f, "%s %s\n", timestamp, event.RawMessage)
+						if err != nil {
+							log.Error().Err(err).Msgf("error writing log for container %s", c.id)
+							return
+						}
 					}
 				}
 			}
@@ -217,3 +248,47 @@ func (h *handler) downloadLogs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+// jsonLogLine is one line of a JSON download. Message holds the parsed fields
+// of structured logs, the lines of grouped logs, or the plain text otherwise.
+// Context is set on lines only included around a match.
+type jsonLogLine struct {
+	Timestamp string `json:"timestamp"`
+	Level     string `json:"level,omitempty"`
+	Stream    string `json:"stream,omitempty"`
+	Type      string `json:"type"`
+	Message   any    `json:"message"`
+	Raw       string `json:"raw,omitempty"`
+	Context   bool   `json:"context,omitempty"`
+}
+
+func writeJSONLogLine(w io.Writer, timestamp string, event *container.LogEvent) error {
+	line := jsonLogLine{
+		Timestamp: timestamp,
+		Level:     event.Level,
+		Stream:    event.Stream,
+		Type:      string(event.Type),
+		Message:   event.Message,
+		Context:   event.Context,
+	}
+	switch message := event.Message.(type) {
+	case []container.LogFragment:
+		lines := make([]string, len(message))
+		for i, fragment := range message {
+			lines[i] = fragment.Message
+		}
+		line.Message = lines
+	case string:
+		line.Message = event.RawMessage
+	default:
+		line.Raw = event.RawMessage
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	// The filter injects highlight markers into matched values
+	_, err = fmt.Fprintf(w, "%s\n", container.StripHighlightMarkers(string(data)))
+	return err
+}
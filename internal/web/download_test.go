@@ -98,3 +98,111 @@ func Test_handler_download_logs_inverse_filter(t *testing.T) {
 		require.NotContains(t, out, "all good")
 	})
 }
+
+func Test_handler_download_logs_json_format(t *testing.T) {
+	id := "123456"
+	data := append(
+		makeMessage("2020-05-13T18:55:37.772853839Z {\"level\":\"error\",\"status\":500}\n", container.STDOUT),
+		makeMessage("2020-05-13T18:56:37.772853839Z INFO all good\n", container.STDOUT)...,
+	)
+
+	mockedClient := new(MockedClient)
+	mockedClient.On("FindContainer", mock.Anything, id).Return(container.Container{ID: id, Tty: false}, nil)
+	mockedClient.On("ContainerLogsBetweenDates", mock.Anything, id, mock.Anything, mock.Anything, container.STDOUT).Return(io.NopCloser(bytes.NewReader(data)), nil)
+	mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+	mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil).Run(func(args mock.Arguments) {
+		time.Sleep(1 * time.Second)
+	})
+	mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{
+		{ID: id, Name: "test", State: "running"},
+	}, nil)
+
+	req, err := http.NewRequest("GET", "/api/containers/localhost~"+id+"/download?stdout=1&format=json", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	createDefaultHandler(mockedClient).ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	body := rr.Body.Bytes()
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	require.Len(t, zr.File, 1)
+	require.Contains(t, zr.File[0].Name, ".jsonl")
+	f, err := zr.File[0].Open()
+	require.NoError(t, err)
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(content), []byte("\n"))
+	require.Len(t, lines, 2)
+	require.JSONEq(t, `{"timestamp":"2020-05-13T18:55:37.772Z","level":"error","stream":"stdout","type":"complex","message":{"level":"error","status":500},"raw":"{\"level\":\"error\",\"status\":500}"}`, string(lines[0]))
+	require.JSONEq(t, `{"timestamp":"2020-05-13T18:56:37.772Z","level":"info","stream":"stdout","type":"single","message":"INFO all good"}`, string(lines[1]))
+}
+
+func Test_handler_download_logs_invalid_format(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/containers/localhost~123456/download?stdout=1&format=xml", nil)
+	require.NoError(t, err)
+	mockedClient := new(MockedClient)
+	mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+	mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil).Run(func(args mock.Arguments) {
+		time.Sleep(1 * time.Second)
+	})
+	mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{}, nil)
+
+	rr := httptest.NewRecorder()
+	createDefaultHandler(mockedClient).ServeHTTP(rr, req)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func Test_handler_download_logs_context_lines(t *testing.T) {
+	id := "123456"
+	data := append(
+		makeMessage("2020-05-13T18:55:37.772853839Z INFO connecting\n", container.STDOUT),
+		makeMessage("2020-05-13T18:55:38.772853839Z ERROR boom\n", container.STDOUT)...,
+	)
+	data = append(data, makeMessage("2020-05-13T18:55:39.772853839Z INFO retrying\n", container.STDOUT)...)
+	data = append(data, makeMessage("2020-05-13T18:56:37.772853839Z INFO all good\n", container.STDOUT)...)
+
+	mockedClient := new(MockedClient)
+	mockedClient.On("FindContainer", mock.Anything, id).Return(container.Container{ID: id, Tty: false}, nil)
+	mockedClient.On("ContainerLogsBetweenDates", mock.Anything, id, mock.Anything, mock.Anything, container.STDOUT).Return(io.NopCloser(bytes.NewReader(data)), nil)
+	mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+	mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil).Run(func(args mock.Arguments) {
+		time.Sleep(1 * time.Second)
+	})
+	mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{
+		{ID: id, Name: "test", State: "running"},
+	}, nil)
+
+	req, err := http.NewRequest("GET", "/api/containers/localhost~"+id+"/download?stdout=1&format=json&filter=boom&before=1&after=1", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	createDefaultHandler(mockedClient).ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	body := rr.Body.Bytes()
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	f, err := zr.File[0].Open()
+	require.NoError(t, err)
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(content), []byte("\n"))
+	require.Len(t, lines, 3)
+	require.Contains(t, string(lines[0]), `"message":"INFO connecting","context":true`)
+	require.NotContains(t, string(lines[1]), `"context"`)
+	require.Contains(t, string(lines[2]), `"message":"INFO retrying","context":true`)
+}
+
+func Test_handler_download_logs_invalid_context_lines(t *testing.T) {
+	for _, query := range []string{"before=-1", "after=abc", "after=101"} {
+		req, err := http.NewRequest("GET", "/api/containers/localhost~123456/download?stdout=1&filter=boom&"+query, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		createDefaultHandler(nil).ServeHTTP(rr, req)
+		require.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
@@ -0,0 +1,158 @@
+package web
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	container_support "github.com/amir20/dozzle/internal/support/container"
+	support_web "github.com/amir20/dozzle/internal/support/web"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultHistogramBuckets is the number of buckets used when no interval is
+	// given.
+	defaultHistogramBuckets = 60
+	// maxHistogramBuckets bounds the response size.
+	maxHistogramBuckets = 1000
+)
+
+type histogramBucket struct {
+	Time   time.Time      `json:"time"`
+	Total  int            `json:"total"`
+	Levels map[string]int `json:"levels"`
+}
+
+type logHistogram struct {
+	From     time.Time         `json:"from"`
+	To       time.Time         `json:"to"`
+	Interval int64             `json:"interval"` // milliseconds
+	Total    int               `json:"total"`
+	Buckets  []histogramBucket `json:"buckets"`
+}
+
+func (h *handler) containerHistogram(w http.ResponseWriter, r *http.Request) {
+	h.histogramForContainers(w, r, containerFilter(r))
+}
+
+func (h *handler) mergedHistogram(w http.ResponseWriter, r *http.Request) {
+	h.histogramForContainers(w, r, mergedFilter(r))
+}
+
+func (h *handler) labelsHistogram(w http.ResponseWriter, r *http.Request) {
+	h.histogramForContainers(w, r, labelsFilter(r))
+}
+
+func (h *handler) groupHistogram(w http.ResponseWriter, r *http.Request) {
+	h.histogramForContainers(w, r, groupFilter(r))
+}
+
+func (h *handler) hostGroupHistogram(w http.ResponseWriter, r *http.Request) {
+	filter, ok := h.hostGroupFilter(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid group")
+		return
+	}
+	h.histogramForContainers(w, r, filter)
+}
+
+func (h *handler) hostHistogram(w http.ResponseWriter, r *http.Request) {
+	h.histogramForContainers(w, r, hostFilter(r))
+}
+
+// histogramForContainers counts the log lines of the matching containers per
+// interval and level. It accepts the filter, inverse and levels params of the
+// log streams; without levels every level is counted.
+func (h *handler) histogramForContainers(w http.ResponseWriter, r *http.Request, containerFilter container_support.ContainerFilter) {
+	from, to, err := parseTimeRange(r, time.Hour)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	interval := to.Sub(from) / defaultHistogramBuckets
+	if value := r.URL.Query().Get("interval"); value != "" {
+		interval, err = time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid interval")
+			return
+		}
+	}
+	interval = max(interval.Truncate(time.Second), time.Second)
+	buckets := int((to.Sub(from) + interval - 1) / interval)
+	if buckets > maxHistogramBuckets {
+		writeError(w, http.StatusBadRequest, "too many buckets, use a larger interval")
+		return
+	}
+
+	stdTypes := parseStdTypes(r)
+	if stdTypes == 0 {
+		stdTypes = container.STDALL
+	}
+
+	var filter *support_web.Filter
+	if r.URL.Query().Has("filter") {
+		filter, err = support_web.ParseFilter(r.URL.Query().Get("filter"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	inverse := r.URL.Query().Get("inverse") == "true"
+
+	levels := make(map[string]struct{})
+	for _, level := range r.URL.Query()["levels"] {
+		levels[level] = struct{}{}
+	}
+	if len(levels) == 0 {
+		levels = container.SupportedLogLevels
+	}
+
+	histogram := logHistogram{
+		From:     from,
+		To:       to,
+		Interval: interval.Milliseconds(),
+		Buckets:  make([]histogramBucket, buckets),
+	}
+	for i := range histogram.Buckets {
+		histogram.Buckets[i] = histogramBucket{Time: from.Add(time.Duration(i) * interval), Levels: make(map[string]int)}
+	}
+
+	userLabels := h.resolveLabels(r)
+	containers, errs := h.hostService.ListAllContainersFiltered(userLabels, containerFilter)
+	if len(errs) > 0 {
+		log.Warn().Err(errs[0]).Msg("error while listing containers")
+	}
+
+	for _, c := range containers {
+		containerService, err := h.hostService.FindContainer(c.Host, c.ID, userLabels)
+		if err != nil {
+			log.Error().Err(err).Msg("error while finding container")
+			continue
+		}
+
+		events, err := containerService.LogsBetweenDates(r.Context(), from, to, stdTypes)
+		if err != nil {
+			log.Error().Err(err).Str("container", c.ID).Msg("error fetching logs for histogram")
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		for event := range events {
+			if !matchesFilter(event, filter, levels, inverse) {
+				continue
+			}
+			timestamp := time.UnixMilli(event.Timestamp)
+			if timestamp.Before(from) || !timestamp.Before(to) {
+				continue
+			}
+			bucket := &histogram.Buckets[timestamp.Sub(from)/interval]
+			bucket.Total++
+			bucket.Levels[event.Level]++
+			histogram.Total++
+		}
+	}
+
+	writeJSON(w, http.StatusOK, histogram)
+}
@@ -0,0 +1,104 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func histogramClient(t *testing.T, id string, from, to time.Time) *MockedClient {
+	t.Helper()
+	var data []byte
+	data = append(data, makeMessage("2020-05-13T18:00:10.000000000Z INFO started\n", container.STDOUT)...)
+	data = append(data, makeMessage("2020-05-13T18:00:20.000000000Z ERROR failed\n", container.STDERR)...)
+	data = append(data, makeMessage("2020-05-13T18:01:30.000000000Z ERROR failed again\n", container.STDERR)...)
+
+	mockedClient := new(MockedClient)
+	mockedClient.On("ContainerLogsBetweenDates", mock.Anything, id, from, to, container.STDALL).Return(io.NopCloser(bytes.NewReader(data)), nil)
+	mockedClient.On("FindContainer", mock.Anything, id).Return(container.Container{ID: id, Host: "localhost"}, nil)
+	mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+	mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{
+		{ID: id, Name: "test", Host: "localhost", State: "running"},
+	}, nil)
+	mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil)
+	return mockedClient
+}
+
+func Test_handler_containerHistogram(t *testing.T) {
+	id := "123456"
+	from := time.Date(2020, 5, 13, 18, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Minute)
+
+	req, err := http.NewRequest("GET", "/api/hosts/localhost/containers/"+id+"/logs/histogram", nil)
+	require.NoError(t, err)
+	q := req.URL.Query()
+	q.Add("from", from.Format(time.RFC3339))
+	q.Add("to", to.Format(time.RFC3339))
+	q.Add("interval", "1m")
+	req.URL.RawQuery = q.Encode()
+
+	handler := createDefaultHandler(histogramClient(t, id, from, to))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var histogram logHistogram
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&histogram))
+	assert.Equal(t, int64(60000), histogram.Interval)
+	assert.Equal(t, 3, histogram.Total)
+	require.Len(t, histogram.Buckets, 3)
+	assert.Equal(t, map[string]int{"info": 1, "error": 1}, histogram.Buckets[0].Levels)
+	assert.Equal(t, map[string]int{"error": 1}, histogram.Buckets[1].Levels)
+	assert.Equal(t, 0, histogram.Buckets[2].Total)
+}
+
+func Test_handler_mergedHistogram_filtered(t *testing.T) {
+	id := "123456"
+	from := time.Date(2020, 5, 13, 18, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Minute)
+
+	req, err := http.NewRequest("GET", "/api/hosts/localhost/logs/mergedHistogram/"+id, nil)
+	require.NoError(t, err)
+	q := req.URL.Query()
+	q.Add("from", from.Format(time.RFC3339))
+	q.Add("to", to.Format(time.RFC3339))
+	q.Add("interval", "1m")
+	q.Add("filter", "again")
+	q.Add("inverse", "true")
+	q.Add("levels", "error")
+	req.URL.RawQuery = q.Encode()
+
+	handler := createDefaultHandler(histogramClient(t, id, from, to))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var histogram logHistogram
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&histogram))
+	assert.Equal(t, 1, histogram.Total)
+	assert.Equal(t, map[string]int{"error": 1}, histogram.Buckets[0].Levels)
+}
+
+func Test_handler_histogram_too_many_buckets(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/hosts/localhost/containers/123456/logs/histogram?interval=1s&from=2020-05-13T00:00:00Z&to=2020-05-14T00:00:00Z", nil)
+	require.NoError(t, err)
+
+	mockedClient := new(MockedClient)
+	mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+	mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{}, nil)
+	mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil)
+
+	handler := createDefaultHandler(mockedClient)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
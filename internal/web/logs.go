@@ -7,7 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -41,8 +40,57 @@ func parseStdTypes(r *http.Request) container.StdType {
 	return stdTypes
 }
 
-func matchesFilter(event *container.LogEvent, regex *regexp.Regexp, levels map[string]struct{}, inverse bool) bool {
-	if regex != nil && inverse == support_web.Search(regex, event) {
+// parseTimeRange reads the optional RFC 3339 from and to query params. A
+// missing to defaults to now and a missing from to window before to.
+func parseTimeRange(r *http.Request, window time.Duration) (time.Time, time.Time, error) {
+	return parseNamedTimeRange(r, "from", "to", window)
+}
+
+// parseNamedTimeRange is parseTimeRange with the names of the params, for
+// handlers that take more than one range.
+func parseNamedTimeRange(r *http.Request, fromKey string, toKey string, window time.Duration) (time.Time, time.Time, error) {
+	to := time.Now()
+	if value := r.URL.Query().Get(toKey); value != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid %s: %w", toKey, err)
+		}
+		to = parsed
+	}
+	from := to.Add(-window)
+	if value := r.URL.Query().Get(fromKey); value != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid %s: %w", fromKey, err)
+		}
+		from = parsed
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%s must be before %s", fromKey, toKey)
+	}
+	return from, to, nil
+}
+
+// parseContextLines reads the optional before and after query params, the
+// number of lines to send around each line matching the filter.
+func parseContextLines(r *http.Request) (int, int, error) {
+	counts := [2]int{}
+	for i, name := range []string{"before", "after"} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 0 || count > support_web.MaxContextLines {
+			return 0, 0, fmt.Errorf("%s must be between 0 and %d", name, support_web.MaxContextLines)
+		}
+		counts[i] = count
+	}
+	return counts[0], counts[1], nil
+}
+
+func matchesFilter(event *container.LogEvent, filter *support_web.Filter, levels map[string]struct{}, inverse bool) bool {
+	if filter != nil && inverse == filter.Match(event) {
 		return false
 	}
 	_, ok := levels[event.Level]
@@ -58,17 +106,28 @@ type searchStatus struct {
 	Reason    string    `json:"reason,omitempty"`
 }
 
+// droppedLogs reports live lines of a container skipped by its rate limiter.
+type droppedLogs struct {
+	ContainerID string         `json:"containerId"`
+	Count       int            `json:"count"`
+	Levels      map[string]int `json:"levels"`
+	Time        time.Time      `json:"time"`
+}
+
 func (h *handler) resolveLabels(r *http.Request) container.ContainerLabels {
 	labels := h.config.Labels
 	if h.config.Authorization.Provider != NONE {
-		user := auth.UserFromContext(r.Context())
-		if user.ContainerLabels.Exists() {
+		// Share links are served without a user.
+		if user := auth.UserFromContext(r.Context()); user != nil && user.ContainerLabels.Exists() {
 			labels = user.ContainerLabels
 		}
 	}
 	return labels
 }
 
+// truncatedHeader is set when more lines matched than /logs returns.
+const truncatedHeader = "X-Dozzle-Truncated"
+
 func (h *handler) fetchLogsBetweenDates(w http.ResponseWriter, r *http.Request) {
 	plainText := strings.Contains(r.Header.Get("Accept"), "text/plain")
 	if plainText {
@@ -95,9 +154,9 @@ func (h *handler) fetchLogsBetweenDates(w http.ResponseWriter, r *http.Request)
 
 	delta := max(to.Sub(from), time.Second*3)
 
-	var regex *regexp.Regexp
+	var filter *support_web.Filter
 	if r.URL.Query().Has("filter") {
-		regex, err = support_web.ParseRegex(r.URL.Query().Get("filter"))
+		filter, err = support_web.ParseFilter(r.URL.Query().Get("filter"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
@@ -106,6 +165,12 @@ func (h *handler) fetchLogsBetweenDates(w http.ResponseWriter, r *http.Request)
 
 	inverse := r.URL.Query().Get("inverse") == "true"
 
+	before, after, err := parseContextLines(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	onlyComplex := r.URL.Query().Has("jsonOnly")
 	everything := r.URL.Query().Has("everything")
 	if everything {
@@ -113,8 +178,9 @@ func (h *handler) fetchLogsBetweenDates(w http.ResponseWriter, r *http.Request)
 		to = time.Now()
 	}
 
+	// context lines come on top of the matches counted by the limits below
 	minimum := 0
-	buffer := utils.NewRingBuffer[*container.LogEvent](500)
+	buffer := support_web.NewContextBuffer(500)
 	if r.URL.Query().Has("min") {
 		minimum, err = strconv.Atoi(r.URL.Query().Get("min"))
 		if err != nil {
@@ -125,7 +191,7 @@ func (h *handler) fetchLogsBetweenDates(w http.ResponseWriter, r *http.Request)
 			http.Error(w, "minimum must be between 0 and buffer size", http.StatusBadRequest)
 			return
 		}
-		buffer = utils.NewRingBuffer[*container.LogEvent](minimum)
+		buffer = support_web.NewContextBuffer(minimum)
 	}
 
 	maxStart := math.MaxInt
@@ -179,7 +245,7 @@ func (h *handler) fetchLogsBetweenDates(w http.ResponseWriter, r *http.Request)
 
 	startIdFound := startId == 0
 	for {
-		if minimum > 0 && buffer.Len() >= minimum {
+		if minimum > 0 && buffer.Matches() >= minimum {
 			break
 		}
 
@@ -192,53 +258,62 @@ func (h *handler) fetchLogsBetweenDates(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
+		contextLines := support_web.NewContextLines(before, after)
+
+	scan:
 		for event := range events {
 			if everything {
 				if _, ok := event.Message.(string); onlyComplex && ok {
 					continue
 				}
-				if regex != nil && inverse == support_web.Search(regex, event) {
-					continue
-				}
+				matched := filter == nil || inverse != filter.Match(event)
 				if len(levels) > 0 {
 					if _, ok := levels[event.Level]; !ok {
-						continue
+						matched = false
 					}
 				}
-				if plainText {
-					// Expand grouped events into their fragment lines; grouped
-					// events store their lines in Message and have an empty
-					// RawMessage, so writing RawMessage alone drops every group.
-					fmt.Fprintf(writer, "%s\n", event.PlainText())
-				} else if err := encoder.Encode(event); err != nil {
-					log.Error().Err(err).Msg("error encoding log event")
+				for _, line := range contextLines.Next(event, matched) {
+					if plainText {
+						// Expand grouped events into their fragment lines; grouped
+						// events store their lines in Message and have an empty
+						// RawMessage, so writing RawMessage alone drops every group.
+						fmt.Fprintf(writer, "%s\n", line.PlainText())
+					} else if err := encoder.Encode(line); err != nil {
+						log.Error().Err(err).Msg("error encoding log event")
+					}
 				}
 				continue
 			}
 
-			if !matchesFilter(event, regex, levels, inverse) {
-				continue
+			lines := contextLines.Next(event, matchesFilter(event, filter, levels, inverse))
+			if len(lines) > 0 && !lines[len(lines)-1].Context && buffer.Matches() >= maxStart {
+				break scan
 			}
 
-			if !startIdFound {
-				if event.Id == startId {
-					log.Debug().Uint32("startId", startId).Msg("found start id, will include subsequent events")
-					startIdFound = true
+			kept := make([]*container.LogEvent, 0, len(lines))
+			lastSeen := false
+			for _, line := range lines {
+				if !startIdFound {
+					if line.Id == startId {
+						log.Debug().Uint32("startId", startId).Msg("found start id, will include subsequent events")
+						startIdFound = true
+					}
+					continue
 				}
-				continue
-			}
 
-			if lastSeenId != 0 && event.Id == lastSeenId {
-				log.Debug().Uint32("lastSeenId", lastSeenId).Msg("found last seen id")
-				break
-			}
+				if lastSeenId != 0 && line.Id == lastSeenId {
+					log.Debug().Uint32("lastSeenId", lastSeenId).Msg("found last seen id")
+					lastSeen = true
+					break
+				}
 
-			if buffer.Len() >= maxStart {
-				break
+				support_web.EscapeHTMLValues(line)
+				kept = append(kept, line)
+			}
+			buffer.Push(kept...)
+			if lastSeen {
+				break scan
 			}
-
-			support_web.EscapeHTMLValues(event)
-			buffer.Push(event)
 		}
 
 		if everything || from.Before(containerService.Container.Created) || minimum == 0 {
@@ -249,7 +324,11 @@ func (h *handler) fetchLogsBetweenDates(w http.ResponseWriter, r *http.Request)
 		delta = delta * 2
 	}
 
-	log.Debug().Int("buffer_size", buffer.Len()).Msg("sending logs to client")
+	log.Debug().Int("buffer_size", len(buffer.Data())).Msg("sending logs to client")
+	if buffer.Truncated() {
+		// only the newest lines of the range fit in the buffer
+		w.Header().Set(truncatedHeader, "true")
+	}
 
 	for _, event := range buffer.Data() {
 		if err := encoder.Encode(event); err != nil {
@@ -260,25 +339,57 @@ func (h *handler) fetchLogsBetweenDates(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *handler) streamContainerLogs(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-
-	h.streamLogsForContainers(w, r, func(container *container.Container) bool {
-		return container.ID == id && container.Host == hostKey(r)
-	})
+	h.streamLogsForContainers(w, r, containerFilter(r))
 }
 
 func (h *handler) streamLogsMerged(w http.ResponseWriter, r *http.Request) {
+	h.streamLogsForContainers(w, r, mergedFilter(r))
+}
+
+func (h *handler) streamLogsWithLabels(w http.ResponseWriter, r *http.Request) {
+	h.streamLogsForContainers(w, r, labelsFilter(r))
+}
+
+func (h *handler) streamGroupedLogs(w http.ResponseWriter, r *http.Request) {
+	h.streamLogsForContainers(w, r, groupFilter(r))
+}
+
+func (h *handler) streamHostGroupLogs(w http.ResponseWriter, r *http.Request) {
+	filter, ok := h.hostGroupFilter(r)
+	if !ok {
+		http.Error(w, "invalid group", http.StatusBadRequest)
+		return
+	}
+	h.streamLogsForContainers(w, r, filter)
+}
+
+func (h *handler) streamHostLogs(w http.ResponseWriter, r *http.Request) {
+	h.streamLogsForContainers(w, r, hostFilter(r))
+}
+
+// The filters below select the containers of each stream route. They are
+// shared with the matching histogram routes.
+
+func containerFilter(r *http.Request) container_support.ContainerFilter {
+	id := chi.URLParam(r, "id")
+	host := hostKey(r)
+	return func(container *container.Container) bool {
+		return container.ID == id && container.Host == host
+	}
+}
+
+func mergedFilter(r *http.Request) container_support.ContainerFilter {
 	ids := make(map[string]bool)
 	for id := range strings.SplitSeq(chi.URLParam(r, "ids"), ",") {
 		ids[id] = true
 	}
-
-	h.streamLogsForContainers(w, r, func(container *container.Container) bool {
-		return ids[container.ID] && container.Host == hostKey(r)
-	})
+	host := hostKey(r)
+	return func(container *container.Container) bool {
+		return ids[container.ID] && container.Host == host
+	}
 }
 
-func (h *handler) streamLogsWithLabels(w http.ResponseWriter, r *http.Request) {
+func labelsFilter(r *http.Request) container_support.ContainerFilter {
 	// Parse label filters from URL path
 	// Expected format: /labels/key1:value1,key2:value2/logs/stream
 	labelsParam := chi.URLParam(r, "labels")
@@ -293,7 +404,7 @@ func (h *handler) streamLogsWithLabels(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	h.streamLogsForContainers(w, r, func(container *container.Container) bool {
+	return func(container *container.Container) bool {
 		if container.State != "running" {
 			return false
 		}
@@ -306,22 +417,20 @@ func (h *handler) streamLogsWithLabels(w http.ResponseWriter, r *http.Request) {
 		}
 
 		return len(labelFilters) > 0
-	})
+	}
 }
 
-func (h *handler) streamGroupedLogs(w http.ResponseWriter, r *http.Request) {
+func groupFilter(r *http.Request) container_support.ContainerFilter {
 	group := chi.URLParam(r, "group")
-
-	h.streamLogsForContainers(w, r, func(container *container.Container) bool {
+	return func(container *container.Container) bool {
 		return container.State == "running" && container.Group == group
-	})
+	}
 }
 
-func (h *handler) streamHostGroupLogs(w http.ResponseWriter, r *http.Request) {
+func (h *handler) hostGroupFilter(r *http.Request) (container_support.ContainerFilter, bool) {
 	group, err := url.PathUnescape(chi.URLParam(r, "group"))
 	if err != nil || group == "" {
-		http.Error(w, "invalid group", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	hostIDs := make(map[string]struct{})
@@ -331,17 +440,17 @@ func (h *handler) streamHostGroupLogs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	h.streamLogsForContainers(w, r, func(c *container.Container) bool {
+	return func(c *container.Container) bool {
 		_, ok := hostIDs[c.Host]
 		return c.State == "running" && ok
-	})
+	}, true
 }
 
-func (h *handler) streamHostLogs(w http.ResponseWriter, r *http.Request) {
+func hostFilter(r *http.Request) container_support.ContainerFilter {
 	host := hostKey(r)
-	h.streamLogsForContainers(w, r, func(container *container.Container) bool {
+	return func(container *container.Container) bool {
 		return container.State == "running" && container.Host == host
-	})
+	}
 }
 
 func (h *handler) streamLogsForContainers(w http.ResponseWriter, r *http.Request, containerFilter container_support.ContainerFilter) {
@@ -384,10 +493,10 @@ func (h *handler) streamLogsForContainers(w http.ResponseWriter, r *http.Request
 		}
 	}
 
-	var regex *regexp.Regexp
+	var filter *support_web.Filter
 	if r.URL.Query().Has("filter") {
 		var err error
-		regex, err = support_web.ParseRegex(r.URL.Query().Get("filter"))
+		filter, err = support_web.ParseFilter(r.URL.Query().Get("filter"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
@@ -396,7 +505,13 @@ func (h *handler) streamLogsForContainers(w http.ResponseWriter, r *http.Request
 
 	inverse := r.URL.Query().Get("inverse") == "true"
 
-	if !allLogs || regex != nil || inverse {
+	before, after, err := parseContextLines(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !allLogs || filter != nil || inverse {
 		absoluteTime = time.Now()
 
 		go func() {
@@ -422,6 +537,7 @@ func (h *handler) streamLogsForContainers(w http.ResponseWriter, r *http.Request
 			}()
 			for minimum > 0 {
 				events := make([]*container.LogEvent, 0)
+				matches := 0
 				stillRunning := false
 				for _, container := range existingContainers {
 					containerService, err := h.hostService.FindContainer(container.Host, container.ID, userLabels)
@@ -441,11 +557,13 @@ func (h *handler) streamLogsForContainers(w http.ResponseWriter, r *http.Request
 						return
 					}
 
+					contextLines := support_web.NewContextLines(before, after)
 					for log := range logs {
-						if !matchesFilter(log, regex, levels, inverse) {
-							continue
+						matched := matchesFilter(log, filter, levels, inverse)
+						if matched {
+							matches++
 						}
-						events = append(events, log)
+						events = append(events, contextLines.Next(log, matched)...)
 					}
 
 					stillRunning = true
@@ -458,10 +576,10 @@ func (h *handler) streamLogsForContainers(w http.ResponseWriter, r *http.Request
 
 				to = to.Add(delta)
 				delta *= 2
-				minimum -= len(events)
-				found += len(events)
+				minimum -= matches
+				found += matches
 				sort.Slice(events, func(i, j int) bool {
-					return events[i].Timestamp < events[j].Timestamp
+					return events[i].SortTimestamp() < events[j].SortTimestamp()
 				})
 				if len(events) > 0 {
 					select {
@@ -477,7 +595,30 @@ func (h *handler) streamLogsForContainers(w http.ResponseWriter, r *http.Request
 		}()
 	}
 
-	streamLogs := func(c container.Container) {
+	// filterLogs forwards the matching lines of a single container, with their
+	// context, to liveLogs until containerLogs is closed. Lines are rate limited
+	// here, before the containers are merged, so a noisy container cannot use up
+	// the budget of quiet ones.
+	filterLogs := func(containerLogs <-chan *container.LogEvent, limiter *container.LogRateLimiter) {
+		contextLines := support_web.NewContextLines(before, after)
+		for logEvent := range containerLogs {
+			if r.Context().Err() != nil {
+				// keep draining so the stream is never blocked
+				continue
+			}
+			for _, line := range contextLines.Next(logEvent, matchesFilter(logEvent, filter, levels, inverse)) {
+				if limiter != nil && !limiter.Allow(line, time.Now()) {
+					continue
+				}
+				select {
+				case liveLogs <- line:
+				case <-r.Context().Done():
+				}
+			}
+		}
+	}
+
+	streamLogs := func(c container.Container, limiter *container.LogRateLimiter) {
 		containerService, err := h.hostService.FindContainer(c.Host, c.ID, userLabels)
 		if err != nil {
 			log.Error().Err(err).Msg("error while finding container")
@@ -485,7 +626,16 @@ func (h *handler) streamLogsForContainers(w http.ResponseWriter, r *http.Request
 		}
 		c = containerService.Container
 		start := utils.Max(absoluteTime, c.StartedAt)
-		err = containerService.StreamLogs(r.Context(), start, stdTypes, liveLogs)
+		containerLogs := make(chan *container.LogEvent)
+		filtered := make(chan struct{})
+		go func() {
+			defer close(filtered)
+			filterLogs(containerLogs, limiter)
+		}()
+		err = containerService.StreamLogs(r.Context(), start, stdTypes, containerLogs)
+		close(containerLogs)
+		// the last lines are sent before the container is reported as stopped
+		<-filtered
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				log.Debug().Str("container", c.ID).Msg("streaming ended")
@@ -509,8 +659,45 @@ func (h *handler) streamLogsForContainers(w http.ResponseWriter, r *http.Request
 		}
 	}
 
-	for _, container := range existingContainers {
-		go streamLogs(container)
+	// Containers ordered by message timestamps can emit lines whose timestamps
+	// are behind those already sent by others, so live events are held briefly
+	// and sorted once any such container is part of the stream.
+	var reorder *reorderBuffer
+	var reorderTick <-chan time.Time
+	enableReorder := func() {
+		if reorder != nil {
+			return
+		}
+		reorder = newReorderBuffer(liveReorderWindow)
+		reorderTicker := time.NewTicker(liveReorderWindow / 4)
+		reorderTick = reorderTicker.C
+		context.AfterFunc(r.Context(), reorderTicker.Stop)
+	}
+
+	// Noisy containers are rate limited; the counts of skipped lines are
+	// reported per container every second so the viewer can show the gap.
+	limiters := make(map[string]*container.LogRateLimiter)
+	var droppedTick <-chan time.Time
+	addLimiter := func(c container.Container) *container.LogRateLimiter {
+		limiter := container.NewLogRateLimiter(c)
+		if limiter == nil {
+			delete(limiters, c.ID)
+			return nil
+		}
+		limiters[c.ID] = limiter
+		if droppedTick == nil {
+			droppedTicker := time.NewTicker(time.Second)
+			droppedTick = droppedTicker.C
+			context.AfterFunc(r.Context(), droppedTicker.Stop)
+		}
+		return limiter
+	}
+
+	for _, c := range existingContainers {
+		if container.UsesMessageTimestamps(c) {
+			enableReorder()
+		}
+		go streamLogs(c, addLimiter(c))
 	}
 
 	newContainers := make(chan container.Container)
@@ -521,17 +708,47 @@ func (h *handler) streamLogsForContainers(w http.ResponseWriter, r *http.Request
 loop:
 	for {
 		select {
-		case logEvent := <-liveLogs:
-			if !matchesFilter(logEvent, regex, levels, inverse) {
+		case line := <-liveLogs:
+			if reorder != nil {
+				reorder.push(line, time.Now())
 				continue
 			}
 
-			support_web.EscapeHTMLValues(logEvent)
-			sseWriter.Message(logEvent)
+			support_web.EscapeHTMLValues(line)
+			sseWriter.Message(line)
+
+		case now := <-reorderTick:
+			for _, logEvent := range reorder.release(now) {
+				support_web.EscapeHTMLValues(logEvent)
+				sseWriter.Message(logEvent)
+			}
+
+		case now := <-droppedTick:
+			var dropped []droppedLogs
+			for id, limiter := range limiters {
+				levels := limiter.TakeDropped()
+				if levels == nil {
+					continue
+				}
+				count := 0
+				for _, n := range levels {
+					count += n
+				}
+				dropped = append(dropped, droppedLogs{ContainerID: id, Count: count, Levels: levels, Time: now})
+			}
+			if len(dropped) > 0 {
+				if err := sseWriter.Event("logs-dropped", dropped); err != nil {
+					log.Error().Err(err).Msg("error encoding dropped logs")
+				}
+			}
+
 		case c := <-newContainers:
 			if _, err := h.hostService.FindContainer(c.Host, c.ID, userLabels); err == nil {
 				events <- &container.ContainerEvent{ActorID: c.ID, Name: "container-started", Host: c.Host, Time: time.Now()}
-				go streamLogs(c)
+				if container.UsesMessageTimestamps(c) {
+					enableReorder()
+				}
+				go streamLogs(c, addLimiter(c))
 			}
 
 		case event := <-events:
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 	"net/url"
 	"regexp"
@@ -449,19 +450,121 @@ func Test_handler_between_dates_with_everything_complex(t *testing.T) {
 	mockedClient.AssertExpectations(t)
 }
 
+func Test_handler_between_dates_with_context_lines(t *testing.T) {
+	id := "123456"
+	req, err := http.NewRequest("GET", "/api/hosts/localhost/containers/"+id+"/logs", nil)
+	require.NoError(t, err, "NewRequest should not return an error.")
+
+	q := req.URL.Query()
+	q.Add("from", "2018-01-01T00:00:00Z")
+	q.Add("to", "2018-01-01T10:00:00Z")
+	q.Add("stdout", "true")
+	q.Add("filter", "boom")
+	q.Add("before", "1")
+	q.Add("after", "1")
+	addAllLogLevels(q)
+	req.URL.RawQuery = q.Encode()
+
+	data := makeMessage("2020-05-13T18:55:36.772853839Z INFO starting\n", container.STDOUT)
+	data = append(data, makeMessage("2020-05-13T18:55:37.772853839Z INFO connecting\n", container.STDOUT)...)
+	data = append(data, makeMessage("2020-05-13T18:55:38.772853839Z ERROR boom\n", container.STDOUT)...)
+	data = append(data, makeMessage("2020-05-13T18:55:39.772853839Z INFO retrying\n", container.STDOUT)...)
+	data = append(data, makeMessage("2020-05-13T18:55:40.772853839Z INFO connected\n", container.STDOUT)...)
+
+	mockedClient := new(MockedClient)
+	mockedClient.On("ContainerLogsBetweenDates", mock.Anything, id, mock.Anything, mock.Anything, container.STDOUT).
+		Return(io.NopCloser(bytes.NewReader(data)), nil)
+	mockedClient.On("FindContainer", mock.Anything, id).Return(container.Container{ID: id}, nil)
+	mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+	mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{
+		{ID: id, Name: "test", Host: "localhost", State: "running"},
+	}, nil)
+	mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil)
+
+	handler := createDefaultHandler(mockedClient)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "connecting")
+	assert.Contains(t, lines[0], `"ctx":true`)
+	assert.Contains(t, lines[1], "boom")
+	assert.NotContains(t, lines[1], `"ctx"`)
+	assert.Contains(t, lines[2], "retrying")
+	assert.Contains(t, lines[2], `"ctx":true`)
+}
+
+func Test_handler_between_dates_context_lines_do_not_count_toward_limits(t *testing.T) {
+	id := "123456"
+	start := time.Date(2020, 5, 13, 18, 0, 0, 0, time.UTC)
+	var data []byte
+	for i := range 600 {
+		for n, message := range []string{"INFO waiting", "ERROR boom", "INFO retrying"} {
+			at := start.Add(time.Duration(3*i+n) * time.Second).Format(time.RFC3339Nano)
+			data = append(data, makeMessage(at+" "+message+"\n", container.STDOUT)...)
+		}
+	}
+
+	fetch := func(extra url.Values) (matches int, context int) {
+		t.Helper()
+		mockedClient := new(MockedClient)
+		mockedClient.On("ContainerLogsBetweenDates", mock.Anything, id, mock.Anything, mock.Anything, container.STDOUT).
+			Return(io.NopCloser(bytes.NewReader(data)), nil)
+		mockedClient.On("FindContainer", mock.Anything, id).Return(container.Container{ID: id}, nil)
+		mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+		mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{
+			{ID: id, Name: "test", Host: "localhost", State: "running"},
+		}, nil)
+		mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil)
+		handler := createDefaultHandler(mockedClient)
+
+		q := url.Values{"from": {"2018-01-01T00:00:00Z"}, "to": {"2018-01-01T10:00:00Z"}, "stdout": {"true"}, "filter": {"boom"}, "before": {"1"}, "after": {"1"}}
+		addAllLogLevels(q)
+		for key, values := range extra {
+			q[key] = values
+		}
+		req, err := http.NewRequest("GET", "/api/hosts/localhost/containers/"+id+"/logs?"+q.Encode(), nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		for line := range strings.SplitSeq(strings.TrimSpace(rr.Body.String()), "\n") {
+			if strings.Contains(line, `"ctx":true`) {
+				context++
+			} else {
+				matches++
+			}
+		}
+		return matches, context
+	}
+
+	matches, context := fetch(nil)
+	assert.Equal(t, 500, matches, "the default cap counts matches")
+	assert.Equal(t, 1000, context)
+
+	matches, context = fetch(url.Values{"maxStart": {"4"}})
+	assert.Equal(t, 4, matches)
+	assert.Equal(t, 8, context)
+}
+
 func Test_matchesFilter_inverse(t *testing.T) {
 	levels := map[string]struct{}{"info": {}}
 
-	regex, err := support_web.ParseRegex("INFO")
+	regex, err := support_web.ParseFilter("INFO")
 	require.NoError(t, err)
 
-	regex2, err := support_web.ParseRegex("ERROR")
+	regex2, err := support_web.ParseFilter("ERROR")
+	require.NoError(t, err)
+
+	query, err := support_web.ParseFilter("?good AND NOT level:error")
 	require.NoError(t, err)
 
 	tests := []struct {
 		name    string
 		event   *container.LogEvent
-		regex   *regexp.Regexp
+		regex   *support_web.Filter
 		levels  map[string]struct{}
 		inverse bool
 		want    bool
@@ -506,6 +609,22 @@ func Test_matchesFilter_inverse(t *testing.T) {
 			inverse: true,
 			want:    true,
 		},
+		{
+			name:    "query: matching query passes",
+			event:   &container.LogEvent{Message: "INFO: all good", Level: "info"},
+			regex:   query,
+			levels:  levels,
+			inverse: false,
+			want:    true,
+		},
+		{
+			name:    "inverse mode: matching query fails (excluded)",
+			event:   &container.LogEvent{Message: "INFO: all good", Level: "info"},
+			regex:   query,
+			levels:  levels,
+			inverse: true,
+			want:    false,
+		},
 		{
 			name:    "inverse mode: wrong level fails regardless of regex",
 			event:   &container.LogEvent{Message: "ERROR: oops", Level: "error"},
@@ -532,3 +651,49 @@ func makeMessage(message string, stream container.StdType) []byte {
 
 	return data
 }
+
+func Test_handler_streamLogs_merged_rate_limited_per_container(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	req, err := http.NewRequestWithContext(ctx, "GET", "/api/hosts/localhost/logs/mergedStream/noisy,quiet", nil)
+	require.NoError(t, err, "NewRequest should not return an error.")
+	q := req.URL.Query()
+	q.Add("stdout", "true")
+	q.Add("stderr", "true")
+	addAllLogLevels(q)
+	req.URL.RawQuery = q.Encode()
+
+	// lines are a second apart so they are not grouped
+	var noisyLogs, quietLogs []byte
+	for i := range 5 {
+		noisyLogs = append(noisyLogs, makeMessage(fmt.Sprintf("2020-05-13T18:56:0%dZ INFO noisy %d\n", i, i), container.STDOUT)...)
+	}
+	for i := range 3 {
+		quietLogs = append(quietLogs, makeMessage(fmt.Sprintf("2020-05-13T18:56:0%dZ INFO quiet %d\n", i, i), container.STDOUT)...)
+	}
+
+	started := time.Date(2020, time.May, 13, 18, 55, 37, 772853839, time.UTC)
+	noisy := container.Container{ID: "noisy", Name: "noisy", Host: "localhost", State: "running", StartedAt: started, Labels: map[string]string{"dev.dozzle.rate-limit": "1"}}
+	quiet := container.Container{ID: "quiet", Name: "quiet", Host: "localhost", State: "running", StartedAt: started}
+
+	mockedClient := new(MockedClient)
+	mockedClient.On("FindContainer", mock.Anything, "noisy").Return(noisy, nil)
+	mockedClient.On("FindContainer", mock.Anything, "quiet").Return(quiet, nil)
+	mockedClient.On("ContainerLogs", mock.Anything, "noisy", started, container.STDALL).Return(io.NopCloser(bytes.NewReader(noisyLogs)), nil)
+	mockedClient.On("ContainerLogs", mock.Anything, "quiet", started, container.STDALL).Return(io.NopCloser(bytes.NewReader(quietLogs)), nil)
+	mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+	mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{noisy, quiet}, nil)
+	mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil)
+
+	// drops are reported once a second
+	time.AfterFunc(1500*time.Millisecond, cancel)
+
+	handler := createDefaultHandler(mockedClient)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	body := rr.Body.String()
+
+	assert.Equal(t, 3, strings.Count(body, `"c":"quiet"`), "the quiet container keeps all of its lines")
+	assert.Equal(t, 1, strings.Count(body, `"c":"noisy"`), "the noisy container is limited to its burst")
+	assert.Regexp(t, `event: logs-dropped\ndata: \[\{"containerId":"noisy","count":4`, body)
+	assert.NotContains(t, body, `"containerId":"quiet"`)
+}
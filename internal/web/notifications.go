@@ -1,8 +1,11 @@
 package web
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"sort"
 	"strconv"
@@ -31,9 +34,12 @@ type NotificationRuleResponse struct {
 	EventExpression     string              `json:"eventExpression,omitempty"`
 	Cooldown            int                 `json:"cooldown,omitempty"`
 	SampleWindow        int                 `json:"sampleWindow,omitempty"`
+	GroupBy             string              `json:"groupBy,omitempty"`
+	DigestWindow        int                 `json:"digestWindow,omitempty"`
 	TriggerCount        int64               `json:"triggerCount"`
 	TriggeredContainers int                 `json:"triggeredContainers"`
 	LastTriggeredAt     *time.Time          `json:"lastTriggeredAt"`
+	FiringCount         int                 `json:"firingCount"`
 	Dispatcher          *DispatcherResponse `json:"dispatcher"`
 }
 
@@ -45,6 +51,25 @@ type DispatcherResponse struct {
 	Template *string           `json:"template,omitempty"`
 	Headers  map[string]string `json:"headers,omitempty"`
 	Prefix   *string           `json:"prefix,omitempty"`
+
+	Channel   string `json:"channel,omitempty"`
+	Topic     string `json:"topic,omitempty"`
+	Priority  string `json:"priority,omitempty"`
+	User      string `json:"user,omitempty"`
+	DozzleURL string `json:"dozzleUrl,omitempty"`
+
+	// Settings of email dispatchers; Template is the HTML body template
+	SMTPHost     string   `json:"smtpHost,omitempty"`
+	SMTPPort     int      `json:"smtpPort,omitempty"`
+	SMTPSecurity string   `json:"smtpSecurity,omitempty"`
+	SMTPUsername string   `json:"smtpUsername,omitempty"`
+	From         string   `json:"from,omitempty"`
+	To           []string `json:"to,omitempty"`
+	Subject      string   `json:"subject,omitempty"`
+
+	// Secrets are never sent back, only whether they are set
+	TokenSet        bool `json:"tokenSet,omitempty"`
+	SMTPPasswordSet bool `json:"smtpPasswordSet,omitempty"`
 }
 
 type NotificationRuleInput struct {
@@ -57,6 +82,8 @@ type NotificationRuleInput struct {
 	EventExpression     string `json:"eventExpression,omitempty"`
 	Cooldown            int    `json:"cooldown,omitempty"`
 	SampleWindow        int    `json:"sampleWindow,omitempty"`
+	GroupBy             string `json:"groupBy,omitempty"`
+	DigestWindow        int    `json:"digestWindow,omitempty"`
 }
 
 type NotificationRuleUpdateInput struct {
@@ -69,6 +96,8 @@ type NotificationRuleUpdateInput struct {
 	EventExpression     *string `json:"eventExpression,omitempty"`
 	Cooldown            *int    `json:"cooldown,omitempty"`
 	SampleWindow        *int    `json:"sampleWindow,omitempty"`
+	GroupBy             *string `json:"groupBy,omitempty"`
+	DigestWindow        *int    `json:"digestWindow,omitempty"`
 }
 
 type DispatcherInput struct {
@@ -77,6 +106,24 @@ type DispatcherInput struct {
 	URL      *string           `json:"url,omitempty"`
 	Template *string           `json:"template,omitempty"`
 	Headers  map[string]string `json:"headers,omitempty"`
+
+	// Settings of native dispatchers
+	Channel   string `json:"channel,omitempty"`
+	Topic     string `json:"topic,omitempty"`
+	Priority  string `json:"priority,omitempty"`
+	Token     string `json:"token,omitempty"`
+	User      string `json:"user,omitempty"`
+	DozzleURL string `json:"dozzleUrl,omitempty"`
+
+	// Settings of email dispatchers; Template is the HTML body template
+	SMTPHost     string   `json:"smtpHost,omitempty"`
+	SMTPPort     int      `json:"smtpPort,omitempty"`
+	SMTPSecurity string   `json:"smtpSecurity,omitempty"`
+	SMTPUsername string   `json:"smtpUsername,omitempty"`
+	SMTPPassword string   `json:"smtpPassword,omitempty"`
+	From         string   `json:"from,omitempty"`
+	To           []string `json:"to,omitempty"`
+	Subject      string   `json:"subject,omitempty"`
 }
 
 type PreviewInput struct {
@@ -97,10 +144,11 @@ type PreviewResult struct {
 	MessageKeys       []string              `json:"messageKeys,omitempty"`
 }
 
+// TestWebhookInput is a dispatcher to test. Type defaults to webhook. When ID
+// is set, blank secrets are taken from that saved dispatcher.
 type TestWebhookInput struct {
-	URL      string            `json:"url"`
-	Template *string           `json:"template,omitempty"`
-	Headers  map[string]string `json:"headers,omitempty"`
+	DispatcherInput
+	ID int `json:"id,omitempty"`
 }
 
 type TestWebhookResult struct {
@@ -118,10 +166,12 @@ func subscriptionToResponse(sub *notification.Subscription, dispatchers []notifi
 
 	triggerCount := sub.TriggerCount.Load()
 	triggeredContainers := sub.TriggeredContainersCount()
+	firingCount := len(sub.FiringAlerts())
 
 	// Merge agent stats if available
 	if as, ok := agentStats[sub.ID]; ok {
 		triggerCount += as.TriggerCount
+		firingCount += len(as.FiringAlerts)
 
 		if as.LastTriggeredAt != nil && (lastTriggeredAt == nil || as.LastTriggeredAt.After(*lastTriggeredAt)) {
 			lastTriggeredAt = as.LastTriggeredAt
@@ -161,9 +211,12 @@ func subscriptionToResponse(sub *notification.Subscription, dispatchers []notifi
 		EventExpression:     sub.EventExpression,
 		Cooldown:            sub.Cooldown,
 		SampleWindow:        sub.SampleWindow,
+		GroupBy:             sub.GroupBy,
+		DigestWindow:        sub.DigestWindow,
 		TriggerCount:        triggerCount,
 		LastTriggeredAt:     lastTriggeredAt,
 		TriggeredContainers: triggeredContainers,
+		FiringCount:         firingCount,
 	}
 }
 
@@ -185,14 +238,163 @@ func dispatcherConfigToResponse(d *notification.DispatcherConfig) *DispatcherRes
 		prefix = &d.Prefix
 	}
 	return &DispatcherResponse{
-		ID:       d.ID,
-		Name:     d.Name,
-		Type:     d.Type,
-		URL:      url,
-		Template: template,
-		Headers:  headers,
-		Prefix:   prefix,
+		ID:           d.ID,
+		Name:         d.Name,
+		Type:         d.Type,
+		URL:          url,
+		Template:     template,
+		Headers:      headers,
+		Prefix:       prefix,
+		Channel:      d.Channel,
+		Topic:        d.Topic,
+		Priority:     d.Priority,
+		User:         d.User,
+		DozzleURL:    d.DozzleURL,
+		SMTPHost:     d.SMTPHost,
+		SMTPPort:     d.SMTPPort,
+		SMTPSecurity: d.SMTPSecurity,
+		SMTPUsername: d.SMTPUsername,
+		From:         d.From,
+		To:           d.To,
+		Subject:      d.Subject,
+
+		TokenSet:        d.Token != "",
+		SMTPPasswordSet: d.SMTPPassword != "",
+	}
+}
+
+// dispatcherConfigToInput returns the settings of a saved dispatcher,
+// secrets included, as they would be sent to create it.
+func dispatcherConfigToInput(d *notification.DispatcherConfig) DispatcherInput {
+	input := DispatcherInput{
+		Name:         d.Name,
+		Type:         d.Type,
+		Channel:      d.Channel,
+		Topic:        d.Topic,
+		Priority:     d.Priority,
+		Token:        d.Token,
+		User:         d.User,
+		DozzleURL:    d.DozzleURL,
+		SMTPHost:     d.SMTPHost,
+		SMTPPort:     d.SMTPPort,
+		SMTPSecurity: d.SMTPSecurity,
+		SMTPUsername: d.SMTPUsername,
+		SMTPPassword: d.SMTPPassword,
+		From:         d.From,
+		To:           d.To,
+		Subject:      d.Subject,
+	}
+	if d.URL != "" {
+		input.URL = &d.URL
+	}
+	if d.Template != "" {
+		input.Template = &d.Template
+	}
+	if len(d.Headers) > 0 {
+		input.Headers = d.Headers
+	}
+	return input
+}
+
+// findDispatcher returns the saved dispatcher with id, except cloud ones.
+func (h *handler) findDispatcher(id int) (notification.DispatcherConfig, bool) {
+	for _, config := range h.hostService.Dispatchers() {
+		if config.ID == id && config.Type != "cloud" {
+			return config, true
+		}
+	}
+	return notification.DispatcherConfig{}, false
+}
+
+// keepStoredSecrets fills the blank secrets of input from the saved
+// dispatcher with id, since responses leave them out.
+func (h *handler) keepStoredSecrets(id int, input *DispatcherInput) {
+	stored, ok := h.findDispatcher(id)
+	if !ok || stored.Type != input.Type {
+		return
+	}
+	if input.Token == "" {
+		input.Token = stored.Token
+	}
+	if input.SMTPPassword == "" {
+		input.SMTPPassword = stored.SMTPPassword
+	}
+}
+
+// newDispatcher creates and validates the dispatcher described by input.
+func newDispatcher(input DispatcherInput) (dispatcher.Dispatcher, error) {
+	url := ""
+	if input.URL != nil {
+		url = *input.URL
+	}
+
+	switch {
+	case input.Type == "webhook":
+		templateStr := ""
+		if input.Template != nil {
+			templateStr = *input.Template
+		}
+		return dispatcher.NewWebhookDispatcher(input.Name, url, templateStr, input.Headers)
+	case dispatcher.IsNativeType(input.Type):
+		return dispatcher.NewNativeDispatcher(input.Name, input.Type, dispatcher.NativeSettings{
+			URL:       url,
+			Channel:   input.Channel,
+			Topic:     input.Topic,
+			Priority:  input.Priority,
+			Token:     input.Token,
+			User:      input.User,
+			DozzleURL: input.DozzleURL,
+		})
+	case input.Type == dispatcher.TypeEmail:
+		body := ""
+		if input.Template != nil {
+			body = *input.Template
+		}
+		return dispatcher.NewEmailDispatcher(input.Name, dispatcher.EmailSettings{
+			Host:      input.SMTPHost,
+			Port:      input.SMTPPort,
+			Security:  input.SMTPSecurity,
+			Username:  input.SMTPUsername,
+			Password:  input.SMTPPassword,
+			From:      input.From,
+			To:        input.To,
+			Subject:   input.Subject,
+			Body:      body,
+			DozzleURL: input.DozzleURL,
+		})
+	default:
+		return nil, errors.New("unknown dispatcher type")
+	}
+}
+
+// dispatcherInputToResponse echoes a saved dispatcher back to the client.
+func dispatcherInputToResponse(id int, input DispatcherInput) *DispatcherResponse {
+	resp := &DispatcherResponse{
+		ID:           id,
+		Name:         input.Name,
+		Type:         input.Type,
+		URL:          input.URL,
+		Template:     input.Template,
+		Channel:      input.Channel,
+		Topic:        input.Topic,
+		Priority:     input.Priority,
+		User:         input.User,
+		DozzleURL:    input.DozzleURL,
+		SMTPHost:     input.SMTPHost,
+		SMTPPort:     input.SMTPPort,
+		SMTPSecurity: input.SMTPSecurity,
+		SMTPUsername: input.SMTPUsername,
+		From:         input.From,
+		To:           input.To,
+		Subject:      input.Subject,
+
+		TokenSet:        input.Token != "",
+		SMTPPasswordSet: input.SMTPPassword != "",
 	}
+	if len(input.Headers) > 0 {
+		resp.Headers = input.Headers
+	}
+	return resp
 }
 
 func writeJSON(w http.ResponseWriter, status int, data any) {
@@ -221,6 +423,99 @@ func (h *handler) listNotificationRules(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, http.StatusOK, rules)
 }
 
+// listFiringAlerts lists the metric and event alerts that are firing on this host and its agents, newest first
+func (h *handler) listFiringAlerts(w http.ResponseWriter, r *http.Request) {
+	alerts := make([]types.FiringAlert, 0)
+	names := make(map[int]string)
+	for _, sub := range h.hostService.Subscriptions() {
+		names[sub.ID] = sub.Name
+		alerts = append(alerts, sub.FiringAlerts()...)
+	}
+	for _, stats := range h.hostService.FetchAgentNotificationStats() {
+		for _, alert := range stats.FiringAlerts {
+			alert.SubscriptionName = names[alert.SubscriptionID]
+			alerts = append(alerts, alert)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Since.After(alerts[j].Since)
+	})
+	writeJSON(w, http.StatusOK, alerts)
+}
+
+// maxDeliveriesLimit bounds the deliveries returned by a single request
+const maxDeliveriesLimit = 1000
+
+// listNotificationDeliveries lists the deliveries of notifications sent by this host and its agents,
+// newest first. Query params: subscriptionId, dispatcherId, type, success, container, since, until and limit.
+func (h *handler) listNotificationDeliveries(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseDeliveryFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deliveries := h.hostService.NotificationDeliveries()
+	deliveries = append(deliveries, h.hostService.FetchAgentNotificationDeliveries()...)
+	writeJSON(w, http.StatusOK, notification.FilterDeliveries(deliveries, filter))
+}
+
+func parseDeliveryFilter(r *http.Request) (notification.DeliveryFilter, error) {
+	query := r.URL.Query()
+	filter := notification.DeliveryFilter{
+		Type:      types.NotificationType(query.Get("type")),
+		Container: query.Get("container"),
+		Limit:     100,
+	}
+
+	if value := query.Get("subscriptionId"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, errors.New("invalid subscriptionId")
+		}
+		filter.SubscriptionID = id
+	}
+	if value := query.Get("dispatcherId"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, errors.New("invalid dispatcherId")
+		}
+		filter.DispatcherID = &id
+	}
+	switch filter.Type {
+	case "", types.LogNotification, types.MetricNotification, types.EventNotification:
+	default:
+		return filter, fmt.Errorf("invalid type %q: must be log, metric or event", filter.Type)
+	}
+	if value := query.Get("success"); value != "" {
+		success, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, errors.New("invalid success")
+		}
+		filter.Success = &success
+	}
+	for _, param := range []struct {
+		key string
+		t   *time.Time
+	}{{"since", &filter.Since}, {"until", &filter.Until}} {
+		if value := query.Get(param.key); value != "" {
+			parsed, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s: %w", param.key, err)
+			}
+			*param.t = parsed
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxDeliveriesLimit {
+			return filter, fmt.Errorf("invalid limit: must be between 1 and %d", maxDeliveriesLimit)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
 func (h *handler) getNotificationRule(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
@@ -256,6 +551,13 @@ func (h *handler) createNotificationRule(w http.ResponseWriter, r *http.Request)
 		EventExpression:     input.EventExpression,
 		Cooldown:            input.Cooldown,
 		SampleWindow:        input.SampleWindow,
+		GroupBy:             input.GroupBy,
+		DigestWindow:        input.DigestWindow,
+	}
+
+	if err := sub.ValidateAlertKind(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
 	if err := h.hostService.AddSubscription(sub); err != nil {
@@ -290,6 +592,13 @@ func (h *handler) replaceNotificationRule(w http.ResponseWriter, r *http.Request
 		EventExpression:     input.EventExpression,
 		Cooldown:            input.Cooldown,
 		SampleWindow:        input.SampleWindow,
+		GroupBy:             input.GroupBy,
+		DigestWindow:        input.DigestWindow,
+	}
+
+	if err := sub.ValidateAlertKind(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
 	if err := h.hostService.ReplaceSubscription(sub); err != nil {
@@ -341,6 +650,28 @@ func (h *handler) updateNotificationRule(w http.ResponseWriter, r *http.Request)
 	if input.SampleWindow != nil {
 		updates["sampleWindow"] = *input.SampleWindow
 	}
+	if input.GroupBy != nil {
+		updates["groupBy"] = *input.GroupBy
+	}
+	if input.DigestWindow != nil {
+		updates["digestWindow"] = *input.DigestWindow
+	}
+
+	// The rule must still alert on one kind of input after the update
+	for _, sub := range h.hostService.Subscriptions() {
+		if sub.ID != id {
+			continue
+		}
+		updated := notification.Subscription{
+			LogExpression:    *cmp.Or(input.LogExpression, &sub.LogExpression),
+			MetricExpression: *cmp.Or(input.MetricExpression, &sub.MetricExpression),
+			EventExpression:  *cmp.Or(input.EventExpression, &sub.EventExpression),
+		}
+		if err := updated.ValidateAlertKind(); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
 
 	if err := h.hostService.UpdateSubscription(id, updates); err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
@@ -404,41 +735,15 @@ func (h *handler) createDispatcher(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var d dispatcher.Dispatcher
-	switch input.Type {
-	case "webhook":
-		url := ""
-		if input.URL != nil {
-			url = *input.URL
-		}
-		templateStr := ""
-		if input.Template != nil {
-			templateStr = *input.Template
-		}
-		webhook, err := dispatcher.NewWebhookDispatcher(input.Name, url, templateStr, input.Headers)
-		if err != nil {
-			writeError(w, http.StatusBadRequest, err.Error())
-			return
-		}
-		d = webhook
-	default:
-		writeError(w, http.StatusBadRequest, "unknown dispatcher type")
+	d, err := newDispatcher(input)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	id := h.hostService.AddDispatcher(d)
 
-	resp := &DispatcherResponse{
-		ID:       id,
-		Name:     input.Name,
-		Type:     input.Type,
-		URL:      input.URL,
-		Template: input.Template,
-	}
-	if len(input.Headers) > 0 {
-		resp.Headers = input.Headers
-	}
-	writeJSON(w, http.StatusCreated, resp)
+	writeJSON(w, http.StatusCreated, dispatcherInputToResponse(id, input))
 }
 
 func (h *handler) updateDispatcher(w http.ResponseWriter, r *http.Request) {
@@ -453,42 +758,45 @@ func (h *handler) updateDispatcher(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
+	h.keepStoredSecrets(id, &input)
 
-	var d dispatcher.Dispatcher
-	switch input.Type {
-	case "webhook":
-		url := ""
-		if input.URL != nil {
-			url = *input.URL
-		}
-		templateStr := ""
-		if input.Template != nil {
-			templateStr = *input.Template
-		}
-		webhook, err := dispatcher.NewWebhookDispatcher(input.Name, url, templateStr, input.Headers)
-		if err != nil {
-			writeError(w, http.StatusBadRequest, err.Error())
-			return
-		}
-		d = webhook
-	default:
-		writeError(w, http.StatusBadRequest, "unknown dispatcher type")
+	d, err := newDispatcher(input)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	h.hostService.UpdateDispatcher(id, d)
 
-	resp := &DispatcherResponse{
-		ID:       id,
-		Name:     input.Name,
-		Type:     input.Type,
-		URL:      input.URL,
-		Template: input.Template,
+	writeJSON(w, http.StatusOK, dispatcherInputToResponse(id, input))
+}
+
+// duplicateDispatcher saves a copy of a dispatcher, secrets included, since
+// clients can't read them to send them again.
+func (h *handler) duplicateDispatcher(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
 	}
-	if len(input.Headers) > 0 {
-		resp.Headers = input.Headers
+
+	config, ok := h.findDispatcher(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "dispatcher not found")
+		return
+	}
+	input := dispatcherConfigToInput(&config)
+	input.Name = "Copy of " + input.Name
+
+	d, err := newDispatcher(input)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
 	}
-	writeJSON(w, http.StatusOK, resp)
+
+	newID := h.hostService.AddDispatcher(d)
+
+	writeJSON(w, http.StatusCreated, dispatcherInputToResponse(newID, input))
 }
 
 func (h *handler) deleteDispatcher(w http.ResponseWriter, r *http.Request) {
@@ -656,12 +964,15 @@ func (h *handler) testWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	templateStr := ""
-	if input.Template != nil {
-		templateStr = *input.Template
+	input.Name = "test"
+	if input.Type == "" {
+		input.Type = "webhook"
+	}
+	if input.ID > 0 {
+		h.keepStoredSecrets(input.ID, &input.DispatcherInput)
 	}
 
-	webhook, err := dispatcher.NewWebhookDispatcher("test", input.URL, templateStr, input.Headers)
+	d, err := newDispatcher(input.DispatcherInput)
 	if err != nil {
 		errStr := err.Error()
 		writeJSON(w, http.StatusOK, &TestWebhookResult{
@@ -671,6 +982,40 @@ func (h *handler) testWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.sendTestNotification(w, r, d)
+}
+
+// testDispatcherByID sends a test notification through a saved dispatcher, so
+// stored secrets such as SMTP passwords don't have to be sent again.
+func (h *handler) testDispatcherByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	config, ok := h.findDispatcher(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "dispatcher not found")
+		return
+	}
+	d, err := newDispatcher(dispatcherConfigToInput(&config))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.sendTestNotification(w, r, d)
+}
+
+// sendTestNotification sends a sample log alert through d and writes the
+// result. Test sends wait for the same slots as alerts.
+func (h *handler) sendTestNotification(w http.ResponseWriter, r *http.Request, d dispatcher.Dispatcher) {
+	tester, ok := d.(dispatcher.Tester)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "dispatcher type cannot be tested")
+		return
+	}
+
 	mockNotification := types.Notification{
 		ID:        "test-notification",
 		Type:      types.LogNotification,
@@ -686,6 +1031,7 @@ func (h *handler) testWebhook(w http.ResponseWriter, r *http.Request) {
 			HostName: "localhost",
 			Labels:   map[string]string{"env": "test"},
 		},
+		Subscription: types.SubscriptionConfig{Name: "Test notification"},
 		Log: &types.NotificationLog{
 			ID:        1,
 			Message:   "This is a test log message from Dozzle",
@@ -696,7 +1042,7 @@ func (h *handler) testWebhook(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	result := webhook.SendTest(r.Context(), mockNotification)
+	result := h.hostService.SendTestNotification(r.Context(), tester, mockNotification)
 
 	var statusCode *int
 	if result.StatusCode > 0 {
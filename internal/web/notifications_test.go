@@ -0,0 +1,195 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/amir20/dozzle/internal/notification"
+	"github.com/amir20/dozzle/internal/notification/dispatcher"
+	"github.com/amir20/dozzle/types"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notificationHostService keeps dispatchers in a notification manager that is
+// never started.
+type notificationHostService struct {
+	HostService
+	manager *notification.Manager
+}
+
+func (s notificationHostService) AddSubscription(sub *notification.Subscription) error {
+	return s.manager.AddSubscription(sub)
+}
+
+func (s notificationHostService) UpdateSubscription(id int, updates map[string]any) error {
+	return s.manager.UpdateSubscription(id, updates)
+}
+
+func (s notificationHostService) Subscriptions() []*notification.Subscription {
+	return s.manager.Subscriptions()
+}
+
+func (s notificationHostService) FetchAgentNotificationStats() map[int]types.SubscriptionStats {
+	return nil
+}
+
+func (s notificationHostService) AddDispatcher(d dispatcher.Dispatcher) int {
+	return s.manager.AddDispatcher(d)
+}
+
+func (s notificationHostService) UpdateDispatcher(id int, d dispatcher.Dispatcher) {
+	s.manager.UpdateDispatcher(id, d)
+}
+
+func (s notificationHostService) Dispatchers() []notification.DispatcherConfig {
+	return s.manager.Dispatchers()
+}
+
+func (s notificationHostService) SendTestNotification(ctx context.Context, tester dispatcher.Tester, n types.Notification) dispatcher.TestResult {
+	return s.manager.SendTest(ctx, tester, n)
+}
+
+func createNotificationHandler(t *testing.T) (*chi.Mux, *notification.Manager) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	manager := notification.NewManager(
+		notification.NewContainerLogListener(ctx, nil),
+		notification.NewContainerStatsListener(ctx, nil),
+		notification.NewContainerEventListener(ctx, nil),
+	)
+
+	h := newTestHandler(nil, nil, Config{Base: "/", Authorization: Authorization{Provider: NONE}})
+	h.hostService = notificationHostService{HostService: h.hostService, manager: manager}
+	return createRouter(h), manager
+}
+
+func Test_handler_createDispatcher_validatesNativeSettings(t *testing.T) {
+	handler := createDefaultHandler(nil)
+
+	rr := serveView(t, handler, "POST", "/api/notifications/dispatchers", `{"name":"Phone","type":"ntfy","priority":"high"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "ntfy requires a topic")
+
+	rr = serveView(t, handler, "POST", "/api/notifications/dispatchers", `{"name":"Chat","type":"irc"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "unknown dispatcher type")
+}
+
+func Test_handler_testWebhook_nativeType(t *testing.T) {
+	handler, _ := createNotificationHandler(t)
+
+	rr := serveView(t, handler, "POST", "/api/notifications/test-webhook", `{"type":"pushover","token":"app"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var result TestWebhookResult
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+	assert.False(t, result.Success)
+	require.NotNil(t, result.Error)
+	assert.Contains(t, *result.Error, "pushover requires an application token and a user key")
+
+	// Loopback targets are refused like webhooks, so the test never leaves the host.
+	rr = serveView(t, handler, "POST", "/api/notifications/test-webhook", `{"type":"slack","url":"http://127.0.0.1:1/hook"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+	assert.False(t, result.Success)
+	require.NotNil(t, result.Error)
+	assert.Contains(t, *result.Error, "blocked address")
+}
+
+func Test_handler_createDispatcher_validatesEmailSettings(t *testing.T) {
+	handler, _ := createNotificationHandler(t)
+
+	rr := serveView(t, handler, "POST", "/api/notifications/dispatchers", `{"name":"On-call","type":"email","smtpHost":"smtp.example.com","from":"dozzle@example.com"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "email requires at least one recipient")
+
+	rr = serveView(t, handler, "POST", "/api/notifications/test-webhook", `{"type":"email","smtpHost":"smtp.example.com","smtpSecurity":"ssl","from":"dozzle@example.com","to":["oncall@example.com"]}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var result TestWebhookResult
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+	assert.False(t, result.Success)
+	require.NotNil(t, result.Error)
+	assert.Contains(t, *result.Error, "invalid SMTP security")
+}
+
+func Test_handler_listNotificationDeliveries_validatesFilter(t *testing.T) {
+	handler := createDefaultHandler(nil)
+
+	for query, message := range map[string]string{
+		"subscriptionId=errors": "invalid subscriptionId",
+		"dispatcherId=slack":    "invalid dispatcherId",
+		"type=digest":           "invalid type",
+		"success=maybe":         "invalid success",
+		"since=yesterday":       "invalid since",
+		"limit=5000":            "invalid limit",
+	} {
+		rr := serveView(t, handler, "GET", "/api/notifications/history?"+query, "")
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+		assert.Contains(t, rr.Body.String(), message, query)
+	}
+}
+
+func Test_handler_dispatchers_hideSecrets(t *testing.T) {
+	handler, manager := createNotificationHandler(t)
+
+	rr := serveView(t, handler, "POST", "/api/notifications/dispatchers", `{"name":"Phone","type":"ntfy","topic":"alerts","token":"tk_secret"}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	assert.NotContains(t, rr.Body.String(), "tk_secret")
+	assert.Contains(t, rr.Body.String(), `"tokenSet":true`)
+
+	rr = serveView(t, handler, "POST", "/api/notifications/dispatchers", `{"name":"On-call","type":"email","smtpHost":"smtp.example.com","smtpUsername":"dozzle","smtpPassword":"app-password","from":"dozzle@example.com","to":["oncall@example.com"]}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	assert.NotContains(t, rr.Body.String(), "app-password")
+
+	rr = serveView(t, handler, "GET", "/api/notifications/dispatchers", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "tk_secret")
+	assert.NotContains(t, rr.Body.String(), "app-password")
+	var dispatchers []DispatcherResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &dispatchers))
+	require.Len(t, dispatchers, 2)
+	assert.True(t, dispatchers[0].TokenSet)
+	assert.True(t, dispatchers[1].SMTPPasswordSet)
+
+	// Blank secrets keep the stored ones
+	rr = serveView(t, handler, "PUT", "/api/notifications/dispatchers/1", `{"name":"Phones","type":"ntfy","topic":"alerts"}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = serveView(t, handler, "PUT", "/api/notifications/dispatchers/2", `{"name":"On-call","type":"email","smtpHost":"smtp.example.com","smtpUsername":"dozzle","from":"dozzle@example.com","to":["oncall@example.com"]}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = serveView(t, handler, "POST", "/api/notifications/dispatchers/1/duplicate", "")
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	configs := manager.Dispatchers()
+	require.Len(t, configs, 3)
+	assert.Equal(t, "Phones", configs[0].Name)
+	assert.Equal(t, "tk_secret", configs[0].Token)
+	assert.Equal(t, "app-password", configs[1].SMTPPassword)
+	assert.Equal(t, "Copy of Phones", configs[2].Name)
+	assert.Equal(t, "tk_secret", configs[2].Token)
+}
+
+func Test_handler_notificationRules_oneAlertKind(t *testing.T) {
+	handler, manager := createNotificationHandler(t)
+
+	rr := serveView(t, handler, "POST", "/api/notifications/rules", `{"name":"API","containerExpression":"true","logExpression":"level == \"error\"","metricExpression":"cpu > 80"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "only one of log, metric and event expressions can be set")
+
+	rr = serveView(t, handler, "POST", "/api/notifications/rules", `{"name":"API","containerExpression":"true","logExpression":"level == \"error\""}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = serveView(t, handler, "PATCH", "/api/notifications/rules/1", `{"metricExpression":"cpu > 80"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "only one of log, metric and event expressions can be set")
+
+	rr = serveView(t, handler, "PATCH", "/api/notifications/rules/1", `{"logExpression":"","metricExpression":"cpu > 80"}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "cpu > 80", manager.Subscriptions()[0].MetricExpression)
+
+	rr = serveView(t, handler, "PUT", "/api/notifications/rules/1", `{"name":"API","containerExpression":"true","metricExpression":"cpu > 80","eventExpression":"name == \"die\""}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
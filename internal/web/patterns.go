@@ -0,0 +1,51 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/patterns"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// fetchLogPatterns clusters the logs of a container between from and to into
+// recurring message templates, most frequent first.
+func (h *handler) fetchLogPatterns(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseTimeRange(r, time.Hour)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := patterns.DefaultLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	stdTypes := parseStdTypes(r)
+	if stdTypes == 0 {
+		stdTypes = container.STDALL
+	}
+
+	containerService, err := h.hostService.FindContainer(hostKey(r), chi.URLParam(r, "id"), h.resolveLabels(r))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	events, err := containerService.LogsBetweenDates(r.Context(), from, to, stdTypes)
+	if err != nil {
+		log.Error().Err(err).Msg("error fetching logs for patterns")
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, patterns.Summarize(events, limit))
+}
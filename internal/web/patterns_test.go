@@ -0,0 +1,75 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/patterns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_handler_fetchLogPatterns(t *testing.T) {
+	id := "123456"
+	req, err := http.NewRequest("GET", "/api/hosts/localhost/containers/"+id+"/patterns", nil)
+	require.NoError(t, err, "NewRequest should not return an error.")
+
+	from, _ := time.Parse(time.RFC3339, "2018-01-01T00:00:00Z")
+	to, _ := time.Parse(time.RFC3339, "2018-01-01T10:00:00Z")
+
+	q := req.URL.Query()
+	q.Add("from", from.Format(time.RFC3339))
+	q.Add("to", to.Format(time.RFC3339))
+	req.URL.RawQuery = q.Encode()
+
+	mockedClient := new(MockedClient)
+
+	var data []byte
+	data = append(data, makeMessage("2020-05-13T18:55:37.772853839Z INFO user 1 logged in\n", container.STDOUT)...)
+	data = append(data, makeMessage("2020-05-13T18:55:38.772853839Z INFO user 2 logged in\n", container.STDOUT)...)
+	data = append(data, makeMessage("2020-05-13T18:56:37.772853839Z ERROR disk full\n", container.STDERR)...)
+
+	mockedClient.On("ContainerLogsBetweenDates", mock.Anything, id, from, to, container.STDALL).Return(io.NopCloser(bytes.NewReader(data)), nil)
+	mockedClient.On("FindContainer", mock.Anything, id).Return(container.Container{ID: id}, nil)
+	mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+	mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{
+		{ID: id, Name: "test", Host: "localhost", State: "running"},
+	}, nil)
+	mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil)
+
+	handler := createDefaultHandler(mockedClient)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var summary patterns.Summary
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&summary))
+	assert.Equal(t, 3, summary.Total)
+	require.Len(t, summary.Patterns, 2)
+	assert.Equal(t, "INFO user <*> logged in", summary.Patterns[0].Template)
+	assert.Equal(t, 2, summary.Patterns[0].Count)
+	assert.Equal(t, map[string]int{"info": 2}, summary.Patterns[0].Levels)
+	assert.Equal(t, map[string]int{"error": 1}, summary.Patterns[1].Levels)
+}
+
+func Test_handler_fetchLogPatterns_invalid_range(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/hosts/localhost/containers/123456/patterns?from=yesterday", nil)
+	require.NoError(t, err)
+
+	mockedClient := new(MockedClient)
+	mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+	mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{}, nil)
+	mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil)
+
+	handler := createDefaultHandler(mockedClient)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -9,13 +9,16 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-func (h *handler) updateProfile(w http.ResponseWriter, r *http.Request) {
-	username := profile.DefaultUsername
+// profileUsername returns the name the profile of the user is stored under.
+func profileUsername(r *http.Request) string {
 	if user := auth.UserFromContext(r.Context()); user != nil {
-		username = user.Username
+		return user.Username
 	}
+	return profile.DefaultUsername
+}
 
-	if err := profile.UpdateFromReader(username, r.Body); err != nil {
+func (h *handler) updateProfile(w http.ResponseWriter, r *http.Request) {
+	if err := profile.UpdateFromReader(profileUsername(r), r.Body); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		log.Error().Err(err).Msg("Failed to update profile")
 		return
@@ -0,0 +1,67 @@
+package web
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+)
+
+// liveReorderWindow is how long live events of containers using message
+// timestamps are held back so that lines arriving out of order from different
+// containers can be sorted before they are sent.
+const liveReorderWindow = time.Second
+
+type heldLogEvent struct {
+	event   *container.LogEvent
+	arrived time.Time
+}
+
+// reorderBuffer sorts live events by timestamp within a short arrival window.
+type reorderBuffer struct {
+	window time.Duration
+	held   []heldLogEvent
+}
+
+func newReorderBuffer(window time.Duration) *reorderBuffer {
+	return &reorderBuffer{window: window}
+}
+
+func (b *reorderBuffer) push(event *container.LogEvent, now time.Time) {
+	b.held = append(b.held, heldLogEvent{event: event, arrived: now})
+}
+
+// release returns, in timestamp order, every event held longer than the
+// window together with any newer arrival that sorts before one of them.
+func (b *reorderBuffer) release(now time.Time) []*container.LogEvent {
+	cutoff := now.Add(-b.window)
+	var latest int64
+	ready := false
+	for _, h := range b.held {
+		if !h.arrived.After(cutoff) {
+			ready = true
+			latest = max(latest, h.event.SortTimestamp())
+		}
+	}
+	if !ready {
+		return nil
+	}
+
+	var released []*container.LogEvent
+	kept := b.held[:0]
+	for _, h := range b.held {
+		if !h.arrived.After(cutoff) || h.event.SortTimestamp() <= latest {
+			released = append(released, h.event)
+		} else {
+			kept = append(kept, h)
+		}
+	}
+	clear(b.held[len(kept):])
+	b.held = kept
+
+	slices.SortStableFunc(released, func(a, b *container.LogEvent) int {
+		return cmp.Compare(a.SortTimestamp(), b.SortTimestamp())
+	})
+	return released
+}
@@ -0,0 +1,35 @@
+package web
+
+import (
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_reorderBuffer(t *testing.T) {
+	start := time.Now()
+	b := newReorderBuffer(time.Second)
+
+	// events are sorted by the message timestamp, not the driver's, when they have one
+	b.push(&container.LogEvent{Id: 1, Timestamp: 1000, MessageTimestamp: 300}, start)
+	b.push(&container.LogEvent{Id: 2, Timestamp: 1200, MessageTimestamp: 100}, start.Add(200*time.Millisecond))
+	b.push(&container.LogEvent{Id: 3, Timestamp: 500}, start.Add(400*time.Millisecond))
+
+	assert.Empty(t, b.release(start.Add(500*time.Millisecond)))
+
+	ids := func(events []*container.LogEvent) []uint32 {
+		var ids []uint32
+		for _, e := range events {
+			ids = append(ids, e.Id)
+		}
+		return ids
+	}
+
+	// Only event 1 has been held for the full window, but event 2 sorts before
+	// it and must not be sent after it.
+	assert.Equal(t, []uint32{2, 1}, ids(b.release(start.Add(time.Second))))
+	assert.Equal(t, []uint32{3}, ids(b.release(start.Add(2*time.Second))))
+	assert.Empty(t, b.release(start.Add(3*time.Second)))
+}
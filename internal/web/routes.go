@@ -7,14 +7,20 @@ import (
 
 	"net/http"
 	"strings"
+	"sync"
 
+	"github.com/amir20/dozzle/internal/archive"
 	"github.com/amir20/dozzle/internal/auth"
 	"github.com/amir20/dozzle/internal/cloud"
 	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/history"
 	dozzle_mcp "github.com/amir20/dozzle/internal/mcp"
 	"github.com/amir20/dozzle/internal/notification"
 	"github.com/amir20/dozzle/internal/notification/dispatcher"
+	"github.com/amir20/dozzle/internal/share"
 	container_support "github.com/amir20/dozzle/internal/support/container"
+	"github.com/amir20/dozzle/internal/timeline"
+	"github.com/amir20/dozzle/internal/views"
 	"github.com/amir20/dozzle/types"
 
 	"github.com/go-chi/chi/v5"
@@ -100,6 +106,10 @@ type HostService interface {
 	SubscribeAvailableHosts(ctx context.Context, hosts chan<- container.Host)
 	LocalClients() []container.Client
 	LocalClientServices() []container_support.ClientService
+	ListArchivedContainers(labels container.ContainerLabels) []container.Container
+	SearchArchive(ctx context.Context, query archive.Query) (archive.SearchResult, error)
+	StatsHistory(host string, id string, from time.Time, to time.Time, step time.Duration) ([]history.Sample, time.Duration, error)
+	ContainerTimeline(query timeline.Query) (timeline.Result, error)
 	// Notification methods
 	AddSubscription(sub *notification.Subscription) error
 	RemoveSubscription(id int)
@@ -110,7 +120,10 @@ type HostService interface {
 	UpdateDispatcher(id int, d dispatcher.Dispatcher)
 	RemoveDispatcher(id int)
 	Dispatchers() []notification.DispatcherConfig
+	SendTestNotification(ctx context.Context, tester dispatcher.Tester, n types.Notification) dispatcher.TestResult
 	FetchAgentNotificationStats() map[int]types.SubscriptionStats
+	NotificationDeliveries() []types.NotificationDelivery
+	FetchAgentNotificationDeliveries() []types.NotificationDelivery
 	CloudConfig() *notification.CloudConfig
 	SetCloudConfig(cc *notification.CloudConfig)
 	SetCloudStreamLogs(enabled bool)
@@ -122,6 +135,9 @@ type handler struct {
 	content     fs.FS
 	config      *Config
 	hostService HostService
+	views       *views.Shared
+	shares      *share.Store
+	shareSigner func() *auth.ShareSigner
 }
 
 func CreateServer(hostService HostService, content fs.FS, config Config) *http.Server {
@@ -129,6 +145,9 @@ func CreateServer(hostService HostService, content fs.FS, config Config) *http.S
 		content:     content,
 		config:      &config,
 		hostService: hostService,
+		views:       views.NewShared(views.DefaultSharedPath),
+		shares:      share.NewStore(share.DefaultPath),
+		shareSigner: sync.OnceValue(func() *auth.ShareSigner { return newShareSigner(config.Authorization) }),
 	}
 
 	return &http.Server{Addr: config.Addr, Handler: createRouter(handler)}
@@ -165,13 +184,34 @@ func createRouter(h *handler) *chi.Mux {
 				r.Get("/hosts/{host}/containers/{id}/logs/stream", h.streamContainerLogs)
 				r.Get("/hosts/{host}/logs/stream", h.streamHostLogs)
 				r.Get("/hosts/{host}/containers/{id}/logs", h.fetchLogsBetweenDates)
+				r.Get("/hosts/{host}/containers/{id}/patterns", h.fetchLogPatterns)
+				r.Get("/hosts/{host}/containers/{id}/stats", h.fetchStatsHistory)
+				r.Get("/hosts/{host}/containers/{id}/timeline", h.fetchContainerTimeline)
 				r.Get("/hosts/{host}/logs/mergedStream/{ids}", h.streamLogsMerged)
 				r.Get("/containers/{hostIds}/download", h.downloadLogs) // formatted as host:container,host:container
 				r.Get("/labels/{labels}/logs/stream", h.streamLogsWithLabels)
 				r.Get("/groups/{group}/logs/stream", h.streamGroupedLogs)
 				r.Get("/host-groups/{group}/logs/stream", h.streamHostGroupLogs)
+
+				// Log volume histograms, one per stream route
+				r.Get("/hosts/{host}/containers/{id}/logs/histogram", h.containerHistogram)
+				r.Get("/hosts/{host}/logs/histogram", h.hostHistogram)
+				r.Get("/hosts/{host}/logs/mergedHistogram/{ids}", h.mergedHistogram)
+				r.Get("/labels/{labels}/logs/histogram", h.labelsHistogram)
+				r.Get("/groups/{group}/logs/histogram", h.groupHistogram)
+				r.Get("/host-groups/{group}/logs/histogram", h.hostGroupHistogram)
 				r.Get("/events/stream", h.streamEvents)
 
+				// Removed containers with archived logs
+				r.Get("/archive/containers", h.archivedContainers)
+				r.Get("/archive/search", h.searchArchive)
+
+				// Lines of all containers carrying a trace or request ID
+				r.Get("/correlate", h.correlateLogs)
+
+				// Differences in message templates between two log ranges
+				r.Get("/diff", h.diffLogs)
+
 				// Action
 				if h.config.EnableActions {
 					r.Post("/hosts/{host}/containers/{id}/actions/update", h.containerUpdate)
@@ -186,6 +226,22 @@ func createRouter(h *handler) *chi.Mux {
 					r.Get("/profile/avatar", h.avatar)
 				}
 				r.Patch("/profile", h.updateProfile)
+
+				// Saved views, private to the user or shared
+				r.Route("/views", func(r chi.Router) {
+					r.Get("/", h.listViews)
+					r.Post("/", h.createView)
+					r.Get("/{id}", h.getView)
+					r.Put("/{id}", h.updateView)
+					r.Delete("/{id}", h.deleteView)
+				})
+
+				// Share links to a range of logs
+				r.Route("/shares", func(r chi.Router) {
+					r.Get("/", h.listShares)
+					r.Post("/", h.createShare)
+					r.Delete("/{id}", h.deleteShare)
+				})
 				r.Get("/version", h.version)
 				if log.Debug().Enabled() {
 					r.Get("/debug/store", h.debugStore)
@@ -200,11 +256,16 @@ func createRouter(h *handler) *chi.Mux {
 					r.Patch("/rules/{id}", h.updateNotificationRule)
 					r.Delete("/rules/{id}", h.deleteNotificationRule)
 
+					r.Get("/alerts", h.listFiringAlerts)
+					r.Get("/history", h.listNotificationDeliveries)
+
 					r.Get("/dispatchers", h.listDispatchers)
 					r.Post("/dispatchers", h.createDispatcher)
 					r.Get("/dispatchers/{id}", h.getDispatcher)
 					r.Put("/dispatchers/{id}", h.updateDispatcher)
 					r.Delete("/dispatchers/{id}", h.deleteDispatcher)
+					r.Post("/dispatchers/{id}/test", h.testDispatcherByID)
+					r.Post("/dispatchers/{id}/duplicate", h.duplicateDispatcher)
 
 					r.Post("/preview", h.previewExpression)
 					r.Post("/test-webhook", h.testWebhook)
@@ -234,6 +295,7 @@ func createRouter(h *handler) *chi.Mux {
 			})
 
 			// Public API routes
+			r.Get("/shared/{token}/logs", h.sharedLogs)
 			if h.config.Authorization.Provider == SIMPLE {
 				r.Post("/token", h.createToken)
 				r.Delete("/token", h.deleteToken)
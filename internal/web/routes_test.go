@@ -97,6 +97,10 @@ func (m *MockedClient) SystemInfo() system.Info {
 }
 
 func createHandler(client docker_support.DockerUpdateClient, content fs.FS, config Config) *chi.Mux {
+	return createRouter(newTestHandler(client, content, config))
+}
+
+func newTestHandler(client docker_support.DockerUpdateClient, content fs.FS, config Config) *handler {
 	if client == nil {
 		client = new(MockedClient)
 		client.(*MockedClient).On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{}, nil)
@@ -114,11 +118,11 @@ func createHandler(client docker_support.DockerUpdateClient, content fs.FS, conf
 
 	manager := docker_support.NewRetriableClientManager(nil, 3*time.Second, tls.Certificate{}, docker_support.NewDockerClientService(client, container.ContainerLabels{}))
 	multiHostService := docker_support.NewMultiHostService(manager, 3*time.Second)
-	return createRouter(&handler{
+	return &handler{
 		hostService: multiHostService,
 		content:     content,
 		config:      &config,
-	})
+	}
 }
 
 func createDefaultHandler(client docker_support.DockerUpdateClient) *chi.Mux {
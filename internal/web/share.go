@@ -0,0 +1,206 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/amir20/dozzle/internal/auth"
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/share"
+	support_web "github.com/amir20/dozzle/internal/support/web"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultShareExpiry = 24 * time.Hour
+	maxShareExpiry     = 30 * 24 * time.Hour
+)
+
+// shareSigner is implemented by authorizers that sign share links with a key
+// of their own.
+type shareSigner interface {
+	ShareSigner() *auth.ShareSigner
+}
+
+// newShareSigner returns the signer of the authorizer, or one with a random
+// secret stored next to the other data files. It returns nil when no secret
+// can be stored, which disables share links.
+func newShareSigner(authorization Authorization) *auth.ShareSigner {
+	if signer, ok := authorization.Authorizer.(shareSigner); ok {
+		return signer.ShareSigner()
+	}
+	secret, err := auth.LoadShareSecret(auth.DefaultShareSecretPath)
+	if err != nil {
+		log.Warn().Err(err).Msg("Could not load share link secret, share links are disabled")
+		return nil
+	}
+	return auth.NewShareSigner(secret)
+}
+
+type createShareRequest struct {
+	Host        string    `json:"host"`
+	ContainerID string    `json:"containerId"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Filter      string    `json:"filter"`
+	ExpiresIn   string    `json:"expiresIn"`
+}
+
+type shareResponse struct {
+	share.Link
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// createShare mints a share link to a range of logs of a container. Like
+// downloads, it requires the download role.
+func (h *handler) createShare(w http.ResponseWriter, r *http.Request) {
+	if h.config.Authorization.Provider != NONE && !auth.UserFromContext(r.Context()).Roles.Has(auth.Download) {
+		log.Warn().Msg("user is not permitted to share logs")
+		writeError(w, http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		return
+	}
+
+	signer := h.shareSigner()
+	if signer == nil {
+		writeError(w, http.StatusServiceUnavailable, "share links are unavailable")
+		return
+	}
+
+	var req createShareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Host == "" || req.ContainerID == "" {
+		writeError(w, http.StatusBadRequest, "host and containerId are required")
+		return
+	}
+	if req.From.IsZero() || req.To.IsZero() || !req.To.After(req.From) {
+		writeError(w, http.StatusBadRequest, "from must be before to")
+		return
+	}
+	if req.Filter != "" {
+		if _, err := support_web.ParseFilter(req.Filter); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	expiresIn := defaultShareExpiry
+	if req.ExpiresIn != "" {
+		d, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || d <= 0 || d > maxShareExpiry {
+			writeError(w, http.StatusBadRequest, "expiresIn must be a duration of up to 720h")
+			return
+		}
+		expiresIn = d
+	}
+
+	containerService, err := h.hostService.FindContainer(req.Host, req.ContainerID, h.resolveLabels(r))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	now := time.Now().UTC()
+	link := share.Link{
+		ID:            share.NewID(),
+		Host:          req.Host,
+		ContainerID:   req.ContainerID,
+		ContainerName: containerService.Container.Name,
+		From:          req.From.UTC(),
+		To:            req.To.UTC(),
+		Filter:        req.Filter,
+		CreatedBy:     profileUsername(r),
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(expiresIn),
+	}
+
+	token, err := signer.Sign(link.Claims())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to sign share link")
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := h.shares.Add(link); err != nil {
+		log.Error().Err(err).Msg("Failed to save share link")
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, shareResponse{
+		Link:  link,
+		Token: token,
+		URL:   strings.TrimSuffix(h.config.Base, "/") + "/api/shared/" + token + "/logs",
+	})
+}
+
+// listShares returns the links created by the user that have not expired.
+func (h *handler) listShares(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.shares.List(profileUsername(r)))
+}
+
+// deleteShare revokes a link created by the user.
+func (h *handler) deleteShare(w http.ResponseWriter, r *http.Request) {
+	if err := h.shares.Delete(chi.URLParam(r, "id"), profileUsername(r)); err != nil {
+		if errors.Is(err, share.ErrNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Error().Err(err).Msg("Failed to revoke share link")
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sharedLogs serves the logs a share link grants access to. It is public: the
+// signed token is the credential, and it only works while its link has not
+// been revoked.
+func (h *handler) sharedLogs(w http.ResponseWriter, r *http.Request) {
+	signer := h.shareSigner()
+	if signer == nil {
+		writeError(w, http.StatusNotFound, auth.ErrInvalidShareToken.Error())
+		return
+	}
+
+	claims, err := signer.Verify(chi.URLParam(r, "token"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, auth.ErrInvalidShareToken.Error())
+		return
+	}
+	link, err := h.shares.Get(claims.ID)
+	if err != nil || !link.Matches(claims) {
+		writeError(w, http.StatusNotFound, auth.ErrInvalidShareToken.Error())
+		return
+	}
+
+	// Everything about the slice comes from the link, nothing from the caller.
+	query := url.Values{
+		"from":   {link.From.Format(time.RFC3339Nano)},
+		"to":     {link.To.Format(time.RFC3339Nano)},
+		"stdout": {"1"},
+		"stderr": {"1"},
+	}
+	for level := range container.SupportedLogLevels {
+		query.Add("levels", level)
+	}
+	if link.Filter != "" {
+		query.Set("filter", link.Filter)
+	}
+
+	shared := r.Clone(r.Context())
+	shared.URL.RawQuery = query.Encode()
+	shared.Header.Del("Accept")
+	rctx := chi.RouteContext(shared.Context())
+	rctx.URLParams.Add("host", link.Host)
+	rctx.URLParams.Add("id", link.ContainerID)
+
+	h.fetchLogsBetweenDates(w, shared)
+}
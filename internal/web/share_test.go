@@ -0,0 +1,130 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/auth"
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/share"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createShareHandler(t *testing.T, authorization Authorization) *chi.Mux {
+	data := makeMessage("2020-05-13T18:55:37.772853839Z INFO connecting\n", container.STDOUT)
+	data = append(data, makeMessage("2020-05-13T18:55:38.772853839Z ERROR boom\n", container.STDOUT)...)
+	return createShareHandlerWithLogs(t, authorization, data)
+}
+
+func createShareHandlerWithLogs(t *testing.T, authorization Authorization, data []byte) *chi.Mux {
+	id := "123456"
+	mockedClient := new(MockedClient)
+	mockedClient.On("ContainerLogsBetweenDates", mock.Anything, id, mock.Anything, mock.Anything, container.STDOUT|container.STDERR).
+		Return(io.NopCloser(bytes.NewReader(data)), nil)
+	mockedClient.On("FindContainer", mock.Anything, id).Return(container.Container{ID: id, Name: "api"}, nil)
+	mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+	mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return([]container.Container{
+		{ID: id, Name: "api", Host: "localhost", State: "running"},
+	}, nil)
+	mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil)
+
+	h := newTestHandler(mockedClient, nil, Config{Base: "/", Authorization: authorization})
+	h.shares = share.NewStore(filepath.Join(t.TempDir(), "shares.yml"))
+	h.shareSigner = func() *auth.ShareSigner { return auth.NewShareSigner([]byte("secret")) }
+	return createRouter(h)
+}
+
+func Test_handler_shareLink(t *testing.T) {
+	handler := createShareHandler(t, Authorization{Provider: NONE})
+
+	rr := serveView(t, handler, "POST", "/api/shares", `{"host":"localhost","containerId":"123456","from":"2020-05-13T18:55:00Z","to":"2020-05-13T18:56:00Z","filter":"boom","expiresIn":"1h"}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created shareResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.Equal(t, "api", created.ContainerName)
+	assert.Equal(t, "/api/shared/"+created.Token+"/logs", created.URL)
+
+	rr = serveView(t, handler, "GET", created.URL+"?filter=connecting&from=2000-01-01T00:00:00Z", "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	require.Len(t, lines, 1, "the caller cannot widen the shared slice")
+	assert.Empty(t, rr.Header().Get(truncatedHeader))
+	assert.Contains(t, lines[0], "boom")
+
+	rr = serveView(t, handler, "GET", "/api/shares", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var links []share.Link
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &links))
+	require.Len(t, links, 1)
+	assert.Equal(t, created.ID, links[0].ID)
+
+	require.Equal(t, http.StatusNoContent, serveView(t, handler, "DELETE", "/api/shares/"+created.ID, "").Code)
+	assert.Equal(t, http.StatusNotFound, serveView(t, handler, "GET", created.URL, "").Code, "revoked links stop working")
+}
+
+func Test_handler_shareLink_truncated(t *testing.T) {
+	start := time.Date(2020, 5, 13, 18, 0, 0, 0, time.UTC)
+	var data []byte
+	for i := range 600 {
+		at := start.Add(time.Duration(i) * time.Second).Format(time.RFC3339Nano)
+		data = append(data, makeMessage(at+" ERROR boom\n", container.STDOUT)...)
+	}
+	handler := createShareHandlerWithLogs(t, Authorization{Provider: NONE}, data)
+
+	rr := serveView(t, handler, "POST", "/api/shares", `{"host":"localhost","containerId":"123456","from":"2020-05-13T18:00:00Z","to":"2020-05-13T19:00:00Z","expiresIn":"1h"}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created shareResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+
+	rr = serveView(t, handler, "GET", created.URL, "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "true", rr.Header().Get(truncatedHeader))
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	assert.Len(t, lines, 500)
+}
+
+func Test_handler_shareLink_invalid(t *testing.T) {
+	handler := createShareHandler(t, Authorization{Provider: NONE})
+
+	for _, body := range []string{
+		`not json`,
+		`{"containerId":"123456","from":"2020-05-13T18:55:00Z","to":"2020-05-13T18:56:00Z"}`,
+		`{"host":"localhost","containerId":"123456"}`,
+		`{"host":"localhost","containerId":"123456","from":"2020-05-13T18:56:00Z","to":"2020-05-13T18:55:00Z"}`,
+		`{"host":"localhost","containerId":"123456","from":"2020-05-13T18:55:00Z","to":"2020-05-13T18:56:00Z","filter":"(boom"}`,
+		`{"host":"localhost","containerId":"123456","from":"2020-05-13T18:55:00Z","to":"2020-05-13T18:56:00Z","expiresIn":"1000h"}`,
+	} {
+		rr := serveView(t, handler, "POST", "/api/shares", body)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+
+	assert.Equal(t, http.StatusNotFound, serveView(t, handler, "GET", "/api/shared/not-a-token/logs", "").Code)
+}
+
+func Test_handler_shareLink_requiresDownloadRole(t *testing.T) {
+	handler := createShareHandler(t, Authorization{
+		Provider:   FORWARD_PROXY,
+		Authorizer: auth.NewForwardProxyAuth("Remote-User", "Remote-Email", "Remote-Name", "Remote-Filter", "Remote-Roles"),
+	})
+
+	body := `{"host":"localhost","containerId":"123456","from":"2020-05-13T18:55:00Z","to":"2020-05-13T18:56:00Z"}`
+	for roles, code := range map[string]int{"shell": http.StatusForbidden, "download": http.StatusCreated} {
+		req, err := http.NewRequest("POST", "/api/shares", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Remote-User", "alice")
+		req.Header.Set("Remote-Roles", roles)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, code, rr.Code, roles)
+	}
+}
@@ -0,0 +1,66 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/amir20/dozzle/internal/history"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type statsHistory struct {
+	From    time.Time        `json:"from"`
+	To      time.Time        `json:"to"`
+	Step    int64            `json:"step"` // milliseconds, 0 for raw samples
+	Samples []history.Sample `json:"samples"`
+	Notice  string           `json:"notice,omitempty"` // why no samples were recorded
+}
+
+// fetchStatsHistory returns the recorded stats of a container between from and
+// to, the last hour by default, averaged per step.
+func (h *handler) fetchStatsHistory(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseTimeRange(r, time.Hour)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var step time.Duration
+	if value := r.URL.Query().Get("step"); value != "" {
+		step, err = time.ParseDuration(value)
+		if err != nil || step <= 0 || to.Sub(from)/step > history.MaxPoints {
+			writeError(w, http.StatusBadRequest, "invalid step")
+			return
+		}
+	}
+
+	containerService, err := h.hostService.FindContainer(hostKey(r), chi.URLParam(r, "id"), h.resolveLabels(r))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	c := containerService.Container
+	samples, step, err := h.hostService.StatsHistory(c.Host, c.ID, from, to, step)
+	switch {
+	case errors.Is(err, history.ErrDisabled):
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, history.ErrNotRecorded):
+		writeJSON(w, http.StatusOK, statsHistory{From: from, To: to, Samples: []history.Sample{}, Notice: err.Error()})
+		return
+	case err != nil:
+		log.Error().Err(err).Msg("error reading stats history")
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, statsHistory{
+		From:    from,
+		To:      to,
+		Step:    step.Milliseconds(),
+		Samples: samples,
+	})
+}
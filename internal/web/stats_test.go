@@ -0,0 +1,56 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amir20/dozzle/internal/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_handler_fetchStatsHistory_invalid(t *testing.T) {
+	handler := createDefaultHandler(nil)
+
+	for _, query := range []string{"step=abc", "step=-1m", "step=1s&from=2024-01-01T00:00:00Z&to=2024-01-02T00:00:00Z", "from=2024-01-02T00:00:00Z&to=2024-01-01T00:00:00Z"} {
+		req, err := http.NewRequest("GET", "/api/hosts/localhost/containers/123456/stats?"+query, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+// agentHostService reports every container as running on an agent host, whose
+// stats are not recorded.
+type agentHostService struct {
+	HostService
+}
+
+func (s agentHostService) StatsHistory(string, string, time.Time, time.Time, time.Duration) ([]history.Sample, time.Duration, error) {
+	return nil, 0, history.ErrNotRecorded
+}
+
+func newAgentTestHandler() http.Handler {
+	h := newTestHandler(mockedClient(), nil, Config{Base: "/", Authorization: Authorization{Provider: NONE}})
+	h.hostService = agentHostService{HostService: h.hostService}
+	return createRouter(h)
+}
+
+func Test_handler_fetchStatsHistory_agent(t *testing.T) {
+	handler := newAgentTestHandler()
+
+	req, err := http.NewRequest("GET", "/api/hosts/localhost/containers/123/stats", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var result statsHistory
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&result))
+	assert.Empty(t, result.Samples)
+	assert.Equal(t, history.ErrNotRecorded.Error(), result.Notice)
+}
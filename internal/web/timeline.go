@@ -0,0 +1,80 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amir20/dozzle/internal/timeline"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type containerTimeline struct {
+	timeline.Result
+	Notice string `json:"notice,omitempty"` // why no entries were recorded
+}
+
+// fetchContainerTimeline returns the recorded lifecycle events of a container
+// and of the earlier containers with the same name, the last 24 hours by
+// default. name filters the events, e.g. name=die,oom.
+func (h *handler) fetchContainerTimeline(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseTimeRange(r, 24*time.Hour)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := timeline.DefaultLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	var names []string
+	for _, value := range r.URL.Query()["name"] {
+		for name := range strings.SplitSeq(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	containerService, err := h.hostService.FindContainer(hostKey(r), chi.URLParam(r, "id"), h.resolveLabels(r))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	c := containerService.Container
+	result, err := h.hostService.ContainerTimeline(timeline.Query{
+		Host:          c.Host,
+		ContainerName: c.Name,
+		From:          from,
+		To:            to,
+		Names:         names,
+		Limit:         limit,
+	})
+	switch {
+	case errors.Is(err, timeline.ErrDisabled):
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, timeline.ErrNotRecorded):
+		writeJSON(w, http.StatusOK, containerTimeline{
+			Result: timeline.Result{Entries: []timeline.Entry{}, Counts: map[string]int{}},
+			Notice: err.Error(),
+		})
+		return
+	case err != nil:
+		log.Error().Err(err).Msg("error reading container timeline")
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, containerTimeline{Result: result})
+}
@@ -0,0 +1,44 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amir20/dozzle/internal/timeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_handler_fetchContainerTimeline_invalid(t *testing.T) {
+	handler := createDefaultHandler(nil)
+
+	for _, query := range []string{"limit=0", "limit=abc", "from=2024-01-02T00:00:00Z&to=2024-01-01T00:00:00Z"} {
+		req, err := http.NewRequest("GET", "/api/hosts/localhost/containers/123456/timeline?"+query, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+// ContainerTimeline reports the events of the agent host as not recorded.
+func (s agentHostService) ContainerTimeline(timeline.Query) (timeline.Result, error) {
+	return timeline.Result{}, timeline.ErrNotRecorded
+}
+
+func Test_handler_fetchContainerTimeline_agent(t *testing.T) {
+	handler := newAgentTestHandler()
+
+	req, err := http.NewRequest("GET", "/api/hosts/localhost/containers/123/timeline", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var result containerTimeline
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&result))
+	assert.Empty(t, result.Entries)
+	assert.Equal(t, timeline.ErrNotRecorded.Error(), result.Notice)
+}
@@ -0,0 +1,255 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/amir20/dozzle/internal/profile"
+	"github.com/amir20/dozzle/internal/views"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// viewScope limits views to the containers a user can see. Users without
+// label restrictions see every container.
+type viewScope struct {
+	restricted bool
+	hosts      map[string][]string // container name to the hosts it runs on
+}
+
+func (h *handler) viewScope(r *http.Request) viewScope {
+	labels := h.resolveLabels(r)
+	if !labels.Exists() {
+		return viewScope{}
+	}
+
+	containers, errs := h.hostService.ListAllContainers(labels)
+	if len(errs) > 0 {
+		log.Warn().Err(errs[0]).Msg("error while listing containers")
+	}
+
+	scope := viewScope{restricted: true, hosts: make(map[string][]string)}
+	for _, c := range containers {
+		scope.hosts[c.Name] = append(scope.hosts[c.Name], c.Host)
+	}
+	return scope
+}
+
+func (s viewScope) canSee(view views.View, name string) bool {
+	if !s.restricted {
+		return true
+	}
+	hosts, ok := s.hosts[name]
+	return ok && (view.Host == "" || slices.Contains(hosts, view.Host))
+}
+
+func (s viewScope) seesAll(view views.View) bool {
+	for _, name := range view.Containers {
+		if !s.canSee(view, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// redact drops the containers of view the user cannot see. It returns false
+// when none are left of a view that had containers, so the view is hidden.
+func (s viewScope) redact(view *views.View) bool {
+	if !s.restricted || len(view.Containers) == 0 {
+		return true
+	}
+	visible := make([]string, 0, len(view.Containers))
+	for _, name := range view.Containers {
+		if s.canSee(*view, name) {
+			visible = append(visible, name)
+		}
+	}
+	view.Containers = visible
+	return len(visible) > 0
+}
+
+// listViews returns the private views of the user followed by the shared
+// views.
+func (h *handler) listViews(w http.ResponseWriter, r *http.Request) {
+	result := make([]views.View, 0)
+	if existing, err := profile.Load(profileUsername(r)); err == nil {
+		result = append(result, existing.Views...)
+	}
+
+	scope := h.viewScope(r)
+	for _, view := range h.views.List() {
+		if scope.redact(&view) {
+			result = append(result, view)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *handler) getView(w http.ResponseWriter, r *http.Request) {
+	view, err := h.findView(r, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	h.viewScope(r).redact(&view)
+	writeJSON(w, http.StatusOK, view)
+}
+
+// findView looks up a private view of the user, then a shared view the user
+// can see.
+func (h *handler) findView(r *http.Request, id string) (views.View, error) {
+	if existing, err := profile.Load(profileUsername(r)); err == nil {
+		for _, view := range existing.Views {
+			if view.ID == id {
+				return view, nil
+			}
+		}
+	}
+
+	view, err := h.views.Get(id)
+	if err != nil {
+		return views.View{}, err
+	}
+	if visible := view; !h.viewScope(r).redact(&visible) {
+		return views.View{}, views.ErrNotFound
+	}
+	return view, nil
+}
+
+// decodeView reads and validates a view from the body. Every container of
+// the view must be visible to the user.
+func (h *handler) decodeView(w http.ResponseWriter, r *http.Request) (views.View, bool) {
+	var view views.View
+	if err := json.NewDecoder(r.Body).Decode(&view); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return views.View{}, false
+	}
+	if err := view.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return views.View{}, false
+	}
+	if !h.viewScope(r).seesAll(view) {
+		writeError(w, http.StatusBadRequest, "view has unknown containers")
+		return views.View{}, false
+	}
+	return view, true
+}
+
+func (h *handler) createView(w http.ResponseWriter, r *http.Request) {
+	view, ok := h.decodeView(w, r)
+	if !ok {
+		return
+	}
+
+	view.ID = views.NewID()
+	view.UpdatedAt = time.Now().UTC()
+
+	var err error
+	if view.Shared {
+		view.Owner = profileUsername(r)
+		err = h.views.Put(view)
+	} else {
+		view.Owner = ""
+		err = profile.Update(profileUsername(r), func(p *profile.Profile) error {
+			p.Views = append(p.Views, view)
+			return nil
+		})
+	}
+	if err != nil {
+		writeViewError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, view)
+}
+
+// updateView replaces a view. A view stays private or shared, and a shared
+// view can only be changed by users who can see all of its containers.
+func (h *handler) updateView(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.editableView(w, r)
+	if !ok {
+		return
+	}
+	view, ok := h.decodeView(w, r)
+	if !ok {
+		return
+	}
+
+	view.ID = existing.ID
+	view.Shared = existing.Shared
+	view.Owner = existing.Owner
+	view.UpdatedAt = time.Now().UTC()
+
+	var err error
+	if view.Shared {
+		err = h.views.Put(view)
+	} else {
+		err = profile.Update(profileUsername(r), func(p *profile.Profile) error {
+			i := slices.IndexFunc(p.Views, func(v views.View) bool { return v.ID == view.ID })
+			if i < 0 {
+				return views.ErrNotFound
+			}
+			p.Views[i] = view
+			return nil
+		})
+	}
+	if err != nil {
+		writeViewError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, view)
+}
+
+func (h *handler) deleteView(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.editableView(w, r)
+	if !ok {
+		return
+	}
+
+	var err error
+	if existing.Shared {
+		err = h.views.Delete(existing.ID)
+	} else {
+		err = profile.Update(profileUsername(r), func(p *profile.Profile) error {
+			i := slices.IndexFunc(p.Views, func(v views.View) bool { return v.ID == existing.ID })
+			if i < 0 {
+				return views.ErrNotFound
+			}
+			p.Views = slices.Delete(p.Views, i, i+1)
+			return nil
+		})
+	}
+	if err != nil {
+		writeViewError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) editableView(w http.ResponseWriter, r *http.Request) (views.View, bool) {
+	existing, err := h.findView(r, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return views.View{}, false
+	}
+	if existing.Shared && !h.viewScope(r).seesAll(existing) {
+		writeError(w, http.StatusForbidden, "view has containers you cannot see")
+		return views.View{}, false
+	}
+	return existing, true
+}
+
+func writeViewError(w http.ResponseWriter, err error) {
+	if errors.Is(err, views.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	log.Error().Err(err).Msg("Failed to save view")
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
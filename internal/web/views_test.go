@@ -0,0 +1,121 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/amir20/dozzle/internal/container"
+	"github.com/amir20/dozzle/internal/views"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createViewsHandler(path string, labels container.ContainerLabels, visible ...container.Container) *chi.Mux {
+	mockedClient := new(MockedClient)
+	mockedClient.On("ListContainers", mock.Anything, mock.Anything).Return(visible, nil)
+	mockedClient.On("Host").Return(container.Host{ID: "localhost"})
+	mockedClient.On("ContainerEvents", mock.Anything, mock.AnythingOfType("chan<- container.ContainerEvent")).Return(nil)
+	for _, c := range visible {
+		mockedClient.On("FindContainer", mock.Anything, c.ID).Return(c, nil)
+	}
+
+	h := newTestHandler(mockedClient, nil, Config{Base: "/", Authorization: Authorization{Provider: NONE}, Labels: labels})
+	h.views = views.NewShared(path)
+	return createRouter(h)
+}
+
+func serveView(t *testing.T, handler http.Handler, method string, path string, body string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, path, strings.NewReader(body))
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func Test_handler_sharedViews(t *testing.T) {
+	handler := createViewsHandler(filepath.Join(t.TempDir(), "views.yml"), nil)
+
+	rr := serveView(t, handler, "POST", "/api/views", `{"name":"API errors","shared":true,"containers":["api"],"query":"?level:error","stdout":true,"stderr":true,"since":"1h"}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created views.View
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.ID)
+	assert.True(t, created.Shared)
+	assert.Equal(t, "__default__", created.Owner)
+
+	rr = serveView(t, handler, "PUT", "/api/views/"+created.ID, `{"name":"API warnings","containers":["api"],"query":"?level:warn","stdout":true}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = serveView(t, handler, "GET", "/api/views/"+created.ID, "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var updated views.View
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &updated))
+	assert.Equal(t, "API warnings", updated.Name)
+	assert.True(t, updated.Shared, "a shared view stays shared")
+
+	rr = serveView(t, handler, "DELETE", "/api/views/"+created.ID, "")
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = serveView(t, handler, "GET", "/api/views/"+created.ID, "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func Test_handler_sharedViews_respectLabels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "views.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`views:
+  - id: mixed
+    name: mixed
+    containers: [api, secret]
+    stdout: true
+  - id: hidden
+    name: hidden
+    containers: [secret]
+    stdout: true
+  - id: all
+    name: all
+    stdout: true
+`), 0644))
+
+	labels := container.ContainerLabels{"team": {"billing"}}
+	handler := createViewsHandler(path, labels, container.Container{ID: "1", Name: "api", Host: "localhost"})
+
+	rr := serveView(t, handler, "GET", "/api/views", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var list []views.View
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	require.Len(t, list, 2)
+	assert.Equal(t, "mixed", list[0].ID)
+	assert.Equal(t, []string{"api"}, list[0].Containers)
+	assert.Equal(t, "all", list[1].ID)
+
+	assert.Equal(t, http.StatusNotFound, serveView(t, handler, "GET", "/api/views/hidden", "").Code)
+	assert.Equal(t, http.StatusNotFound, serveView(t, handler, "DELETE", "/api/views/hidden", "").Code)
+	assert.Equal(t, http.StatusForbidden, serveView(t, handler, "DELETE", "/api/views/mixed", "").Code)
+
+	rr = serveView(t, handler, "POST", "/api/views", `{"name":"new","shared":true,"containers":["secret"],"stdout":true}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code, "containers the user cannot see are rejected")
+}
+
+func Test_handler_createView_invalid(t *testing.T) {
+	handler := createViewsHandler(filepath.Join(t.TempDir(), "views.yml"), nil)
+
+	for _, body := range []string{
+		`not json`,
+		`{"name":"","stdout":true}`,
+		`{"name":"no streams"}`,
+		`{"name":"bad level","stdout":true,"levels":["loud"]}`,
+		`{"name":"bad query","stdout":true,"query":"?(level:error"}`,
+		`{"name":"bad since","stdout":true,"since":"soon"}`,
+		`{"name":"open range","stdout":true,"from":"2024-01-01T00:00:00Z"}`,
+	} {
+		rr := serveView(t, handler, "POST", "/api/views", body)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+}
@@ -60,6 +60,13 @@ func main() {
 
 	log.Info().Msgf("Dozzle version %s", args.Version())
 	dispatcher.UserAgent = fmt.Sprintf("Dozzle/%s", args.Version())
+	container.LoadParserConfigFile(container.DefaultParserConfigPath)
+	container.LoadRedactionConfigFile(container.DefaultRedactionConfigPath)
+	container.SetDefaultLogRateLimit(container.LogRateLimit{
+		Rate:   args.StreamRateLimit,
+		Burst:  args.StreamRateBurst,
+		Sample: args.StreamSampleRate,
+	})
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -76,6 +83,15 @@ func main() {
 		if err := multiHostService.StartNotificationManager(ctx); err != nil {
 			log.Fatal().Err(err).Msg("Could not start notification manager")
 		}
+		if err := multiHostService.StartArchive(ctx); err != nil {
+			log.Fatal().Err(err).Msg("Could not start log archive")
+		}
+		if err := multiHostService.StartStatsHistory(ctx); err != nil {
+			log.Fatal().Err(err).Msg("Could not start stats history")
+		}
+		if err := multiHostService.StartTimeline(ctx); err != nil {
+			log.Fatal().Err(err).Msg("Could not start container timeline")
+		}
 		hostService = multiHostService
 		notificationService = multiHostService
 	} else if args.Mode == "swarm" {
@@ -93,6 +109,15 @@ func main() {
 		if err := multiHostService.StartNotificationManager(ctx); err != nil {
 			log.Fatal().Err(err).Msg("Could not start notification manager")
 		}
+		if err := multiHostService.StartArchive(ctx); err != nil {
+			log.Fatal().Err(err).Msg("Could not start log archive")
+		}
+		if err := multiHostService.StartStatsHistory(ctx); err != nil {
+			log.Fatal().Err(err).Msg("Could not start stats history")
+		}
+		if err := multiHostService.StartTimeline(ctx); err != nil {
+			log.Fatal().Err(err).Msg("Could not start container timeline")
+		}
 		hostService = multiHostService
 		notificationService = multiHostService
 		log.Info().Msg("Starting in swarm mode")
@@ -127,6 +152,15 @@ func main() {
 		if err := clusterService.StartNotificationManager(ctx); err != nil {
 			log.Fatal().Err(err).Msg("Could not start notification manager")
 		}
+		if err := clusterService.StartArchive(ctx); err != nil {
+			log.Fatal().Err(err).Msg("Could not start log archive")
+		}
+		if err := clusterService.StartStatsHistory(ctx); err != nil {
+			log.Fatal().Err(err).Msg("Could not start stats history")
+		}
+		if err := clusterService.StartTimeline(ctx); err != nil {
+			log.Fatal().Err(err).Msg("Could not start container timeline")
+		}
 
 		go cli.StartEvent(args, "k8s", localClient, "")
 		hostService = clusterService
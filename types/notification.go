@@ -11,19 +11,37 @@ const (
 	EventNotification  NotificationType = "event"
 )
 
+// NotificationStatus is the state of a metric or event alert a notification reports
+type NotificationStatus string
+
+const (
+	StatusFiring   NotificationStatus = "firing"
+	StatusResolved NotificationStatus = "resolved"
+)
+
 // Notification represents a notification event that can be filtered and sent
 type Notification struct {
 	ID           string                `json:"id"`
 	Type         NotificationType      `json:"type"`
+	Status       NotificationStatus    `json:"status,omitempty"` // empty for log alerts, which have no state
 	Detail       string                `json:"detail"`
 	Container    NotificationContainer `json:"container"`
 	Log          *NotificationLog      `json:"log,omitempty"`
 	Stat         *NotificationStat     `json:"stat,omitempty"`
 	Event        *NotificationEvent    `json:"event,omitempty"`
+	Digest       *NotificationDigest   `json:"digest,omitempty"`
 	Subscription SubscriptionConfig    `json:"subscription"`
 	Timestamp    time.Time             `json:"timestamp"`
 }
 
+// NotificationDigest summarizes the log lines batched into a digest notification.
+// Log holds the first of them.
+type NotificationDigest struct {
+	Count   int       `json:"count"`
+	Samples []string  `json:"samples"`
+	Since   time.Time `json:"since"`
+}
+
 // NotificationContainer represents a simplified container structure for notifications
 type NotificationContainer struct {
 	ID       string            `json:"id" expr:"id"`
@@ -88,14 +106,49 @@ type SubscriptionConfig struct {
 	EventExpression     string `json:"eventExpression,omitempty"`
 	Cooldown            int    `json:"cooldown,omitempty"`
 	SampleWindow        int    `json:"sampleWindow,omitempty"`
+	GroupBy             string `json:"groupBy,omitempty"`
+	DigestWindow        int    `json:"digestWindow,omitempty"`
 }
 
 // SubscriptionStats represents runtime stats for a notification subscription
 type SubscriptionStats struct {
-	SubscriptionID        int        `json:"subscriptionId"`
-	TriggerCount          int64      `json:"triggerCount"`
-	LastTriggeredAt       *time.Time `json:"lastTriggeredAt,omitempty"`
-	TriggeredContainerIDs []string   `json:"triggeredContainerIds"`
+	SubscriptionID        int           `json:"subscriptionId"`
+	TriggerCount          int64         `json:"triggerCount"`
+	LastTriggeredAt       *time.Time    `json:"lastTriggeredAt,omitempty"`
+	TriggeredContainerIDs []string      `json:"triggeredContainerIds"`
+	FiringAlerts          []FiringAlert `json:"firingAlerts,omitempty"`
+}
+
+// FiringAlert is a metric or event alert of a container that fired and hasn't resolved yet
+type FiringAlert struct {
+	SubscriptionID   int                   `json:"subscriptionId"`
+	SubscriptionName string                `json:"subscriptionName"`
+	Type             NotificationType      `json:"type"`
+	Container        NotificationContainer `json:"container"`
+	Detail           string                `json:"detail"`
+	Since            time.Time             `json:"since"`
+}
+
+// NotificationDelivery is one attempt to send a notification to a dispatcher
+type NotificationDelivery struct {
+	NotificationID   string             `json:"notificationId"`
+	Time             time.Time          `json:"time"`
+	Type             NotificationType   `json:"type"`
+	Status           NotificationStatus `json:"status,omitempty"`
+	SubscriptionID   int                `json:"subscriptionId"`
+	SubscriptionName string             `json:"subscriptionName"`
+	DispatcherID     int                `json:"dispatcherId"`
+	DispatcherName   string             `json:"dispatcherName"`
+	DispatcherType   string             `json:"dispatcherType"`
+	ContainerID      string             `json:"containerId"`
+	ContainerName    string             `json:"containerName"`
+	HostID           string             `json:"hostId"`
+	HostName         string             `json:"hostName"`
+	Summary          string             `json:"summary"`
+	Success          bool               `json:"success"`
+	StatusCode       int                `json:"statusCode,omitempty"`
+	LatencyMs        int64              `json:"latencyMs"`
+	Error            string             `json:"error,omitempty"`
 }
 
 // CloudConfig holds the cloud API key and metadata for broadcasting to agents.
@@ -113,4 +166,20 @@ type DispatcherConfig struct {
 	URL      string
 	Template string
 	Headers  map[string]string
+
+	Channel   string
+	Topic     string
+	Priority  string
+	Token     string
+	User      string
+	DozzleURL string
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPSecurity string
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+	To           []string
+	Subject      string
 }